@@ -0,0 +1,166 @@
+package main
+
+// achievementHook identifies the moments when achievement rules are
+// evaluated. Rules may be evaluated at several of them.
+type achievementHook int
+
+const (
+	CounterHook  achievementHook = 1 << iota // after a stats counter changed
+	LevelEndHook                             // when leaving a level
+	GameEndHook                              // when the player dies or escapes
+)
+
+// achievementRule declares when an achievement is obtained.
+type achievementRule struct {
+	Ach   achievement
+	Hooks achievementHook
+	Cond  func(g *game) bool
+}
+
+// counterRule returns a rule for an achievement obtained when a stats
+// counter reaches a threshold.
+func counterRule(ach achievement, threshold int, counter func(st *stats) int) achievementRule {
+	return achievementRule{Ach: ach, Hooks: CounterHook, Cond: func(g *game) bool {
+		return counter(&g.Stats) >= threshold
+	}}
+}
+
+// tieredRules returns the counter rules of Novice, Initiate and Master
+// achievements.
+func tieredRules(achs [3]achievement, thresholds [3]int, counter func(st *stats) int) []achievementRule {
+	rules := []achievementRule{}
+	for i, ach := range achs {
+		rules = append(rules, counterRule(ach, thresholds[i], counter))
+	}
+	return rules
+}
+
+// windowRule returns a rule for an achievement obtained when leaving a level
+// at depth minDepth or deeper, if that level and the window-1 previous ones
+// all satisfy a condition.
+func windowRule(ach achievement, minDepth, window int, level func(st *stats, depth int) bool) achievementRule {
+	return achievementRule{Ach: ach, Hooks: LevelEndHook, Cond: func(g *game) bool {
+		if g.Depth < minDepth || g.Depth > MaxDepth {
+			return false
+		}
+		for depth := g.Depth; depth > g.Depth-window; depth-- {
+			if depth < 1 || !level(&g.Stats, depth) {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// stealthRule returns a rule for a stealth achievement obtained when leaving
+// a level at depth minDepth or deeper, if few distinct monsters spotted the
+// player on the recent levels of the window, and the player was seldom
+// spotted on the older ones.
+func stealthRule(ach achievement, minDepth, window, recent int) achievementRule {
+	return achievementRule{Ach: ach, Hooks: LevelEndHook, Cond: func(g *game) bool {
+		if g.Depth < minDepth || g.Depth > MaxDepth {
+			return false
+		}
+		for i := 0; i < window; i++ {
+			spotted := g.Stats.DSpotted
+			if i < recent {
+				spotted = g.Stats.DUSpotted
+			}
+			if g.Depth-i < 1 || spotted[g.Depth-i] >= 3 {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// withHooks returns the rule evaluated at other hooks.
+func withHooks(rule achievementRule, hooks achievementHook) achievementRule {
+	rule.Hooks = hooks
+	return rule
+}
+
+func noRest(st *stats, depth int) bool {
+	return st.DRests[depth] == 0
+}
+
+func noMagara(st *stats, depth int) bool {
+	return st.DMagaraUses[depth] == 0
+}
+
+func explored(st *stats, depth int) bool {
+	return st.DExplPerc[depth] > 93
+}
+
+func joinRules(groups ...[]achievementRule) []achievementRule {
+	rules := []achievementRule{}
+	for _, group := range groups {
+		rules = append(rules, group...)
+	}
+	return rules
+}
+
+// achievementRules lists the achievements obtained from statistics, in the
+// order they are evaluated. Achievements obtained on special events, like
+// rescuing Shaedra, are not listed.
+var achievementRules = joinRules(
+	tieredRules([3]achievement{AchHarmonistNovice, AchHarmonistInitiate, AchHarmonistMaster},
+		[3]int{6, 11, 16}, func(st *stats) int { return st.HarmonicMagUse }),
+	tieredRules([3]achievement{AchNoviceOricCelmist, AchInitiateOricCelmist, AchMasterOricCelmist},
+		[3]int{6, 11, 16}, func(st *stats) int { return st.OricMagUse }),
+	tieredRules([3]achievement{AchPyromancerNovice, AchPyromancerInitiate, AchPyromancerMaster},
+		[3]int{2, 4, 6}, func(st *stats) int { return st.FireUse }),
+	tieredRules([3]achievement{AchDestructorNovice, AchDestructorInitiate, AchDestructorMaster},
+		[3]int{20, 40, 60}, func(st *stats) int { return st.DestructionUse }),
+	[]achievementRule{
+		counterRule(AchTeleport, 14, func(st *stats) int { return st.OricTelUse }),
+		counterRule(AchAcrobat, 15, func(st *stats) int { return st.Jumps + st.WallJumps }),
+		counterRule(AchTree, 12, func(st *stats) int { return st.ClimbedTree }),
+		counterRule(AchTable, 12, func(st *stats) int { return st.TableHides }),
+		counterRule(AchHole, 12, func(st *stats) int { return st.HoledWallsCrawled }),
+		counterRule(AchDoors, 100, func(st *stats) int { return st.DoorsOpened }),
+		counterRule(AchBarrels, 20, func(st *stats) int { return st.BarrelHides }),
+		counterRule(AchExtinguisher, 15, func(st *stats) int { return st.Extinguishments }),
+		counterRule(AchSleepy, 10, func(st *stats) int { return st.Rest }),
+		counterRule(AchLoreStudent, 4, func(st *stats) int { return len(st.Lore) }),
+		{Ach: AchLoremaster, Hooks: CounterHook, Cond: func(g *game) bool {
+			return len(g.Params.Lore) > 0 && len(g.Stats.Lore) >= len(g.Params.Lore)
+		}},
+		{Ach: AchUnstealthy, Hooks: CounterHook, Cond: func(g *game) bool {
+			return g.Depth >= 1 && g.Depth <= MaxDepth && len(g.Monsters) > 0 &&
+				g.Stats.DUSpotted[g.Depth] >= 90*len(g.Monsters)/100
+		}},
+		stealthRule(AchStealthNovice, 1, 1, 1),
+		stealthRule(AchStealthInitiate, 5, 3, 1),
+		stealthRule(AchStealthMaster, 8, 4, 2),
+		windowRule(AchInsomniaNovice, 3, 2, noRest),
+		windowRule(AchInsomniaInitiate, 5, 4, noRest),
+		windowRule(AchInsomniaMaster, 8, 6, noRest),
+		windowRule(AchAntimagicNovice, 3, 2, noMagara),
+		windowRule(AchAntimagicInitiate, 5, 4, noMagara),
+		windowRule(AchAntimagicMaster, 8, 6, noMagara),
+	},
+	[]achievementRule{
+		// explored levels also count when dying on them
+		withHooks(windowRule(AchNoviceExplorer, 1, 1, explored), LevelEndHook|GameEndHook),
+		withHooks(windowRule(AchInitiateExplorer, 5, 3, explored), LevelEndHook|GameEndHook),
+		withHooks(windowRule(AchMasterExplorer, 8, 5, explored), LevelEndHook|GameEndHook),
+		// last, so that any other achievement obtained on death counts
+		{Ach: NoAchievement, Hooks: GameEndHook, Cond: func(g *game) bool {
+			return g.Player.HP <= 0 && len(g.Stats.Achievements) == 0
+		}},
+	},
+)
+
+// CheckAchievements gives the player the achievements whose rules are
+// satisfied, among the ones evaluated at the given hook.
+func (g *game) CheckAchievements(hook achievementHook) {
+	for _, rule := range achievementRules {
+		if rule.Hooks&hook == 0 || g.Stats.Achievements[rule.Ach] != 0 {
+			continue
+		}
+		if rule.Cond(g) {
+			rule.Ach.Get(g)
+		}
+	}
+}
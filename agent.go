@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// agent is an external program playing the game: it reads observations as
+// JSON lines and answers each of them with an action, also as a JSON line.
+// If bot is not nil, the actions come from the built-in bot instead.
+type agent struct {
+	in       *bufio.Scanner
+	enc      *json.Encoder
+	logIndex int
+	bot      *bot
+}
+
+// NewAgentUI returns an interface for a game played by an agent that sends
+// actions on r and reads observations on w. Nothing is drawn, whatever the
+// backend.
+func NewAgentUI(g *game, r io.Reader, w io.Writer) *gameui {
+	ui := NewGameUI(g)
+	ui.DisableAnimations = true
+	ui.menuHover = -1
+	ui.agent = newAgent(r, w)
+	return ui
+}
+
+// agentInput is the input of backends in an agent game. There is no
+// keyboard: prompts are cancelled and autoexploration goes on.
+var agentInput = uiInput{key: "\x1b", interrupt: true}
+
+// agentAction is an action sent by an agent, for example:
+//
+//	{"Action": "Explore"}
+//	{"Action": "Evoke", "Magara": 1}
+//	{"Action": "Travel", "Target": {"X": 12, "Y": 7}}
+//
+// Magara is the slot of the magara to evoke, or to replace when interacting
+// with a magara on the ground. Target is the destination of a travel.
+type agentAction struct {
+	Action string
+	Magara int
+	Target *position
+}
+
+// agentActions maps the names of the actions available to agents to their
+// keyboard counterparts.
+var agentActions = map[string]action{
+	"W":          ActionW,
+	"S":          ActionS,
+	"N":          ActionN,
+	"E":          ActionE,
+	"RunW":       ActionRunW,
+	"RunS":       ActionRunS,
+	"RunN":       ActionRunN,
+	"RunE":       ActionRunE,
+	"WaitTurn":   ActionWaitTurn,
+	"GoToStairs": ActionGoToStairs,
+	"Explore":    ActionExplore,
+	"Evoke":      ActionEvoke,
+	"Interact":   ActionInteract,
+	"Travel":     ActionTravel,
+	"GoToBarrel": ActionGoToBarrel,
+	"GoToStone":  ActionGoToStone,
+	"GoToMagara": ActionGoToMagara,
+	"GoToPotion": ActionGoToPotion,
+}
+
+// agentObservation is what an agent knows before choosing an action. Log
+// contains the messages since the previous observation. The last
+// observation of a game has Done set, and Result is either "win" or "death".
+type agentObservation struct {
+	Turn      int
+	Depth     int
+	Pos       position
+	HP        int
+	HPMax     int
+	MP        int
+	MPMax     int
+	Bananas   int
+	Statuses  map[string]int
+	Magaras   []agentMagara
+	Inventory agentInventory
+	Cells     []agentCell
+	Monsters  []agentMonster
+	Log       []string
+	Error     string `json:",omitempty"`
+	Done      bool
+	Result    string `json:",omitempty"`
+}
+
+type agentMagara struct {
+	Kind    string
+	Charges int
+	MPCost  int
+}
+
+type agentInventory struct {
+	Body string
+	Neck string
+	Misc string
+}
+
+type agentCell struct {
+	Pos     position
+	Terrain string
+}
+
+// agentMonster is a monster the player sees, or remembers from the last time
+// it was seen if Visible is false.
+type agentMonster struct {
+	Kind    string
+	Pos     position
+	State   string
+	Visible bool
+}
+
+func newAgent(r io.Reader, w io.Writer) *agent {
+	return &agent{in: bufio.NewScanner(r), enc: json.NewEncoder(w)}
+}
+
+// Read returns the next action of the agent. It returns io.EOF when the
+// agent stops sending actions.
+func (ag *agent) Read() (agentAction, error) {
+	var act agentAction
+	if !ag.in.Scan() {
+		err := ag.in.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return act, err
+	}
+	err := json.Unmarshal(ag.in.Bytes(), &act)
+	if err != nil {
+		return act, fmt.Errorf("Invalid action: %v", err)
+	}
+	return act, nil
+}
+
+// Observe sends the current state of the game to the agent.
+func (ag *agent) Observe(g *game, obs agentObservation) error {
+	p := g.Player
+	obs.Turn = g.Turn
+	obs.Depth = g.Depth
+	obs.Pos = p.Pos
+	obs.HP = p.HP
+	obs.HPMax = p.HPMax()
+	obs.MP = p.MP
+	obs.MPMax = p.MPMax()
+	obs.Bananas = p.Bananas
+	obs.Statuses = map[string]int{}
+	obs.Magaras = []agentMagara{}
+	obs.Cells = []agentCell{}
+	obs.Monsters = []agentMonster{}
+	obs.Log = []string{}
+	for st, n := range p.Statuses {
+		if n > 0 {
+			obs.Statuses[st.String()] = n
+		}
+	}
+	for _, mag := range p.Magaras {
+		obs.Magaras = append(obs.Magaras, agentMagara{Kind: mag.String(), Charges: mag.Charges, MPCost: mag.MPCost(g)})
+	}
+	obs.Inventory = agentInventory{
+		Body: p.Inventory.Body.ShortDesc(g),
+		Neck: p.Inventory.Neck.ShortDesc(g),
+		Misc: p.Inventory.Misc.ShortDesc(g),
+	}
+	for i := 0; i < DungeonNCells; i++ {
+		pos := idxtopos(i)
+		if !p.Sees(pos) {
+			continue
+		}
+		t := g.Dungeon.Cell(pos).T
+		if t == FakeStairCell {
+			// fake stairs look like normal ones
+			t = StairCell
+		}
+		obs.Cells = append(obs.Cells, agentCell{Pos: pos, Terrain: t.String()})
+	}
+	for _, mons := range g.Monsters {
+		if !mons.Exists() {
+			continue
+		}
+		if p.Sees(mons.Pos) {
+			obs.Monsters = append(obs.Monsters, agentMonster{Kind: mons.Kind.String(), Pos: mons.Pos, State: mons.State.String(), Visible: true})
+		} else if mons.LastKnownPos != InvalidPos {
+			obs.Monsters = append(obs.Monsters, agentMonster{Kind: mons.Kind.String(), Pos: mons.LastKnownPos, State: mons.LastSeenState.String()})
+		}
+	}
+	for _, e := range g.Log {
+		if e.Index >= ag.logIndex {
+			obs.Log = append(obs.Log, e.String())
+		}
+	}
+	ag.logIndex = g.LogIndex
+	return ag.enc.Encode(&obs)
+}
+
+// AgentTurn plays a player turn with actions read from the agent, until one
+// of them takes time. It returns true if the agent quit.
+func (ui *gameui) AgentTurn() (quit bool) {
+	g := ui.g
+	if ui.agent.bot != nil {
+		return ui.BotTurn()
+	}
+	var err error
+	for {
+		obs := agentObservation{}
+		if err != nil {
+			obs.Error = err.Error()
+		}
+		if ui.agent.Observe(g, obs) != nil {
+			return true
+		}
+		var act agentAction
+		act, err = ui.agent.Read()
+		if err == io.EOF {
+			return true
+		}
+		if err != nil {
+			continue
+		}
+		var again bool
+		again, quit, err = ui.AgentAction(act)
+		if quit {
+			return true
+		}
+		if !again {
+			return false
+		}
+	}
+}
+
+// AgentAction performs an action of the agent. Actions that use a menu in
+// the interface take their choice from the action instead.
+func (ui *gameui) AgentAction(act agentAction) (again, quit bool, err error) {
+	g := ui.g
+	k, ok := agentActions[act.Action]
+	if !ok {
+		return true, false, fmt.Errorf("Unknown action %q.", act.Action)
+	}
+	switch {
+	case k == ActionEvoke:
+		if act.Magara < 0 || act.Magara >= len(g.Player.Magaras) {
+			err = fmt.Errorf("Invalid magara slot %d.", act.Magara)
+			break
+		}
+		err = g.UseMagara(act.Magara)
+	case k == ActionInteract && g.Dungeon.Cell(g.Player.Pos).T == MagaraCell:
+		if act.Magara < 0 || act.Magara >= len(g.Player.Magaras) {
+			err = fmt.Errorf("Invalid magara slot %d.", act.Magara)
+			break
+		}
+		err = g.EquipMagara(act.Magara)
+	case k == ActionTravel:
+		if act.Target == nil || !act.Target.valid() {
+			err = errors.New("Travel needs a valid target.")
+			break
+		}
+		err = g.GoToLandmark(*act.Target)
+	default:
+		return ui.HandleKey(runeKeyAction{k: k})
+	}
+	if err != nil {
+		again = true
+	}
+	return again, quit, err
+}
+
+// PlayAgent plays a new game with actions from the agent of the interface,
+// and sends a last observation when the game ends. Saves, dumps and replays
+// go to a temporary directory, so that the games of the agent do not
+// interfere with the ones of the player.
+func (ui *gameui) PlayAgent() error {
+	g := ui.g
+	dir, err := ioutil.TempDir("", "harmonist-agent")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	g.dataDir = dir
+	ui.LinkColors()
+	g.config.Version = Version
+	ui.ApplyConfig()
+	g.ui = ui
+	ui.DrawBufferInit()
+	g.InitLevel()
+	g.EventLoop()
+	obs := agentObservation{Done: true}
+	switch {
+	case g.Depth == -1:
+		obs.Result = "win"
+	case g.Player.HP <= 0:
+		obs.Result = "death"
+	default:
+		// the agent quit
+		return nil
+	}
+	return ui.agent.Observe(g, obs)
+}
@@ -0,0 +1,79 @@
+package main
+
+// DurationAlarm is the duration of an alarm raised by a guard.
+const DurationAlarm = 80
+
+// AlarmRange is the maximal distance a guard goes to raise an alarm.
+const AlarmRange = 25
+
+// RaisesAlarms reports whether monsters of the kind run to alarm gongs when
+// they lose track of the player.
+func (mk monsterKind) RaisesAlarms() bool {
+	switch mk {
+	case MonsGuard, MonsHighGuard:
+		return true
+	default:
+		return false
+	}
+}
+
+// NearestAlarm returns the nearest alarm gong the monster can reach, or
+// InvalidPos.
+func (m *monster) NearestAlarm(g *game) position {
+	alarms := []position{}
+	for i, c := range g.Dungeon.Cells {
+		if c.T == AlarmCell {
+			alarms = append(alarms, idxtopos(i))
+		}
+	}
+	best := InvalidPos
+	dist := AlarmRange + 1
+	for _, pos := range alarms {
+		if pos.Distance(m.Pos) >= dist {
+			continue
+		}
+		if path := m.APath(g, m.Pos, pos); len(path) > 0 && len(path) <= AlarmRange+1 {
+			best = pos
+			dist = pos.Distance(m.Pos)
+		}
+	}
+	return best
+}
+
+// GoRaiseAlarm makes a monster that lost track of the player head to the
+// nearest alarm gong, if it is the kind of monster that raises alarms and
+// there is no alarm already. It returns true if the monster does so.
+func (m *monster) GoRaiseAlarm(g *game) bool {
+	if !m.Kind.RaisesAlarms() || g.Player.HasStatus(StatusAlarm) {
+		return false
+	}
+	pos := m.NearestAlarm(g)
+	if pos == InvalidPos {
+		return false
+	}
+	if g.Player.Sees(m.Pos) {
+		g.Printf("%s runs to raise the alarm.", m.Kind.Definite(true))
+	}
+	m.MakeWanderAt(pos)
+	m.RaisingAlarm = true
+	return true
+}
+
+// RaiseAlarm puts the monsters of the level on the alert: for the duration
+// of the alarm, they watch longer and search around the place where the
+// monster raising the alarm last saw the player.
+func (g *game) RaiseAlarm(m *monster) {
+	g.PrintStyled("Gong! The alarm has been raised.", logCritic)
+	g.StoryPrint("Guards raised the alarm")
+	g.Stats.Alarms++
+	g.PutStatus(StatusAlarm, DurationAlarm)
+	for _, mons := range g.Monsters {
+		if !mons.Exists() || mons.Kind.Peaceful() || mons.State == Hunting || mons.Band == m.Band {
+			continue
+		}
+		if m.Search != InvalidPos {
+			mons.Search = m.Search
+		}
+		mons.StartWatching()
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math/rand"
 	"sort"
 	"time"
 )
@@ -15,7 +16,7 @@ const (
 )
 
 func (ui *gameui) SwappingAnimation(mpos, ppos position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
@@ -23,27 +24,27 @@ func (ui *gameui) SwappingAnimation(mpos, ppos position) {
 	_, fgm, bgColorm := ui.PositionDrawing(mpos)
 	_, _, bgColorp := ui.PositionDrawing(ppos)
 	ui.DrawAtPosition(mpos, true, 'Φ', fgm, bgColorp)
-	ui.DrawAtPosition(ppos, true, 'Φ', ColorFgPlayer, bgColorm)
+	ui.DrawAtPosition(ppos, true, 'Φ', ui.ColorFgPlayer, bgColorm)
 	ui.Flush()
 	Sleep(AnimDurMedium)
-	ui.DrawAtPosition(mpos, true, 'Φ', ColorFgPlayer, bgColorp)
+	ui.DrawAtPosition(mpos, true, 'Φ', ui.ColorFgPlayer, bgColorp)
 	ui.DrawAtPosition(ppos, true, 'Φ', fgm, bgColorm)
 	ui.Flush()
 	Sleep(AnimDurMedium)
 }
 
 func (ui *gameui) TeleportAnimation(from, to position, showto bool) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	_, _, bgColorf := ui.PositionDrawing(from)
 	_, _, bgColort := ui.PositionDrawing(to)
-	ui.DrawAtPosition(from, true, 'Φ', ColorCyan, bgColorf)
+	ui.DrawAtPosition(from, true, 'Φ', ui.ColorCyan, bgColorf)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	if showto {
-		ui.DrawAtPosition(from, true, 'Φ', ColorBlue, bgColorf)
-		ui.DrawAtPosition(to, true, 'Φ', ColorCyan, bgColort)
+		ui.DrawAtPosition(from, true, 'Φ', ui.ColorBlue, bgColorf)
+		ui.DrawAtPosition(to, true, 'Φ', ui.ColorCyan, bgColort)
 		ui.Flush()
 		Sleep(AnimDurMedium)
 	}
@@ -58,7 +59,7 @@ const (
 )
 
 func (ui *gameui) MonsterProjectileAnimation(ray []position, r rune, fg uicolor) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
@@ -83,7 +84,9 @@ func (ui *gameui) ExplosionDrawAt(pos position, fg uicolor) {
 	_, _, bgColor := ui.PositionDrawing(pos)
 	mons := g.MonsterAt(pos)
 	r := ';'
-	switch RandInt(9) {
+	// animations do not use the random generator of the game, so that
+	// they do not change how the game plays
+	switch rand.Intn(9) {
 	case 0, 6:
 		r = ','
 	case 1:
@@ -104,11 +107,11 @@ func (ui *gameui) ExplosionDrawAt(pos position, fg uicolor) {
 }
 
 func (ui *gameui) NoiseAnimation(noises []position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.LOSWavesAnimation(DefaultLOSRange, WaveMagicNoise, ui.g.Player.Pos)
-	colors := []uicolor{ColorFgSleepingMonster, ColorFgMagicPlace}
+	colors := []uicolor{ui.ColorFgSleepingMonster, ui.ColorFgMagicPlace}
 	for i := 0; i < 2; i++ {
 		for _, pos := range noises {
 			r := '♫'
@@ -125,15 +128,15 @@ func (ui *gameui) NoiseAnimation(noises []position) {
 
 func (ui *gameui) ExplosionAnimation(es explosionStyle, pos position) {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
 	Sleep(AnimDurShort)
-	colors := [2]uicolor{ColorFgExplosionStart, ColorFgExplosionEnd}
+	colors := [2]uicolor{ui.ColorFgExplosionStart, ui.ColorFgExplosionEnd}
 	if es == WallExplosion || es == AroundWallExplosion {
-		colors[0] = ColorFgExplosionWallStart
-		colors[1] = ColorFgExplosionWallEnd
+		colors[0] = ui.ColorFgExplosionWallStart
+		colors[1] = ui.ColorFgExplosionWallEnd
 	}
 	for i := 0; i < 3; i++ {
 		nb := g.Dungeon.FreeNeighbors(pos)
@@ -141,7 +144,7 @@ func (ui *gameui) ExplosionAnimation(es explosionStyle, pos position) {
 			nb = append(nb, pos)
 		}
 		for _, npos := range nb {
-			fg := colors[RandInt(2)]
+			fg := colors[rand.Intn(2)]
 			if !g.Player.LOS[npos] {
 				continue
 			}
@@ -160,7 +163,7 @@ func (g *game) Waves(maxCost int, ws wavestyle, center position) (dists []int, c
 	default:
 		dij = &noisePath{game: g}
 	}
-	nm := Dijkstra(dij, []position{center}, maxCost)
+	nm := g.Dijkstra(dij, []position{center}, maxCost)
 	cdists = make(map[int][]int)
 	nm.iter(g.Player.Pos, func(n *node) {
 		pos := n.Pos
@@ -196,7 +199,7 @@ const (
 )
 
 func (ui *gameui) WaveAnimation(wave []int, ws wavestyle) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
@@ -204,32 +207,32 @@ func (ui *gameui) WaveAnimation(wave []int, ws wavestyle) {
 		pos := idxtopos(i)
 		switch ws {
 		case WaveConfusion:
-			fg := ColorFgConfusedMonster
+			fg := ui.ColorFgConfusedMonster
 			if ui.g.Player.Sees(pos) {
 				ui.WaveDrawAt(pos, fg)
 			}
 		case WaveSleeping:
-			fg := ColorFgSleepingMonster
+			fg := ui.ColorFgSleepingMonster
 			if ui.g.Player.Sees(pos) {
 				ui.WaveDrawAt(pos, fg)
 			}
 		case WaveSlowing:
-			fg := ColorFgParalysedMonster
+			fg := ui.ColorFgParalysedMonster
 			if ui.g.Player.Sees(pos) {
 				ui.WaveDrawAt(pos, fg)
 			}
 		case WaveTree:
-			fg := ColorFgLignifiedMonster
+			fg := ui.ColorFgLignifiedMonster
 			if ui.g.Player.Sees(pos) {
 				ui.WaveDrawAt(pos, fg)
 			}
 		case WaveNoise:
-			fg := ColorFgWanderingMonster
+			fg := ui.ColorFgWanderingMonster
 			if ui.g.Player.Sees(pos) {
 				ui.WaveDrawAt(pos, fg)
 			}
 		case WaveMagicNoise:
-			fg := ColorFgMagicPlace
+			fg := ui.ColorFgMagicPlace
 			ui.WaveDrawAt(pos, fg)
 		}
 	}
@@ -238,10 +241,10 @@ func (ui *gameui) WaveAnimation(wave []int, ws wavestyle) {
 }
 
 func (ui *gameui) WallExplosionAnimation(pos position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
-	colors := [2]uicolor{ColorFgExplosionWallStart, ColorFgExplosionWallEnd}
+	colors := [2]uicolor{ui.ColorFgExplosionWallStart, ui.ColorFgExplosionWallEnd}
 	for _, fg := range colors {
 		_, _, bgColor := ui.PositionDrawing(pos)
 		//ui.DrawAtPosition(pos, true, '☼', fg, bgColor)
@@ -260,7 +263,7 @@ const (
 )
 
 func (ui *gameui) BeamsAnimation(ray []position, bs beamstyle) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
@@ -269,18 +272,18 @@ func (ui *gameui) BeamsAnimation(ray []position, bs beamstyle) {
 	var fg uicolor
 	switch bs {
 	case BeamSleeping:
-		fg = ColorFgSleepingMonster
+		fg = ui.ColorFgSleepingMonster
 	case BeamLignification:
-		fg = ColorFgLignifiedMonster
+		fg = ui.ColorFgLignifiedMonster
 	case BeamObstruction:
-		fg = ColorFgMagicPlace
+		fg = ui.ColorFgMagicPlace
 	}
 	for j := 0; j < 3; j++ {
 		for i := len(ray) - 1; i >= 0; i-- {
 			pos := ray[i]
 			_, _, bgColor := ui.PositionDrawing(pos)
 			r := '*'
-			if RandInt(2) == 0 {
+			if rand.Intn(2) == 0 {
 				r = '×'
 			}
 			ui.DrawAtPosition(pos, true, r, bgColor, fg)
@@ -291,19 +294,19 @@ func (ui *gameui) BeamsAnimation(ray []position, bs beamstyle) {
 }
 
 func (ui *gameui) SlowingMagaraAnimation(ray []position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
 	Sleep(AnimDurShort)
-	colors := [2]uicolor{ColorFgConfusedMonster, ColorFgMagicPlace}
+	colors := [2]uicolor{ui.ColorFgConfusedMonster, ui.ColorFgMagicPlace}
 	for j := 0; j < 3; j++ {
 		for i := len(ray) - 1; i >= 0; i-- {
-			fg := colors[RandInt(2)]
+			fg := colors[rand.Intn(2)]
 			pos := ray[i]
 			_, _, bgColor := ui.PositionDrawing(pos)
 			r := '*'
-			if RandInt(2) == 0 {
+			if rand.Intn(2) == 0 {
 				r = '×'
 			}
 			ui.DrawAtPosition(pos, true, r, bgColor, fg)
@@ -329,7 +332,7 @@ func (ui *gameui) ProjectileSymbol(dir direction) (r rune) {
 
 func (ui *gameui) MonsterJavelinAnimation(ray []position, hit bool) {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(NormalMode)
@@ -337,7 +340,7 @@ func (ui *gameui) MonsterJavelinAnimation(ray []position, hit bool) {
 	for i := 0; i < len(ray); i++ {
 		pos := ray[i]
 		r, fgColor, bgColor := ui.PositionDrawing(pos)
-		ui.DrawAtPosition(pos, true, ui.ProjectileSymbol(pos.Dir(g.Player.Pos)), ColorFgMonster, bgColor)
+		ui.DrawAtPosition(pos, true, ui.ProjectileSymbol(pos.Dir(g.Player.Pos)), ui.ColorFgMonster, bgColor)
 		ui.Flush()
 		Sleep(AnimDurShort)
 		ui.DrawAtPosition(pos, true, r, fgColor, bgColor)
@@ -347,16 +350,16 @@ func (ui *gameui) MonsterJavelinAnimation(ray []position, hit bool) {
 
 func (ui *gameui) WoundedAnimation() {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(AnimationMode)
 	r, _, bg := ui.PositionDrawing(g.Player.Pos)
-	ui.DrawAtPosition(g.Player.Pos, false, r, ColorFgHPwounded, bg)
+	ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorFgHPwounded, bg)
 	ui.Flush()
 	Sleep(AnimDurShortMedium)
 	if g.Player.HP <= 15 {
-		ui.DrawAtPosition(g.Player.Pos, false, r, ColorFgHPcritical, bg)
+		ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorFgHPcritical, bg)
 		ui.Flush()
 		Sleep(AnimDurShortMedium)
 	}
@@ -364,16 +367,16 @@ func (ui *gameui) WoundedAnimation() {
 
 func (ui *gameui) PlayerGoodEffectAnimation() {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(AnimationMode)
 	Sleep(AnimDurShort)
 	r, fg, bg := ui.PositionDrawing(g.Player.Pos)
-	ui.DrawAtPosition(g.Player.Pos, false, r, ColorGreen, bg)
+	ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorGreen, bg)
 	ui.Flush()
 	Sleep(AnimDurShortMedium)
-	ui.DrawAtPosition(g.Player.Pos, false, r, ColorYellow, bg)
+	ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorYellow, bg)
 	ui.Flush()
 	Sleep(AnimDurShortMedium)
 	ui.DrawAtPosition(g.Player.Pos, false, r, fg, bg)
@@ -382,23 +385,23 @@ func (ui *gameui) PlayerGoodEffectAnimation() {
 
 func (ui *gameui) StatusEndAnimation() {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	ui.DrawDungeonView(AnimationMode)
 	r, _, bg := ui.PositionDrawing(g.Player.Pos)
-	ui.DrawAtPosition(g.Player.Pos, false, r, ColorViolet, bg)
+	ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorViolet, bg)
 	ui.Flush()
 	Sleep(AnimDurShortMedium)
 }
 
 func (ui *gameui) FoundFakeStairsAnimation() {
 	g := ui.g
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	r, fg, bg := ui.PositionDrawing(g.Player.Pos)
-	ui.DrawAtPosition(g.Player.Pos, false, r, ColorMagenta, bg)
+	ui.DrawAtPosition(g.Player.Pos, false, r, ui.ColorMagenta, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	ui.DrawAtPosition(g.Player.Pos, false, r, fg, bg)
@@ -406,12 +409,12 @@ func (ui *gameui) FoundFakeStairsAnimation() {
 }
 
 func (ui *gameui) MusicAnimation(pos position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	// TODO: not convinced by this animation
 	//r, fg, bg := ui.PositionDrawing(pos)
-	//ui.DrawAtPosition(pos, false, '♪', ColorCyan, bg)
+	//ui.DrawAtPosition(pos, false, '♪', ui.ColorCyan, bg)
 	//ui.Flush()
 	//time.Sleep(AnimDurMediumLong)
 	//ui.DrawAtPosition(pos, false, r, fg, bg)
@@ -419,7 +422,7 @@ func (ui *gameui) MusicAnimation(pos position) {
 }
 
 func (ui *gameui) PushAnimation(path []position) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	if len(path) == 0 {
@@ -429,15 +432,15 @@ func (ui *gameui) PushAnimation(path []position) {
 	ui.DrawDungeonView(AnimationMode)
 	_, _, bg := ui.PositionDrawing(path[0])
 	for _, pos := range path[:len(path)-1] {
-		ui.DrawAtPosition(pos, false, '×', ColorFgPlayer, bg)
+		ui.DrawAtPosition(pos, false, '×', ui.ColorFgPlayer, bg)
 	}
-	ui.DrawAtPosition(path[len(path)-1], false, '@', ColorFgPlayer, bg)
+	ui.DrawAtPosition(path[len(path)-1], false, '@', ui.ColorFgPlayer, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 }
 
 func (ui *gameui) MenuSelectedAnimation(m menu, ok bool) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	if !ui.Small() {
@@ -451,9 +454,9 @@ func (ui *gameui) MenuSelectedAnimation(m menu, ok bool) {
 			return
 		}
 		if ok {
-			ui.DrawColoredText(message, MenuCols[m][0], DungeonHeight, ColorCyan)
+			ui.DrawColoredText(message, ui.MenuCols[m][0], DungeonHeight, ui.ColorCyan)
 		} else {
-			ui.DrawColoredText(message, MenuCols[m][0], DungeonHeight, ColorMagenta)
+			ui.DrawColoredText(message, ui.MenuCols[m][0], DungeonHeight, ui.ColorMagenta)
 		}
 		ui.Flush()
 		var t time.Duration = 25
@@ -461,12 +464,12 @@ func (ui *gameui) MenuSelectedAnimation(m menu, ok bool) {
 			t += 25
 		}
 		Sleep(t)
-		ui.DrawColoredText(message, MenuCols[m][0], DungeonHeight, ColorViolet)
+		ui.DrawColoredText(message, ui.MenuCols[m][0], DungeonHeight, ui.ColorViolet)
 	}
 }
 
 func (ui *gameui) MagicMappingAnimation(border []int) {
-	if DisableAnimations {
+	if ui.DisableAnimations {
 		return
 	}
 	for _, i := range border {
@@ -479,7 +482,7 @@ func (ui *gameui) MagicMappingAnimation(border []int) {
 
 func (ui *gameui) FreeingShaedraAnimation() {
 	g := ui.g
-	//if DisableAnimations {
+	//if ui.DisableAnimations {
 	// TODO this animation cannot be disabled as-is, because code is mixed with it...
 	//return
 	//}
@@ -490,7 +493,7 @@ func (ui *gameui) FreeingShaedraAnimation() {
 	ui.Flush()
 	ui.WaitForContinue(-1)
 	_, _, bg := ui.PositionDrawing(g.Places.Monolith)
-	ui.DrawAtPosition(g.Places.Monolith, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Monolith, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Objects.Stairs[g.Places.Monolith] = WinStair
@@ -499,7 +502,7 @@ func (ui *gameui) FreeingShaedraAnimation() {
 	ui.Flush()
 	Sleep(AnimDurLong)
 	_, _, bg = ui.PositionDrawing(g.Places.Marevor)
-	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Objects.Story[g.Places.Marevor] = StoryMarevor
@@ -510,8 +513,8 @@ func (ui *gameui) FreeingShaedraAnimation() {
 	ui.Flush()
 	ui.WaitForContinue(-1)
 	ui.DrawDungeonView(NoFlushMode)
-	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ColorFgMagicPlace, bg)
-	ui.DrawAtPosition(g.Places.Shaedra, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ui.ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Shaedra, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Dungeon.SetCell(g.Places.Shaedra, GroundCell)
@@ -526,7 +529,7 @@ func (g *game) RescuedShaedra() {
 	g.Player.Magaras = append(g.Player.Magaras, magara{})
 	g.Player.Inventory.Misc = NoItem
 	g.PrintStyled("You equip the new magara in the artifact's old place.", logSpecial)
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		g.Player.Magaras[len(g.Player.Magaras)-1] = magara{Kind: DispersalMagara, Charges: DispersalMagara.DefaultCharges()}
 	} else {
 		g.Player.Magaras[len(g.Player.Magaras)-1] = magara{Kind: DelayedOricExplosionMagara, Charges: DelayedOricExplosionMagara.DefaultCharges()}
@@ -536,7 +539,7 @@ func (g *game) RescuedShaedra() {
 
 func (ui *gameui) TakingArtifactAnimation() {
 	g := ui.g
-	//if DisableAnimations {
+	//if ui.DisableAnimations {
 	// TODO this animation cannot be disabled as-is, because code is mixed with it...
 	//return
 	//}
@@ -547,7 +550,7 @@ func (ui *gameui) TakingArtifactAnimation() {
 	ui.WaitForContinue(-1)
 	g.Dungeon.SetCell(g.Places.Artifact, GroundCell)
 	_, _, bg := ui.PositionDrawing(g.Places.Monolith)
-	ui.DrawAtPosition(g.Places.Monolith, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Monolith, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Objects.Stairs[g.Places.Monolith] = WinStair
@@ -556,7 +559,7 @@ func (ui *gameui) TakingArtifactAnimation() {
 	ui.Flush()
 	Sleep(AnimDurLong)
 	_, _, bg = ui.PositionDrawing(g.Places.Marevor)
-	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Objects.Story[g.Places.Marevor] = StoryMarevor
@@ -567,7 +570,7 @@ func (ui *gameui) TakingArtifactAnimation() {
 	ui.Flush()
 	ui.WaitForContinue(-1)
 	ui.DrawDungeonView(NoFlushMode)
-	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ColorFgMagicPlace, bg)
+	ui.DrawAtPosition(g.Places.Marevor, false, 'Φ', ui.ColorFgMagicPlace, bg)
 	ui.Flush()
 	Sleep(AnimDurMediumLong)
 	g.Dungeon.SetCell(g.Places.Marevor, GroundCell)
@@ -5,51 +5,62 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"io"
+	"log"
 	"os"
 	"os/exec"
+	"runtime"
 )
 
-var InCh chan uiInput
-var Interrupt chan bool
-
-func init() {
-	InCh = make(chan uiInput, 100)
-	Interrupt = make(chan bool)
-}
-
 type gameui struct {
-	g       *game
-	bStdin  *bufio.Reader
-	bStdout *bufio.Writer
-	cursor  position
-	stty    string
+	uiContext
+	g         *game
+	bStdin    *bufio.Reader
+	bStdout   *bufio.Writer
+	stream    bool
+	inCh      chan uiInput
+	interrupt chan bool
+	hangup    chan bool
+	cursor    position
+	stty      string
 	// below unused for this backend
 	menuHover menu
 	itemHover int
 }
 
+// NewStreamUI returns an interface for a game that reads keys from r and
+// draws to w, instead of using the terminal of the process.
+func NewStreamUI(g *game, r io.Reader, w io.Writer) *gameui {
+	ui := NewGameUI(g)
+	ui.bStdin = bufio.NewReader(r)
+	ui.bStdout = bufio.NewWriter(w)
+	ui.stream = true
+	return ui
+}
+
 func (ui *gameui) Init() error {
-	ui.bStdin = bufio.NewReader(os.Stdin)
-	ui.bStdout = bufio.NewWriter(os.Stdout)
+	ui.inCh = make(chan uiInput, 100)
+	ui.interrupt = make(chan bool)
+	ui.hangup = make(chan bool)
+	if !ui.stream {
+		ui.bStdin = bufio.NewReader(os.Stdin)
+		ui.bStdout = bufio.NewWriter(os.Stdout)
+	}
 	fmt.Fprint(ui.bStdout, "\x1b[2J")
 	ui.HideCursor()
 	fmt.Fprintf(ui.bStdout, "\x1b[?25l")
-	cmd := exec.Command("stty", "-g")
-	cmd.Stdin = os.Stdin
-	save, err := cmd.Output()
-	if err != nil {
-		save = []byte("sane")
-	}
-	ui.stty = string(save)
-	cmd = exec.Command("stty", "raw", "-echo")
-	cmd.Stdin = os.Stdin
-	cmd.Run()
 	ui.menuHover = -1
+	if !ui.stream {
+		ui.RawMode()
+	}
 	go func() {
 		for {
 			r, _, err := ui.bStdin.ReadRune()
 			if err == nil {
-				InCh <- uiInput{key: string(r)}
+				ui.inCh <- uiInput{key: string(r)}
+			} else if ui.stream {
+				close(ui.hangup)
+				return
 			}
 		}
 	}()
@@ -57,10 +68,26 @@ func (ui *gameui) Init() error {
 	return nil
 }
 
+func (ui *gameui) RawMode() {
+	cmd := exec.Command("stty", "-g")
+	cmd.Stdin = os.Stdin
+	save, err := cmd.Output()
+	if err != nil {
+		save = []byte("sane")
+	}
+	ui.stty = string(save)
+	cmd = exec.Command("stty", "raw", "-echo")
+	cmd.Stdin = os.Stdin
+	cmd.Run()
+}
+
 func (ui *gameui) Close() {
 	fmt.Fprint(ui.bStdout, "\x1b[2J")
 	fmt.Fprintf(ui.bStdout, "\x1b[?25h")
 	ui.bStdout.Flush()
+	if ui.stream {
+		return
+	}
 	cmd := exec.Command("stty", ui.stty)
 	cmd.Stdin = os.Stdin
 	err := cmd.Run()
@@ -76,6 +103,9 @@ func (ui *gameui) MoveTo(x, y int) {
 }
 
 func (ui *gameui) Flush() {
+	if ui.agent != nil {
+		return
+	}
 	ui.DrawLogFrame()
 	var prevfg, prevbg uicolor
 	first := true
@@ -124,36 +154,56 @@ func (ui *gameui) Flush() {
 }
 
 func (ui *gameui) ApplyToggleLayout() {
-	GameConfig.Small = !GameConfig.Small
-	if GameConfig.Small {
+	ui.g.config.Small = !ui.g.config.Small
+	if ui.g.config.Small {
 		ui.Clear()
 		ui.Flush()
-		UIHeight = 24
-		UIWidth = 80
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	} else {
-		UIHeight = 26
-		if CenteredCamera {
-			UIWidth = 80
+		ui.UIHeight = 26
+		if ui.CenteredCamera {
+			ui.UIWidth = 80
 		} else {
-			UIWidth = 100
+			ui.UIWidth = 100
 		}
 	}
-	ui.g.DrawBuffer = make([]UICell, UIWidth*UIHeight)
+	ui.g.DrawBuffer = make([]UICell, ui.UIWidth*ui.UIHeight)
 	ui.Clear()
 }
 
 func (ui *gameui) Small() bool {
-	return GameConfig.Small
+	return ui.g.config.Small
 }
 
 func (ui *gameui) Interrupt() {
-	Interrupt <- true
+	if ui.agent != nil {
+		return
+	}
+	select {
+	case ui.interrupt <- true:
+	case <-ui.hangup:
+	}
 }
 
 func (ui *gameui) PollEvent() (in uiInput) {
+	if ui.agent != nil {
+		return agentInput
+	}
 	select {
-	case in = <-InCh:
-	case in.interrupt = <-Interrupt:
+	case in = <-ui.inCh:
+	case in.interrupt = <-ui.interrupt:
+	case <-ui.hangup:
+		// The remote player is gone: save the game if it is still
+		// going on, and stop the goroutine running it, after its
+		// deferred calls.
+		if ui.g.Ev != nil && ui.g.Depth > 0 && ui.g.Player.HP > 0 {
+			ui.g.Ev.Renew(ui.g, 0)
+			if err := ui.g.Save(); err != nil {
+				log.Printf("Could not save game: %v", err)
+			}
+		}
+		runtime.Goexit()
 	}
 	return in
 }
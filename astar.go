@@ -40,16 +40,30 @@ type node struct {
 }
 
 type nodeMap struct {
-	Nodes []node
-	Index int
+	Nodes       []node
+	Index       int
+	iterVisited []int
+	iterQueue   []int
 }
 
-var nodeCache nodeMap
-var queueCache priorityQueue
+// pathCache holds the buffers reused by the pathfinding functions of a game.
+type pathCache struct {
+	nodes        nodeMap
+	queue        priorityQueue
+	dijkstraMap  [DungeonNCells]int
+	searchAround []position
+}
 
-func init() {
-	nodeCache.Nodes = make([]node, DungeonNCells)
-	queueCache = make(priorityQueue, 0, DungeonNCells)
+func (g *game) PathCache() *pathCache {
+	if g.pathCache == nil {
+		pc := &pathCache{}
+		pc.nodes.Nodes = make([]node, DungeonNCells)
+		pc.nodes.iterVisited = make([]int, DungeonNCells)
+		pc.nodes.iterQueue = make([]int, DungeonNCells)
+		pc.queue = make(priorityQueue, 0, DungeonNCells)
+		g.pathCache = pc
+	}
+	return g.pathCache
 }
 
 func (nm nodeMap) get(p position) *node {
@@ -68,26 +82,23 @@ func (nm nodeMap) at(p position) (*node, bool) {
 	return n, true
 }
 
-var iterVisitedCache [DungeonNCells]int
-var iterQueueCache [DungeonNCells]int
-
 func (nm nodeMap) iter(pos position, f func(*node)) {
 	nb := make([]position, 4)
 	var qstart, qend int
-	iterQueueCache[qend] = pos.idx()
-	iterVisitedCache[qend] = nm.Index
+	nm.iterQueue[qend] = pos.idx()
+	nm.iterVisited[qend] = nm.Index
 	qend++
 	for qstart < qend {
-		pos = idxtopos(iterQueueCache[qstart])
+		pos = idxtopos(nm.iterQueue[qstart])
 		qstart++
 		nb = pos.CardinalNeighbors(nb, func(npos position) bool { return npos.valid() })
 		for _, npos := range nb {
 			n := &nm.Nodes[npos.idx()]
-			if n.CacheIndex == nm.Index && iterVisitedCache[npos.idx()] != nm.Index {
+			if n.CacheIndex == nm.Index && nm.iterVisited[npos.idx()] != nm.Index {
 				f(n)
-				iterQueueCache[qend] = npos.idx()
+				nm.iterQueue[qend] = npos.idx()
 				qend++
-				iterVisitedCache[npos.idx()] = nm.Index
+				nm.iterVisited[npos.idx()] = nm.Index
 			}
 		}
 	}
@@ -99,9 +110,11 @@ type Astar interface {
 	Estimation(position, position) int
 }
 
-func AstarPath(ast Astar, from, to position) (path []position, length int, found bool) {
+func (g *game) AstarPath(ast Astar, from, to position) (path []position, length int, found bool) {
+	pc := g.PathCache()
+	nodeCache := &pc.nodes
 	nodeCache.Index++
-	nqs := queueCache[:0]
+	nqs := pc.queue[:0]
 	nq := &nqs
 	heap.Init(nq)
 	fromNode := nodeCache.get(from)
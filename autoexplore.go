@@ -2,8 +2,6 @@ package main
 
 import "errors"
 
-var DijkstraMapCache [DungeonNCells]int
-
 func (g *game) Autoexplore() error {
 	if mons := g.MonsterInLOS(); mons.Exists() {
 		return errors.New("You cannot auto-explore while there are monsters in view.")
@@ -67,13 +65,19 @@ func (g *game) AutoexploreSources() []int {
 
 func (g *game) BuildAutoexploreMap(sources []int) {
 	ap := &autoexplorePath{game: g}
-	g.AutoExploreDijkstra(ap, sources)
+	if g.config.StealthPaths {
+		ap.costs = g.StealthCosts()
+		g.StealthAutoExploreDijkstra(ap, sources)
+	} else {
+		g.AutoExploreDijkstra(ap, sources)
+	}
 	g.DijkstraMapRebuild = false
 }
 
 func (g *game) NextAuto() (next *position, finished bool) {
 	ap := &autoexplorePath{game: g}
-	if DijkstraMapCache[g.Player.Pos.idx()] == unreachable {
+	dmap := g.PathCache().dijkstraMap[:]
+	if dmap[g.Player.Pos.idx()] == unreachable {
 		return nil, false
 	}
 	neighbors := ap.Neighbors(g.Player.Pos)
@@ -81,15 +85,15 @@ func (g *game) NextAuto() (next *position, finished bool) {
 		return nil, false
 	}
 	n := neighbors[0]
-	ncost := DijkstraMapCache[n.idx()]
+	ncost := dmap[n.idx()]
 	for _, pos := range neighbors[1:] {
-		cost := DijkstraMapCache[pos.idx()]
+		cost := dmap[pos.idx()]
 		if cost < ncost {
 			n = pos
 			ncost = cost
 		}
 	}
-	if ncost >= DijkstraMapCache[g.Player.Pos.idx()] {
+	if ncost >= dmap[g.Player.Pos.idx()] {
 		finished = true
 	}
 	next = &n
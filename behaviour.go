@@ -0,0 +1,174 @@
+package main
+
+// mbehaviour identifies the behaviour of a band of monsters. It is saved
+// with the band, so new kinds should be appended at the end.
+type mbehaviour int
+
+const (
+	BehPatrol mbehaviour = iota
+	BehGuard
+	BehWander
+	BehExplore
+	BehCrazyImp
+)
+
+// behaviour is the way the monsters of a band move around while they are not
+// hunting the player.
+type behaviour interface {
+	// NextTarget returns the next place the monster heads to.
+	NextTarget(g *game, m *monster) position
+	// Awake is called when a resting monster awakes by itself, after its
+	// new target has been chosen.
+	Awake(g *game, m *monster)
+	// StopWatching is called when a watching monster is done watching,
+	// after its new target has been chosen.
+	StopWatching(g *game, m *monster)
+	// Turn is called at the start of the turn of an active monster, and
+	// returns true if the monster did spend its turn.
+	Turn(g *game, m *monster) (done bool)
+}
+
+// behaviours maps each kind of behaviour to its implementation. New kinds of
+// behaviours only need an entry here.
+var behaviours = map[mbehaviour]behaviour{
+	BehPatrol:   patrolBehaviour{},
+	BehGuard:    guardBehaviour{},
+	BehWander:   wanderBehaviour{},
+	BehExplore:  exploreBehaviour{},
+	BehCrazyImp: crazyImpBehaviour{},
+}
+
+// Behaviour returns the implementation of the behaviour of the band.
+func (bd bandInfo) Behaviour() behaviour {
+	return behaviours[bd.Beh]
+}
+
+// defaultBehaviour implements the parts of behaviours that most of them
+// share: monsters wander away after resting or watching.
+type defaultBehaviour struct{}
+
+func (defaultBehaviour) Awake(g *game, m *monster) {
+	m.MakeWander()
+}
+
+func (defaultBehaviour) StopWatching(g *game, m *monster) {
+	m.MakeWander()
+	m.GatherBand(g)
+}
+
+func (defaultBehaviour) Turn(g *game, m *monster) bool {
+	return false
+}
+
+// patrolBehaviour makes monsters walk between the two ends of their path.
+type patrolBehaviour struct{ defaultBehaviour }
+
+func (patrolBehaviour) NextTarget(g *game, m *monster) (pos position) {
+	band := g.Bands[m.Band]
+	if m.Search != InvalidPos && g.RandInt(4) > 0 {
+		pos = m.SearchAround(g, m.Search, 7)
+		if pos != m.Pos && pos != InvalidPos {
+			return pos
+		}
+	}
+	if band.Path[0] == m.Target {
+		pos = band.Path[1]
+	} else if band.Path[1] == m.Target {
+		pos = band.Path[0]
+	} else if band.Path[0].Distance(m.Pos) < band.Path[1].Distance(m.Pos) {
+		pos = band.Path[0]
+		if g.RandInt(4) == 0 {
+			pos = band.Path[1]
+		}
+	} else {
+		pos = band.Path[1]
+		if g.RandInt(4) == 0 {
+			pos = band.Path[0]
+		}
+	}
+	return pos
+}
+
+// guardBehaviour makes monsters stay at their post, watching around.
+type guardBehaviour struct{ defaultBehaviour }
+
+func (guardBehaviour) NextTarget(g *game, m *monster) position {
+	band := g.Bands[m.Band]
+	if m.Search != InvalidPos && m.Search.Distance(m.Pos) < 5 && g.RandInt(2) == 0 {
+		pos := m.SearchAround(g, m.Search, 3)
+		if pos != InvalidPos {
+			return pos
+		}
+	}
+	return band.Path[0]
+}
+
+func (guardBehaviour) Awake(g *game, m *monster) {
+	m.StartWatching()
+}
+
+func (guardBehaviour) StopWatching(g *game, m *monster) {
+	m.Alternate(g)
+	if m.Pos != m.Target {
+		m.MakeWander()
+		m.GatherBand(g)
+	}
+}
+
+// wanderBehaviour makes monsters move around the first place of their path.
+type wanderBehaviour struct{ defaultBehaviour }
+
+func (wanderBehaviour) NextTarget(g *game, m *monster) (pos position) {
+	band := g.Bands[m.Band]
+	if m.Pos.Distance(band.Path[0]) < 8+g.RandInt(8) {
+		pos = m.SearchAround(g, m.Pos, 4)
+		if pos != InvalidPos {
+			return pos
+		}
+	}
+	if m.Search != InvalidPos && g.RandInt(2) == 0 {
+		pos = m.SearchAround(g, m.Search, 7)
+		if pos != InvalidPos {
+			return pos
+		}
+	}
+	pos = m.SearchAround(g, band.Path[0], 7)
+	if pos != InvalidPos {
+		return pos
+	}
+	return band.Path[0]
+}
+
+// exploreBehaviour makes monsters move between random places of their path,
+// or around them if they can open doors.
+type exploreBehaviour struct{ defaultBehaviour }
+
+func (exploreBehaviour) NextTarget(g *game, m *monster) (pos position) {
+	band := g.Bands[m.Band]
+	if m.Kind.CanOpenDoors() {
+		if m.Search != InvalidPos && g.RandInt(4) == 0 {
+			pos = m.SearchAround(g, m.Search, 7)
+		} else {
+			pos = m.SearchAround(g, m.Pos, 5)
+		}
+		if pos != InvalidPos {
+			return pos
+		}
+	}
+	return band.Path[g.RandInt(len(band.Path))]
+}
+
+// crazyImpBehaviour makes monsters follow the player whenever they can reach
+// it, or its scent otherwise.
+type crazyImpBehaviour struct{ defaultBehaviour }
+
+func (crazyImpBehaviour) NextTarget(g *game, m *monster) position {
+	path := m.APath(g, m.Pos, g.Player.Pos)
+	if len(path) == 0 {
+		if pos, ok := m.SmellScent(g); ok {
+			return pos
+		}
+		return m.SearchAround(g, m.Pos, 3)
+	}
+	return g.Player.Pos
+}
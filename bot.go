@@ -0,0 +1,483 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+)
+
+// BotMaxTurns is the number of turns after which the bot gives up a game.
+const BotMaxTurns = 20000
+
+// BotExploreTurns is the number of turns the bot spends exploring a level
+// before heading to the stairs.
+const BotExploreTurns = 300
+
+// BotFleeRange is the farthest the bot looks for a place to flee to.
+const BotFleeRange = 12
+
+// bot is the reference scripted player: it explores levels along stealthy
+// paths, sneaks around the cones of view of monsters in sight, flees to
+// hiding places or stairs from monsters coming close, rests in barrels when
+// hurt, evokes magaras when spotted, completes the story objectives and
+// descends.
+type bot struct {
+	fleeTarget position // last place the bot fled to
+}
+
+// botMagaras lists the magaras the bot evokes when spotted, by order of
+// preference.
+var botMagaras = []magaraKind{
+	SleepingMagara,
+	ParalysisMagara,
+	LignificationMagara,
+	ConfusionMagara,
+	TeleportOtherMagara,
+	ObstructionMagara,
+	FogMagara,
+	ShadowsMagara,
+	TransparencyMagara,
+	DisguiseMagara,
+	DispersalMagara,
+	BlinkMagara,
+	TeleportMagara,
+	SwiftnessMagara,
+}
+
+// Actions returns the actions the bot wants to perform, by order of
+// preference: actions that cannot be performed are skipped.
+func (b *bot) Actions(g *game) (acts []agentAction) {
+	p := g.Player
+	here := g.Dungeon.Cell(p.Pos).T
+	canDescend := g.Depth != WinDepth || g.LiberatedShaedra
+	spotted := b.Spotted(g)
+	threatened := b.Threatened(g)
+	if here == StairCell && g.Objects.Stairs[p.Pos] == NormalStair && canDescend && threatened {
+		// monsters do not follow downstairs
+		acts = append(acts, agentAction{Action: "Interact"})
+	}
+	if spotted {
+		acts = append(acts, b.Evocations(g)...)
+	}
+	if threatened {
+		if act, ok := b.Flee(g); ok {
+			acts = append(acts, act)
+		}
+	}
+	if spotted {
+		// jumping over a monster is a last resort, as it hits on the way
+		if act, ok := b.JumpOver(g); ok {
+			acts = append(acts, act)
+		}
+	}
+	inView := g.MonsterInLOS().Exists() || threatened
+	travel := func(targets []position) {
+		if len(targets) == 0 {
+			return
+		}
+		if sorted := g.SortedNearestTo(targets, p.Pos); !inView && len(sorted) > 0 {
+			acts = append(acts, agentAction{Action: "Travel", Target: &sorted[0]})
+		}
+		if act, ok := b.Sneak(g, targets); ok {
+			acts = append(acts, act)
+		}
+	}
+
+	// escape
+	wins := b.Stairs(g, WinStair)
+	if here == StairCell && g.Objects.Stairs[p.Pos] == WinStair {
+		acts = append(acts, agentAction{Action: "Interact"})
+	}
+	travel(wins)
+
+	// story objectives
+	if here == StoryCell || here == StoneCell && g.Objects.Stones[p.Pos] == SealStone {
+		acts = append(acts, agentAction{Action: "Interact"})
+	}
+	travel(b.Objectives(g))
+
+	// rest
+	if b.NeedsRest(g) {
+		if here == BarrelCell {
+			acts = append(acts, agentAction{Action: "Interact"})
+		}
+		if !inView {
+			acts = append(acts, agentAction{Action: "GoToBarrel"})
+		}
+	}
+
+	explore := func() {
+		if g.AllExplored() {
+			return
+		}
+		if !inView {
+			acts = append(acts, agentAction{Action: "Explore"})
+			return
+		}
+		sources := []position{}
+		for _, i := range g.AutoexploreSources() {
+			sources = append(sources, idxtopos(i))
+		}
+		if act, ok := b.Sneak(g, sources); ok {
+			acts = append(acts, act)
+		}
+	}
+	descend := func() {
+		if !canDescend {
+			return
+		}
+		if here == StairCell && g.Objects.Stairs[p.Pos] == NormalStair {
+			acts = append(acts, agentAction{Action: "Interact"})
+		}
+		travel(b.Stairs(g, NormalStair))
+	}
+	if g.DepthPlayerTurn < BotExploreTurns {
+		explore()
+		descend()
+	} else {
+		descend()
+		explore()
+	}
+	return acts
+}
+
+// Spotted reports whether a monster in sight is hunting the player nearby.
+func (b *bot) Spotted(g *game) bool {
+	for _, mons := range g.Monsters {
+		if !mons.Exists() || mons.Kind.Peaceful() || mons.State != Hunting {
+			continue
+		}
+		if mons.SeesPlayer(g) && mons.Pos.Distance(g.Player.Pos) <= 5 {
+			return true
+		}
+	}
+	return false
+}
+
+// Threatened reports whether a dangerous monster in sight is close enough to
+// notice the player soon. Immobile plants are only a threat once they
+// noticed the player.
+func (b *bot) Threatened(g *game) bool {
+	for _, pos := range b.Threats(g) {
+		if pos.Distance(g.Player.Pos) > 6 {
+			continue
+		}
+		if mons := g.MonsterAt(pos); mons.Exists() && mons.Kind == MonsSatowalgaPlant && mons.State != Hunting {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// Threats returns the positions of the dangerous monsters in sight, and of
+// the monsters heard.
+func (b *bot) Threats(g *game) []position {
+	threats := []position{}
+	for _, mons := range g.Monsters {
+		if mons.Exists() && !mons.Kind.Peaceful() && g.Player.Sees(mons.Pos) {
+			threats = append(threats, mons.Pos)
+		}
+	}
+	heard := []position{}
+	for pos := range g.Noise {
+		heard = append(heard, pos)
+	}
+	SortPositions(heard)
+	return append(threats, heard...)
+}
+
+// Flee returns a step toward a place the player can reach before the
+// dangerous monsters in sight, preferably out of their cones of view, on
+// stairs or in a barrel.
+func (b *bot) Flee(g *game) (agentAction, bool) {
+	p := g.Player
+	ap := &autoexplorePath{game: g}
+	var tdist [DungeonNCells]int
+	nm := g.Dijkstra(ap, b.Threats(g), unreachable)
+	for i := range tdist {
+		tdist[i] = unreachable
+		if n, ok := nm.at(idxtopos(i)); ok {
+			tdist[i] = n.Cost
+		}
+	}
+	canDescend := g.Depth != WinDepth || g.LiberatedShaedra
+	score := func(pos position, dist int) int {
+		s := 3*Min(tdist[pos.idx()], BotFleeRange) - dist
+		if !g.MonsterLOS[pos] {
+			s += 12
+		}
+		switch g.Dungeon.Cell(pos).T {
+		case StairCell:
+			if g.Objects.Stairs[pos] == NormalStair && canDescend {
+				s += 30
+			}
+		case BarrelCell:
+			s += 10
+		}
+		if pos == b.fleeTarget {
+			// stick to the plan instead of hesitating
+			s += 8
+		}
+		return s
+	}
+	target := p.Pos
+	best := score(p.Pos, 0)
+	nm = g.Dijkstra(ap, []position{p.Pos}, BotFleeRange)
+	for i := 0; i < DungeonNCells; i++ {
+		pos := idxtopos(i)
+		n, ok := nm.at(pos)
+		if !ok || n.Cost > BotFleeRange || n.Cost >= tdist[i] || g.MonsterAt(pos).Exists() {
+			continue
+		}
+		if s := score(pos, n.Cost); s > best {
+			target = pos
+			best = s
+		}
+	}
+	b.fleeTarget = target
+	if target == p.Pos {
+		return agentAction{}, false
+	}
+	return b.Sneak(g, []position{target})
+}
+
+// JumpOver returns a jump over an adjacent monster hunting the player, which
+// leaves it behind.
+func (b *bot) JumpOver(g *game) (agentAction, bool) {
+	if g.Player.HasStatus(StatusExhausted) {
+		return agentAction{}, false
+	}
+	for _, mons := range g.Monsters {
+		if mons.Exists() && !mons.Kind.Peaceful() && mons.State == Hunting && mons.Pos.Distance(g.Player.Pos) == 1 {
+			return agentAction{Action: mons.Pos.Dir(g.Player.Pos).String()}, true
+		}
+	}
+	return agentAction{}, false
+}
+
+// Evocations returns the evocations of the usable magaras among the ones of
+// botMagaras.
+func (b *bot) Evocations(g *game) (acts []agentAction) {
+	for _, k := range botMagaras {
+		for i, mag := range g.Player.Magaras {
+			if mag.Kind == k && mag.Charges > 0 && mag.MPCost(g) <= g.Player.MP {
+				acts = append(acts, agentAction{Action: "Evoke", Magara: i})
+			}
+		}
+	}
+	return acts
+}
+
+// NeedsRest reports whether the player is hurt or low on magic, and has a
+// banana to eat before sleeping.
+func (b *bot) NeedsRest(g *game) bool {
+	p := g.Player
+	return p.Bananas > 0 && (p.HP < p.HPMax() || 2*p.MP < p.MPMax())
+}
+
+// Stairs returns the known stairs of the given kind.
+func (b *bot) Stairs(g *game, st stair) []position {
+	stairs := []position{}
+	for _, pos := range g.StairsSlice() {
+		if g.Dungeon.Cell(pos).T == FakeStairCell {
+			if st == NormalStair {
+				// the bot cannot tell them from normal stairs
+				stairs = append(stairs, pos)
+			}
+			continue
+		}
+		if g.Objects.Stairs[pos] == st {
+			stairs = append(stairs, pos)
+		}
+	}
+	return stairs
+}
+
+// Objectives returns the known places the bot has to reach to progress in
+// the story: around Shaedra, the stone releasing the artifact, and the
+// artifact.
+func (b *bot) Objectives(g *game) []position {
+	targets := []position{}
+	switch {
+	case g.Depth == WinDepth && !g.LiberatedShaedra:
+		if g.Dungeon.Cell(g.Places.Shaedra).Explored {
+			targets = append(targets, g.Places.Shaedra.N(), g.Places.Shaedra.S(), g.Places.Shaedra.E(), g.Places.Shaedra.W())
+		}
+	case g.Depth == MaxDepth && !g.LiberatedArtifact:
+		if g.Objects.Story[g.Places.Artifact] == StoryArtifact {
+			if g.Dungeon.Cell(g.Places.Artifact).Explored {
+				targets = append(targets, g.Places.Artifact)
+			}
+			break
+		}
+		for pos, stn := range g.Objects.Stones {
+			if stn == SealStone && g.Dungeon.Cell(pos).Explored {
+				targets = append(targets, pos)
+			}
+		}
+		SortPositions(targets)
+	}
+	return targets
+}
+
+// Sneak returns a step toward the nearest of the targets, following stealthy
+// paths and avoiding the cones of view of monsters in sight, unless the
+// player is already seen.
+func (b *bot) Sneak(g *game, targets []position) (agentAction, bool) {
+	p := g.Player
+	for _, pos := range targets {
+		if pos.Distance(p.Pos) == 1 && !g.MonsterAt(pos).Exists() {
+			return agentAction{Action: pos.Dir(p.Pos).String()}, true
+		}
+	}
+	sources := []int{}
+	for _, pos := range targets {
+		if pos.valid() {
+			sources = append(sources, pos.idx())
+		}
+	}
+	if len(sources) == 0 {
+		return agentAction{}, false
+	}
+	ap := &autoexplorePath{game: g, costs: g.StealthCosts()}
+	g.StealthAutoExploreDijkstra(ap, sources)
+	g.DijkstraMapRebuild = true
+	dmap := g.PathCache().dijkstraMap[:]
+	threats := b.Threats(g)
+	next := p.Pos
+	cost := dmap[p.Pos.idx()]
+neighbors:
+	for _, pos := range ap.Neighbors(p.Pos) {
+		if g.MonsterAt(pos).Exists() || g.MonsterLOS[pos] && !g.MonsterLOS[p.Pos] {
+			continue
+		}
+		for _, tpos := range threats {
+			if pos.Distance(tpos) <= 1 {
+				continue neighbors
+			}
+		}
+		if dmap[pos.idx()] < cost {
+			next = pos
+			cost = dmap[pos.idx()]
+		}
+	}
+	if next == p.Pos {
+		return agentAction{}, false
+	}
+	return agentAction{Action: next.Dir(p.Pos).String()}, true
+}
+
+// BotTurn plays a player turn with the first action of the bot that takes
+// time. It returns true if the bot gives up the game.
+func (ui *gameui) BotTurn() (quit bool) {
+	g := ui.g
+	if g.Turn > BotMaxTurns {
+		return true
+	}
+	g.config.StealthPaths = true
+	for _, act := range ui.agent.bot.Actions(g) {
+		again, quit, _ := ui.AgentAction(act)
+		if quit {
+			return true
+		}
+		if !again {
+			return false
+		}
+	}
+	g.WaitTurn()
+	return false
+}
+
+// botResult is the outcome of a game played by the bot.
+type botResult struct {
+	Seed     int64
+	Win      bool
+	Depth    int
+	Turns    int
+	KilledBy string // empty if the bot won or gave up
+}
+
+func (r botResult) String() string {
+	switch {
+	case r.Win:
+		return fmt.Sprintf("seed %d: won in %d turns", r.Seed, r.Turns)
+	case r.KilledBy != "":
+		return fmt.Sprintf("seed %d: killed by %s at depth %d after %d turns", r.Seed, r.KilledBy, r.Depth, r.Turns)
+	default:
+		return fmt.Sprintf("seed %d: gave up at depth %d after %d turns", r.Seed, r.Depth, r.Turns)
+	}
+}
+
+// PlayBot plays a game with the bot, using the given random seed. With
+// telemetry, game events are written to a file in the data directory.
+func PlayBot(seed int64, telemetry bool) (botResult, error) {
+	g := &game{}
+	g.Seed(seed)
+	if telemetry {
+		err := g.StartTelemetry()
+		if err != nil {
+			return botResult{}, err
+		}
+		defer g.StopTelemetry()
+	}
+	ui := NewAgentUI(g, nil, ioutil.Discard)
+	ui.agent.bot = &bot{}
+	err := ui.PlayAgent()
+	if err != nil {
+		return botResult{}, err
+	}
+	r := botResult{Seed: seed, Depth: Max(g.Depth, g.ExploredLevels), Turns: g.Turn}
+	switch {
+	case g.Depth == -1:
+		r.Win = true
+	case g.Player.HP <= 0:
+		r.KilledBy = g.Stats.KilledBy
+	}
+	return r, nil
+}
+
+// BotBenchmark plays n games with the bot, with seeds from 1 to n, and
+// writes to w the result of each game, followed by the win rate, the
+// average depth reached and the causes of death.
+func BotBenchmark(n int, w io.Writer, telemetry bool) error {
+	var wins, depths int
+	deaths := map[string]int{}
+	for seed := int64(1); seed <= int64(n); seed++ {
+		r, err := PlayBot(seed, telemetry)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, r)
+		if r.Win {
+			wins++
+		} else if r.KilledBy != "" {
+			deaths[r.KilledBy]++
+		}
+		depths += r.Depth
+	}
+	if n == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "\nGames: %d\n", n)
+	fmt.Fprintf(w, "Win rate: %.1f%%\n", 100*float64(wins)/float64(n))
+	fmt.Fprintf(w, "Average depth: %.2f\n", float64(depths)/float64(n))
+	causes := []string{}
+	for k := range deaths {
+		causes = append(causes, k)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if deaths[causes[i]] != deaths[causes[j]] {
+			return deaths[causes[i]] > deaths[causes[j]]
+		}
+		return causes[i] < causes[j]
+	})
+	if len(causes) > 0 {
+		fmt.Fprintf(w, "Causes of death:\n")
+	}
+	for _, k := range causes {
+		fmt.Fprintf(w, "  %-20s %d\n", k, deaths[k])
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+// gameEvent is a domain event emitted by the game, describing something that
+// happened for observers like statistics or the story log. Unlike event, it
+// does not schedule anything.
+type gameEvent interface {
+	// Name returns a short name for the kind of event.
+	Name() string
+}
+
+// playerSpotted is emitted when a monster starts hunting the player.
+type playerSpotted struct {
+	Mons    *monster
+	Unaware bool // the monster was not alerted before
+}
+
+// noiseMade is emitted when a noise is made somewhere in the level.
+type noiseMade struct {
+	Noise int
+	Pos   position
+}
+
+// magaraEvoked is emitted after the player successfully evoked a magara.
+type magaraEvoked struct {
+	Magara magara
+}
+
+// monsterKilled is emitted when the player kills a monster.
+type monsterKilled struct {
+	Mons *monster
+	Seen bool // the player saw the monster die
+}
+
+// statusGained is emitted when the player gets a new status.
+type statusGained struct {
+	Status   status
+	Duration int
+}
+
+// statusEnded is emitted when a status of the player ends.
+type statusEnded struct {
+	Status status
+}
+
+// levelEntered is emitted once a new level has been generated, or a kept
+// level restored.
+type levelEntered struct {
+	Depth int
+}
+
+// monsterActed is emitted after a monster event has been handled.
+type monsterActed struct {
+	Mons   *monster
+	Action monsterAction
+}
+
+// gameEnded is emitted when the player dies or escapes.
+type gameEnded struct {
+	Won bool
+}
+
+func (playerSpotted) Name() string { return "player-spotted" }
+func (noiseMade) Name() string     { return "noise-made" }
+func (magaraEvoked) Name() string  { return "magara-evoked" }
+func (monsterKilled) Name() string { return "monster-killed" }
+func (statusGained) Name() string  { return "status-gained" }
+func (statusEnded) Name() string   { return "status-ended" }
+func (levelEntered) Name() string  { return "level-entered" }
+func (monsterActed) Name() string  { return "monster-acted" }
+func (gameEnded) Name() string     { return "game-ended" }
+
+// observer is notified of the domain events emitted by the game.
+type observer interface {
+	Observe(g *game, ev gameEvent)
+}
+
+// coreObservers are always notified, in order, before the observers
+// subscribed with Subscribe.
+var coreObservers = []observer{
+	statsObserver{},
+	storyObserver{},
+	achievementsObserver{},
+	dailyObserver{},
+	historyObserver{},
+	conductsObserver{},
+}
+
+// Subscribe registers an observer of the domain events of the game, for
+// analytics or external tooling. Observers are not saved with the game.
+func (g *game) Subscribe(o observer) {
+	g.observers = append(g.observers, o)
+}
+
+// Emit notifies the observers of a domain event.
+func (g *game) Emit(ev gameEvent) {
+	for _, o := range coreObservers {
+		o.Observe(g, ev)
+	}
+	for _, o := range g.observers {
+		o.Observe(g, ev)
+	}
+}
+
+// statsObserver updates the game statistics.
+type statsObserver struct{}
+
+func (statsObserver) Observe(g *game, ev gameEvent) {
+	switch ev := ev.(type) {
+	case playerSpotted:
+		g.Stats.NSpotted++
+		g.Stats.DSpotted[g.Depth]++
+		if ev.Unaware {
+			g.Stats.NUSpotted++
+			g.Stats.DUSpotted[g.Depth]++
+		}
+	case magaraEvoked:
+		mag := ev.Magara
+		g.Stats.MagarasUsed++
+		g.Stats.UsedMagaras[mag.Kind]++
+		g.Stats.DMagaraUses[g.Depth]++
+		if mag.Harmonic() {
+			g.Stats.HarmonicMagUse++
+		} else if mag.Oric() {
+			g.Stats.OricMagUse++
+		} else if mag.Kind == FireMagara {
+			g.Stats.FireUse++
+		}
+		switch mag.Kind {
+		case TeleportMagara, TeleportOtherMagara, BlinkMagara, SwappingMagara, DispersalMagara:
+			g.Stats.OricTelUse++
+		}
+	case monsterKilled:
+		g.Stats.Killed++
+		g.Stats.KilledMons[ev.Mons.Kind]++
+	case statusGained:
+		g.Stats.Statuses[ev.Status]++
+	}
+}
+
+// storyObserver writes the story log.
+type storyObserver struct{}
+
+func (storyObserver) Observe(g *game, ev gameEvent) {
+	switch ev := ev.(type) {
+	case magaraEvoked:
+		g.StoryPrintf("Evoked %s (MP: %d, Charges: %d)", ev.Magara, g.Player.MP, ev.Magara.Charges)
+	case monsterKilled:
+		g.StoryPrintf("Death of %s", ev.Mons.Kind.Indefinite(false))
+	}
+}
+
+// achievementsObserver gives achievements, once statistics are up to date.
+type achievementsObserver struct{}
+
+func (achievementsObserver) Observe(g *game, ev gameEvent) {
+	switch ev := ev.(type) {
+	case playerSpotted:
+		if ev.Unaware {
+			g.CheckAchievements(CounterHook)
+		}
+	case magaraEvoked:
+		g.CheckAchievements(CounterHook)
+	case monsterKilled:
+		if ev.Seen {
+			AchAssassin.Get(g)
+		}
+	case gameEnded:
+		g.CheckAchievements(GameEndHook)
+	}
+}
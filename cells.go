@@ -36,8 +36,99 @@ const (
 	FakeStairCell
 	PotionCell
 	QueenRockCell
+	AlarmCell
 )
 
+func (t terrain) String() (s string) {
+	switch t {
+	case WallCell:
+		s = "wall"
+	case GroundCell:
+		s = "ground"
+	case DoorCell:
+		s = "door"
+	case FoliageCell:
+		s = "foliage"
+	case BarrelCell:
+		s = "barrel"
+	case StairCell:
+		s = "stairs"
+	case StoneCell:
+		s = "stone"
+	case MagaraCell:
+		s = "magara"
+	case BananaCell:
+		s = "banana"
+	case LightCell:
+		s = "light"
+	case ExtinguishedLightCell:
+		s = "extinguished light"
+	case TableCell:
+		s = "table"
+	case TreeCell:
+		s = "tree"
+	case HoledWallCell:
+		s = "holed wall"
+	case ScrollCell:
+		s = "scroll"
+	case StoryCell:
+		s = "story"
+	case ItemCell:
+		s = "item"
+	case BarrierCell:
+		s = "barrier"
+	case WindowCell:
+		s = "window"
+	case ChasmCell:
+		s = "chasm"
+	case WaterCell:
+		s = "water"
+	case RubbleCell:
+		s = "rubble"
+	case CavernCell:
+		s = "cavern"
+	case FakeStairCell:
+		s = "fake stairs"
+	case PotionCell:
+		s = "potion"
+	case QueenRockCell:
+		s = "queen rock"
+	case AlarmCell:
+		s = "alarm"
+	}
+	return s
+}
+
+// SoundCost returns the cost of noise propagation through the terrain:
+// muffling materials absorb part of the noise.
+func (t terrain) SoundCost() int {
+	switch t {
+	case WallCell:
+		return 5
+	case DoorCell, WindowCell, BarrierCell:
+		return 3
+	case FoliageCell, HoledWallCell, TreeCell:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Footsteps returns the noise made by walking on the terrain, and the
+// corresponding sound.
+func (t terrain) Footsteps() (noise int, sound string) {
+	switch t {
+	case QueenRockCell:
+		return QueenRockFootstepNoise, "Tap-tap."
+	case WaterCell:
+		return WaterFootstepNoise, "Splash."
+	case RubbleCell:
+		return RubbleFootstepNoise, "Crunch."
+	default:
+		return 0, ""
+	}
+}
+
 func (c cell) IsPassable() bool {
 	switch c.T {
 	case WallCell, DoorCell, BarrelCell, TableCell, TreeCell, HoledWallCell, BarrierCell, WindowCell, StoryCell, ChasmCell, WaterCell:
@@ -58,7 +149,7 @@ func (c cell) IsJumpPassable() bool {
 
 func (c cell) IsNormalPatrolWay() bool {
 	switch c.T {
-	case GroundCell, ScrollCell, DoorCell, StairCell, LightCell, ItemCell, ExtinguishedLightCell, StoneCell, MagaraCell, FakeStairCell:
+	case GroundCell, ScrollCell, DoorCell, StairCell, LightCell, ItemCell, ExtinguishedLightCell, StoneCell, MagaraCell, FakeStairCell, AlarmCell:
 		return true
 	default:
 		return false
@@ -280,6 +371,8 @@ func (c cell) ShortDesc(g *game, pos position) (desc string) {
 		desc = g.Objects.Potions[pos].ShortDesc(g)
 	case QueenRockCell:
 		desc = "queen rock"
+	case AlarmCell:
+		desc = "an alarm gong"
 	}
 	return desc
 }
@@ -338,14 +431,16 @@ func (c cell) Desc(g *game, pos position) (desc string) {
 		desc = "This is natural cave ground."
 	case FakeStairCell:
 		if g.Depth == WinDepth {
-			desc = DeepStairDesc
+			desc = DeepStairDesc + g.StairBarrierDesc()
 		} else {
-			desc = NormalStairDesc
+			desc = NormalStairDesc + g.StairBarrierDesc()
 		}
 	case PotionCell:
 		desc = g.Objects.Potions[pos].Desc(g)
 	case QueenRockCell:
 		desc = "Queen rock amplifies sounds. Even though you are usually very silent, monsters may hear your footsteps when walking on those rocks."
+	case AlarmCell:
+		desc = "An alarm gong. Guards that lose track of you may run to it and raise the alarm, putting the other monsters of the level on the alert for a while."
 	}
 	var autodesc string
 	if !c.T.IsPlayerPassable() {
@@ -381,34 +476,34 @@ func (c cell) Desc(g *game, pos position) (desc string) {
 func (c cell) Style(g *game, pos position) (r rune, fg uicolor) {
 	switch c.T {
 	case WallCell:
-		r, fg = '#', ColorFgLOS
+		r, fg = '#', g.Palette().ColorFgLOS
 	case GroundCell:
-		r, fg = '.', ColorFgLOS
+		r, fg = '.', g.Palette().ColorFgLOS
 	case DoorCell:
-		r, fg = '+', ColorFgPlace
+		r, fg = '+', g.Palette().ColorFgPlace
 	case FoliageCell:
-		r, fg = '"', ColorFgLOS
+		r, fg = '"', g.Palette().ColorFgLOS
 	case BarrelCell:
-		r, fg = '&', ColorFgObject
+		r, fg = '&', g.Palette().ColorFgObject
 	case StoneCell:
 		r, fg = g.Objects.Stones[pos].Style(g)
 	case StairCell:
 		st := g.Objects.Stairs[pos]
 		r, fg = st.Style(g)
 	case MagaraCell:
-		r, fg = '/', ColorFgObject
+		r, fg = '/', g.Palette().ColorFgObject
 	case BananaCell:
-		r, fg = ')', ColorFgObject
+		r, fg = ')', g.Palette().ColorFgObject
 	case LightCell:
-		r, fg = '☼', ColorFgObject
+		r, fg = '☼', g.Palette().ColorFgObject
 	case ExtinguishedLightCell:
-		r, fg = '○', ColorFgLOS
+		r, fg = '○', g.Palette().ColorFgLOS
 	case TableCell:
-		r, fg = 'π', ColorFgObject
+		r, fg = 'π', g.Palette().ColorFgObject
 	case TreeCell:
-		r, fg = '♣', ColorFgConfusedMonster
+		r, fg = '♣', g.Palette().ColorFgConfusedMonster
 	case HoledWallCell:
-		r, fg = 'Π', ColorViolet
+		r, fg = 'Π', g.Palette().ColorViolet
 	case ScrollCell:
 		r, fg = g.Objects.Scrolls[pos].Style(g)
 	case StoryCell:
@@ -416,29 +511,31 @@ func (c cell) Style(g *game, pos position) (r rune, fg uicolor) {
 	case ItemCell:
 		r, fg = g.Objects.Items[pos].Style(g)
 	case BarrierCell:
-		r, fg = 'Ξ', ColorFgMagicPlace
+		r, fg = 'Ξ', g.Palette().ColorFgMagicPlace
 	case WindowCell:
-		r, fg = 'Θ', ColorViolet
+		r, fg = 'Θ', g.Palette().ColorViolet
 	case ChasmCell:
-		r, fg = '◊', ColorFgLOS
+		r, fg = '◊', g.Palette().ColorFgLOS
 		if g.Depth == MaxDepth || g.Depth == WinDepth {
-			fg = ColorViolet
+			fg = g.Palette().ColorViolet
 		}
 	case WaterCell:
-		r, fg = '≈', ColorFgLOS
+		r, fg = '≈', g.Palette().ColorFgLOS
 	case RubbleCell:
-		r, fg = '^', ColorFgLOS
+		r, fg = '^', g.Palette().ColorFgLOS
 	case CavernCell:
-		r, fg = ',', ColorFgLOS
+		r, fg = ',', g.Palette().ColorFgLOS
 	case FakeStairCell:
-		r, fg = '>', ColorFgPlace
+		r, fg = '>', g.Palette().ColorFgPlace
 		if g.Depth == WinDepth {
-			fg = ColorViolet
+			fg = g.Palette().ColorViolet
 		}
 	case PotionCell:
 		r, fg = g.Objects.Potions[pos].Style(g)
 	case QueenRockCell:
-		r, fg = '‗', ColorFgLOS
+		r, fg = '‗', g.Palette().ColorFgLOS
+	case AlarmCell:
+		r, fg = 'Ω', g.Palette().ColorFgPlace
 	}
 	return r, fg
 }
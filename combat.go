@@ -29,6 +29,7 @@ func (m *monster) InflictDamage(g *game, damage, max int) {
 		g.StoryPrintf("Hit by %s (HP: %d)", m.Kind, g.Player.HP)
 	} else {
 		g.StoryPrintf("Killed by %s", m.Kind)
+		g.Stats.KilledBy = m.Kind.String()
 	}
 	if g.Player.HP > 0 && g.Player.Inventory.Body == CloakConversion && g.Player.MP < g.Player.MPMax() {
 		g.Player.MP++
@@ -50,8 +51,12 @@ func (g *game) MakeMonstersAware() {
 }
 
 func (g *game) MakeNoise(noise int, at position) {
-	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{at}, noise)
+	g.Emit(noiseMade{Noise: noise, Pos: at})
+	if at == g.Player.Pos && noise > g.NoiseLevel {
+		g.NoiseLevel = noise
+	}
+	dij := &soundPath{game: g}
+	nm := g.Dijkstra(dij, []position{at}, noise)
 	//if at.Distance(g.Player.Pos)-noise < DefaultLOSRange && noise > 4 {
 	//g.ui.LOSWavesAnimation(noise, WaveNoise, at)
 	//}
@@ -81,7 +86,7 @@ func (g *game) MakeNoise(noise int, at position) {
 
 func (m *monster) LeaveRoomForPlayer(g *game) position {
 	dij := &monPath{game: g, monster: m}
-	nm := Dijkstra(dij, []position{m.Pos}, 10)
+	nm := g.Dijkstra(dij, []position{m.Pos}, 10)
 	free := InvalidPos
 	dist := unreachable
 	nm.iter(m.Pos, func(n *node) {
@@ -106,7 +111,7 @@ func (m *monster) LeaveRoomForPlayer(g *game) position {
 
 func (g *game) FindJumpTarget(m *monster) position {
 	dij := &jumpPath{game: g}
-	nm := Dijkstra(dij, []position{m.Pos}, 10)
+	nm := g.Dijkstra(dij, []position{m.Pos}, 10)
 	free := InvalidPos
 	dist := unreachable
 	nm.iter(m.Pos, func(n *node) {
@@ -156,6 +161,9 @@ func (g *game) Jump(mons *monster) error {
 	if g.Player.HasStatus(StatusExhausted) {
 		return errors.New("You cannot jump while exhausted.")
 	}
+	if err := g.ConfirmConduct(ConductNoJumps); err != nil {
+		return err
+	}
 	dir := mons.Pos.Dir(g.Player.Pos)
 	pos := g.Player.Pos
 	for {
@@ -186,9 +194,7 @@ func (g *game) Jump(mons *monster) error {
 	g.Stats.Jumps++
 	g.Printf("You jump over %s", mons.Kind.Definite(false))
 	g.StoryPrintf("Jumped over %s", mons.Kind)
-	if g.Stats.Jumps+g.Stats.WallJumps == 15 {
-		AchAcrobat.Get(g)
-	}
+	g.CheckAchievements(CounterHook)
 	return nil
 }
 
@@ -233,6 +239,9 @@ func (g *game) WallJump(pos position) error {
 	if !g.PlayerCanPass(tpos) || (count != 3 && count != 2) {
 		return errors.New("There's not enough room to jump.")
 	}
+	if err := g.ConfirmConduct(ConductNoJumps); err != nil {
+		return err
+	}
 	if !g.Player.HasStatus(StatusSwift) && g.Player.Inventory.Body != CloakAcrobat {
 		g.PutStatus(StatusExhausted, 5)
 	}
@@ -240,9 +249,7 @@ func (g *game) WallJump(pos position) error {
 	g.Stats.WallJumps++
 	g.Print("You jump by propulsing yourself against the wall.")
 	g.ui.PushAnimation(path)
-	if g.Stats.Jumps+g.Stats.WallJumps == 15 {
-		AchAcrobat.Get(g)
-	}
+	g.CheckAchievements(CounterHook)
 	return nil
 }
 
@@ -259,15 +266,10 @@ const (
 )
 
 func (g *game) HandleKill(mons *monster) {
-	g.Stats.Killed++
-	g.Stats.KilledMons[mons.Kind]++
-	if g.Player.Sees(mons.Pos) {
-		AchAssassin.Get(g)
-	}
+	g.Emit(monsterKilled{Mons: mons, Seen: g.Player.Sees(mons.Pos)})
 	if g.Dungeon.Cell(mons.Pos).T == DoorCell {
 		g.ComputeLOS()
 	}
-	g.StoryPrintf("Death of %s", mons.Kind.Indefinite(false))
 }
 
 const (
@@ -283,6 +285,8 @@ const (
 	SingingNoise           = 12
 	EarthquakeNoise        = 35
 	QueenRockFootstepNoise = 7
+	WaterFootstepNoise     = 4
+	RubbleFootstepNoise    = 3
 	DelayedHarmonicNoise   = 25
 	OricExplosionNoise     = 20
 )
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// conduct is a voluntary challenge kept over the whole game. Conducts are
+// tracked from statistics, whether they were declared or not.
+type conduct int
+
+const (
+	ConductNoEvocation conduct = iota
+	ConductNoRest
+	ConductNoStones
+	ConductNoJumps
+	ConductNeverSpotted
+	ConductNoPotions
+)
+
+var conducts = []conduct{
+	ConductNoEvocation,
+	ConductNoRest,
+	ConductNoStones,
+	ConductNoJumps,
+	ConductNeverSpotted,
+	ConductNoPotions,
+}
+
+func (c conduct) String() string {
+	switch c {
+	case ConductNoEvocation:
+		return "No evocations"
+	case ConductNoRest:
+		return "No rest"
+	case ConductNoStones:
+		return "No stones"
+	case ConductNoJumps:
+		return "No jumps"
+	case ConductNeverSpotted:
+		return "Never spotted"
+	case ConductNoPotions:
+		return "No potions"
+	default:
+		return "unknown conduct"
+	}
+}
+
+// Desc returns a short description of the conduct.
+func (c conduct) Desc() string {
+	switch c {
+	case ConductNoEvocation:
+		return "never evoke a magara"
+	case ConductNoRest:
+		return "never sleep in a barrel"
+	case ConductNoStones:
+		return "never activate a stone"
+	case ConductNoJumps:
+		return "never jump over monsters or against walls"
+	case ConductNeverSpotted:
+		return "never get spotted by a monster"
+	case ConductNoPotions:
+		return "never drink a potion"
+	default:
+		return ""
+	}
+}
+
+// Kept reports whether the conduct has been kept so far.
+func (c conduct) Kept(st *stats) bool {
+	switch c {
+	case ConductNoEvocation:
+		return st.MagarasUsed == 0
+	case ConductNoRest:
+		return st.Rest == 0
+	case ConductNoStones:
+		return st.UsedStones == 0
+	case ConductNoJumps:
+		return st.Jumps+st.WallJumps == 0
+	case ConductNeverSpotted:
+		return st.NSpotted == 0
+	case ConductNoPotions:
+		return st.Potions == 0
+	default:
+		return true
+	}
+}
+
+// ConfirmConduct asks the player for confirmation before an action that
+// would break a declared conduct that has been kept so far.
+func (g *game) ConfirmConduct(c conduct) error {
+	if !g.Conducts[c] || !c.Kept(&g.Stats) {
+		return nil
+	}
+	g.Printf("This breaks your declared conduct: %s. Continue? [y/N]", c)
+	g.ui.DrawDungeonView(NoFlushMode)
+	g.ui.Flush()
+	if g.ui.PromptConfirmation() {
+		return nil
+	}
+	return errors.New(DoNothing)
+}
+
+// ConductsReport returns the list of conducts and whether they were kept so
+// far.
+func (g *game) ConductsReport() string {
+	buf := &bytes.Buffer{}
+	for _, c := range conducts {
+		state := "kept"
+		if !c.Kept(&g.Stats) {
+			state = "broken"
+		}
+		if g.Conducts[c] {
+			state += " (declared)"
+		}
+		fmt.Fprintf(buf, "- %s: %s\n", c, state)
+	}
+	return buf.String()
+}
+
+// ConductsMenu lets the player declare conducts before a new game. Breaking
+// a declared conduct asks for confirmation first.
+func (ui *gameui) ConductsMenu() map[conduct]bool {
+	declared := map[conduct]bool{}
+	for {
+		ui.Clear()
+		ui.DrawColoredText(" Conducts ", 7, 2, ui.ColorYellow)
+		for i, c := range conducts {
+			mark := " "
+			if declared[c] {
+				mark = "*"
+			}
+			ui.DrawText(fmt.Sprintf("(%c) [%s] %-14s %s", 'a'+i, mark, c, c.Desc()), 7, 4+i)
+		}
+		ui.DrawText("Press a letter to declare a conduct, or again to withdraw it.\nPress (x) to start the game.", 7, 5+len(conducts))
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.interrupt || in.key == "x" || in.key == "X" {
+			return declared
+		}
+		for i, c := range conducts {
+			if in.key == string('a'+rune(i)) || in.mouse && in.button == 0 && in.mouseY == 4+i {
+				declared[c] = !declared[c]
+			}
+		}
+	}
+}
+
+// conductsObserver warns the player when a declared conduct is broken
+// without a prior confirmation.
+type conductsObserver struct{}
+
+func (conductsObserver) Observe(g *game, ev gameEvent) {
+	if _, ok := ev.(playerSpotted); ok && g.Conducts[ConductNeverSpotted] && g.Stats.NSpotted == 1 {
+		g.PrintfStyled("You broke your declared conduct: %s.", logCritic, ConductNeverSpotted)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// DailyShownResults is the number of previous daily challenges listed in the
+// daily challenge screen.
+const DailyShownResults = 10
+
+// dailyRecord is the local result of the daily challenge of a day. A record
+// is written as soon as the challenge starts, so that there is only one
+// attempt per day.
+type dailyRecord struct {
+	Date     string
+	Done     bool
+	Won      bool
+	Depth    int
+	Turns    int
+	KilledBy string
+	Wizard   bool
+	Practice bool
+}
+
+type dailyRecords []dailyRecord
+
+// Find returns the index of the record for the date, or -1.
+func (recs dailyRecords) Find(date string) int {
+	for i, rec := range recs {
+		if rec.Date == date {
+			return i
+		}
+	}
+	return -1
+}
+
+func (rec dailyRecord) String() string {
+	if rec.Wizard {
+		return fmt.Sprintf("%s: played in wizard mode", rec.Date)
+	}
+	if rec.Practice {
+		return fmt.Sprintf("%s: played in practice mode", rec.Date)
+	}
+	switch {
+	case !rec.Done:
+		return fmt.Sprintf("%s: unfinished", rec.Date)
+	case rec.Won:
+		return fmt.Sprintf("%s: escaped in %d turns", rec.Date, rec.Turns)
+	default:
+		return fmt.Sprintf("%s: killed by %s at depth %d after %d turns", rec.Date, rec.KilledBy, rec.Depth, rec.Turns)
+	}
+}
+
+// DailyDate returns the date of the daily challenge at a given time. Dates
+// are in UTC, so that everyone plays the same challenge at the same time.
+func DailyDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// DailySeed returns the random seed of the daily challenge of a date, from
+// which the dungeon and starting parameters are generated.
+func DailySeed(date string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("harmonist daily " + date))
+	return int64(h.Sum64())
+}
+
+// StartDaily makes the new game the daily challenge of the date. It fails
+// if the challenge has already been attempted. It has to be called before
+// the first level is generated.
+func (g *game) StartDaily(date string) error {
+	recs, err := g.LoadDailyRecords()
+	if err != nil {
+		return err
+	}
+	if recs.Find(date) >= 0 {
+		return errors.New("the daily challenge has already been attempted today")
+	}
+	recs = append(recs, dailyRecord{Date: date})
+	err = g.SaveDailyRecords(recs)
+	if err != nil {
+		return err
+	}
+	g.Daily = date
+	g.Seed(DailySeed(date))
+	return nil
+}
+
+// RecordDaily records the result of the daily challenge at the end of the
+// game.
+func (g *game) RecordDaily(won bool) error {
+	recs, err := g.LoadDailyRecords()
+	if err != nil {
+		return err
+	}
+	i := recs.Find(g.Daily)
+	if i < 0 {
+		recs = append(recs, dailyRecord{Date: g.Daily})
+		i = len(recs) - 1
+	}
+	recs[i] = dailyRecord{
+		Date:     g.Daily,
+		Done:     true,
+		Won:      won,
+		Depth:    Max(g.Depth, g.ExploredLevels),
+		Turns:    g.Turn,
+		Wizard:   g.Wizard,
+		Practice: g.Practice,
+	}
+	if !won {
+		recs[i].KilledBy = g.Stats.KilledBy
+	}
+	return g.SaveDailyRecords(recs)
+}
+
+// DailyReport returns a description of the daily challenge of the date and
+// of the most recent previous results.
+func DailyReport(recs dailyRecords, date string) string {
+	buf := &bytes.Buffer{}
+	if i := recs.Find(date); i >= 0 {
+		fmt.Fprintf(buf, "Today: %s\n", recs[i])
+		fmt.Fprintf(buf, "You already made your attempt today. Come back tomorrow!\n")
+	} else {
+		fmt.Fprintf(buf, "Today (%s): not attempted yet. You only have one try!\n", date)
+	}
+	previous := dailyRecords{}
+	for _, rec := range recs {
+		if rec.Date != date {
+			previous = append(previous, rec)
+		}
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i].Date > previous[j].Date })
+	if len(previous) > DailyShownResults {
+		previous = previous[:DailyShownResults]
+	}
+	if len(previous) > 0 {
+		fmt.Fprintf(buf, "\nPrevious days:\n")
+	}
+	for _, rec := range previous {
+		fmt.Fprintf(buf, "  %s\n", rec)
+	}
+	return buf.String()
+}
+
+// DailyMenu shows the daily challenge screen, and returns true if today's
+// challenge can be started.
+func (ui *gameui) DailyMenu() bool {
+	g := ui.g
+	date := DailyDate(time.Now())
+	recs, err := g.LoadDailyRecords()
+	var text string
+	if err != nil {
+		text = fmt.Sprintf("Error loading daily challenge results: %v\n", err)
+	} else {
+		text = DailyReport(recs, date)
+	}
+	ok := err == nil && recs.Find(date) < 0
+	if ok {
+		text += "\n───Press (d) to start the challenge or any other key to go back───"
+	} else {
+		text += "\n───Press any key to go back───"
+	}
+	ui.Clear()
+	ui.DrawColoredText(" Daily Challenge ", 7, 2, ui.ColorYellow)
+	ui.DrawText(text, 7, 4)
+	ui.Flush()
+	for {
+		in := ui.PollEvent()
+		if in.interrupt {
+			return false
+		}
+		if in.key == "d" || in.key == "D" {
+			return ok
+		}
+		if in.key != "" || in.mouse && in.button != -1 {
+			return false
+		}
+	}
+}
+
+func EncodeDailyRecords(recs dailyRecords) ([]byte, error) {
+	data := bytes.Buffer{}
+	enc := gob.NewEncoder(&data)
+	err := enc.Encode(recs)
+	if err != nil {
+		return nil, err
+	}
+	return data.Bytes(), nil
+}
+
+func DecodeDailyRecords(data []byte) (dailyRecords, error) {
+	dec := gob.NewDecoder(bytes.NewBuffer(data))
+	recs := dailyRecords{}
+	err := dec.Decode(&recs)
+	if err != nil {
+		return nil, err
+	}
+	return recs, nil
+}
+
+// dailyObserver records the result of daily challenges.
+type dailyObserver struct{}
+
+func (dailyObserver) Observe(g *game, ev gameEvent) {
+	if ev, ok := ev.(gameEnded); ok && g.Daily != "" {
+		err := g.RecordDaily(ev.Won)
+		if err != nil {
+			g.PrintfStyled("Error recording daily challenge: %v", logError, err)
+		}
+	}
+}
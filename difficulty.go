@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// difficulty is a preset of the plan of a run. The zero value is the normal
+// difficulty.
+type difficulty int
+
+const (
+	DifficultyNormal difficulty = iota
+	DifficultyExplorer
+	DifficultyNightmare
+	DifficultyCustom
+)
+
+func (d difficulty) String() string {
+	switch d {
+	case DifficultyExplorer:
+		return "Explorer"
+	case DifficultyNightmare:
+		return "Nightmare"
+	case DifficultyCustom:
+		return "Custom"
+	default:
+		return "Normal"
+	}
+}
+
+// Desc returns a short description of the difficulty for the menu.
+func (d difficulty) Desc() string {
+	switch d {
+	case DifficultyExplorer:
+		return "more potions, stones and bananas, fewer monsters"
+	case DifficultyNightmare:
+		return "less help and more monsters"
+	case DifficultyCustom:
+		return "choose each setting of the run"
+	default:
+		return "the intended experience"
+	}
+}
+
+// runPlan gathers the settings used to generate the start parameters of a
+// game and its levels.
+type runPlan struct {
+	LoreLevels     int  // number of levels with a lore message
+	HealthPotions  int  // number of levels with a health potion
+	MappingStones  int  // number of levels with a mapping stone
+	ExtraBananas   int  // bananas added to (or removed from) the whole run
+	MonsterDensity int  // percentage of common monster bands
+	BlockedChance  int  // percentage chance of a level with blocked stairs
+	Endless        bool // levels keep being generated beyond MaxDepth
+	Revisits       bool // levels are kept and can be revisited by up stairs
+}
+
+// Plan returns the plan of a difficulty preset. The custom difficulty starts
+// from the normal plan.
+func (d difficulty) Plan() runPlan {
+	switch d {
+	case DifficultyExplorer:
+		return runPlan{LoreLevels: 8, HealthPotions: 8, MappingStones: 5, ExtraBananas: 3, MonsterDensity: 75, BlockedChance: 50}
+	case DifficultyNightmare:
+		return runPlan{LoreLevels: 8, HealthPotions: 3, MappingStones: 1, ExtraBananas: -2, MonsterDensity: 130, BlockedChance: 100}
+	default:
+		return runPlan{LoreLevels: 8, HealthPotions: 5, MappingStones: 3, ExtraBananas: 0, MonsterDensity: 100, BlockedChance: 90}
+	}
+}
+
+// planSetting describes a setting of a run plan that can be changed in the
+// custom difficulty menu.
+type planSetting struct {
+	Key      string
+	Name     string
+	Min, Max int
+	Step     int
+	Value    func(p *runPlan) *int
+}
+
+var planSettings = []planSetting{
+	{"a", "lore levels", 0, Min(MaxDepth, len(LoreMessages)), 1, func(p *runPlan) *int { return &p.LoreLevels }},
+	{"b", "health potions", 0, MaxDepth, 1, func(p *runPlan) *int { return &p.HealthPotions }},
+	{"c", "mapping stones", 0, MaxDepth, 1, func(p *runPlan) *int { return &p.MappingStones }},
+	{"d", "extra bananas", -MaxDepth, MaxDepth, 1, func(p *runPlan) *int { return &p.ExtraBananas }},
+	{"e", "monster density (%)", 25, 200, 5, func(p *runPlan) *int { return &p.MonsterDensity }},
+	{"f", "blocked stairs chance (%)", 0, 100, 10, func(p *runPlan) *int { return &p.BlockedChance }},
+}
+
+// planToggle describes a game mode of a run plan that can be toggled in the
+// difficulty menu, whatever the difficulty.
+type planToggle struct {
+	Key   string
+	Name  string
+	Value func(p *runPlan) *bool
+}
+
+var planToggles = []planToggle{
+	{"e", fmt.Sprintf("endless descent beyond depth %d", MaxDepth), func(p *runPlan) *bool { return &p.Endless }},
+	{"r", "revisitable levels with up stairs", func(p *runPlan) *bool { return &p.Revisits }},
+}
+
+// SetDifficulty chooses the difficulty of a new game. Other difficulties than
+// the custom one only use the toggled modes of the plan. It has to be called
+// before the first level is generated.
+func (g *game) SetDifficulty(d difficulty, plan runPlan) {
+	g.Params.Difficulty = d
+	if d != DifficultyCustom {
+		preset := d.Plan()
+		for _, t := range planToggles {
+			*t.Value(&preset) = *t.Value(&plan)
+		}
+		plan = preset
+	}
+	g.Params.Plan = plan
+}
+
+// ScaleBands returns the number of common monster bands to place instead of
+// n, according to the monster density of the plan.
+func (p runPlan) ScaleBands(g *game, n int) int {
+	n *= p.MonsterDensity
+	m := n / 100
+	if n%100 > 0 && g.RandInt(100) < n%100 {
+		m++
+	}
+	return m
+}
+
+// DifficultyMenu shows the difficulty menu before a new game, and returns
+// the chosen difficulty and plan. Game modes can be toggled in the same
+// menu.
+func (ui *gameui) DifficultyMenu() (difficulty, runPlan) {
+	difficulties := []difficulty{DifficultyExplorer, DifficultyNormal, DifficultyNightmare, DifficultyCustom}
+	var modes runPlan // only toggles are used
+	for {
+		ui.Clear()
+		ui.DrawColoredText(" Difficulty ", 7, 2, ui.ColorYellow)
+		for i, d := range difficulties {
+			ui.DrawText(fmt.Sprintf("- (%d) %-9s  %s", i+1, d, d.Desc()), 7, 4+i)
+		}
+		y := 5 + len(difficulties)
+		for i, t := range planToggles {
+			mode := "off"
+			if *t.Value(&modes) {
+				mode = "on"
+			}
+			ui.DrawText(fmt.Sprintf("- (%s) %s: %s", t.Key, t.Name, mode), 7, y+i)
+		}
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.interrupt {
+			return DifficultyNormal, DifficultyNormal.Plan()
+		}
+		for i, t := range planToggles {
+			if in.key == t.Key || in.key == strings.ToUpper(t.Key) || in.mouse && in.button == 0 && in.mouseY == y+i {
+				v := t.Value(&modes)
+				*v = !*v
+			}
+		}
+		for i, d := range difficulties {
+			if in.key != fmt.Sprint(i+1) && !(in.mouse && in.button == 0 && in.mouseY == 4+i) {
+				continue
+			}
+			plan := d.Plan()
+			if d == DifficultyCustom {
+				plan = ui.CustomPlanMenu()
+			}
+			for _, t := range planToggles {
+				*t.Value(&plan) = *t.Value(&modes)
+			}
+			return d, plan
+		}
+	}
+}
+
+// CustomPlanMenu lets the player change the settings of the plan of a run,
+// starting from the normal one.
+func (ui *gameui) CustomPlanMenu() runPlan {
+	plan := DifficultyNormal.Plan()
+	for {
+		ui.Clear()
+		ui.DrawColoredText(" Custom Difficulty ", 7, 2, ui.ColorYellow)
+		for i, s := range planSettings {
+			ui.DrawText(fmt.Sprintf("(%s/%s) %-26s %4d", s.Key, strings.ToUpper(s.Key), s.Name, *s.Value(&plan)), 7, 4+i)
+		}
+		ui.DrawText("Press a letter to decrease a setting, or the upper case letter\nto increase it. Press (x) to start the game.", 7, 5+len(planSettings))
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.interrupt || in.key == "x" || in.key == "X" {
+			return plan
+		}
+		for _, s := range planSettings {
+			v := s.Value(&plan)
+			switch in.key {
+			case s.Key:
+				*v = Max(s.Min, *v-s.Step)
+			case strings.ToUpper(s.Key):
+				*v = Min(s.Max, *v+s.Step)
+			}
+		}
+	}
+}
@@ -9,9 +9,11 @@ type Dijkstrer interface {
 	Cost(position, position) int
 }
 
-func Dijkstra(dij Dijkstrer, sources []position, maxCost int) nodeMap {
+func (g *game) Dijkstra(dij Dijkstrer, sources []position, maxCost int) nodeMap {
+	pc := g.PathCache()
+	nodeCache := &pc.nodes
 	nodeCache.Index++
-	nqs := queueCache[:0]
+	nqs := pc.queue[:0]
 	nq := &nqs
 	heap.Init(nq)
 	for _, f := range sources {
@@ -21,7 +23,7 @@ func Dijkstra(dij Dijkstrer, sources []position, maxCost int) nodeMap {
 	}
 	for {
 		if nq.Len() == 0 {
-			return nodeCache
+			return *nodeCache
 		}
 		current := heap.Pop(nq).(*node)
 		current.Open = false
@@ -55,7 +57,7 @@ const unreachable = 9999
 // auto-exploration.
 func (g *game) AutoExploreDijkstra(dij Dijkstrer, sources []int) {
 	d := g.Dungeon
-	dmap := DijkstraMapCache[:]
+	dmap := g.PathCache().dijkstraMap[:]
 	var visited [DungeonNCells]bool
 	var queue [DungeonNCells]int
 	var qstart, qend int
@@ -86,3 +88,20 @@ func (g *game) AutoExploreDijkstra(dij Dijkstrer, sources []int) {
 		}
 	}
 }
+
+// StealthAutoExploreDijkstra is like AutoExploreDijkstra, but takes movement
+// costs into account.
+func (g *game) StealthAutoExploreDijkstra(dij Dijkstrer, sources []int) {
+	dmap := g.PathCache().dijkstraMap[:]
+	ps := []position{}
+	for _, s := range sources {
+		ps = append(ps, idxtopos(s))
+	}
+	nm := g.Dijkstra(dij, ps, unreachable)
+	for i := 0; i < DungeonNCells; i++ {
+		dmap[i] = unreachable
+		if n, ok := nm.at(idxtopos(i)); ok && !g.Dungeon.Cells[i].IsWall() {
+			dmap[i] = n.Cost
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// startingKit is a starting equipment that can be chosen in the draft at
+// the start of a new game, instead of the usual random starting magara.
+type startingKit struct {
+	Magaras [2]magaraKind
+	Cloak   item
+}
+
+// DraftChoices is the number of starting kits offered in the draft.
+const DraftChoices = 3
+
+func (k startingKit) String() string {
+	items := []string{}
+	for _, mk := range k.Magaras {
+		items = append(items, magara{Kind: mk}.String())
+	}
+	items = append(items, k.Cloak.ShortDesc(nil))
+	return strings.Join(items, ", ")
+}
+
+// DraftKits returns random starting kits for the draft. No magara nor cloak
+// is offered twice.
+func (g *game) DraftKits() []startingKit {
+	mags, cloaks := g.GeneratedMagaras, g.GeneratedCloaks
+	defer func() {
+		g.GeneratedMagaras, g.GeneratedCloaks = mags, cloaks
+	}()
+	kits := []startingKit{}
+	for i := 0; i < DraftChoices; i++ {
+		var k startingKit
+		for j := range k.Magaras {
+			k.Magaras[j] = g.RandomStartingMagara().Kind
+			g.GeneratedMagaras = append(g.GeneratedMagaras, k.Magaras[j])
+		}
+		k.Cloak = g.RandomCloak()
+		g.GeneratedCloaks = append(g.GeneratedCloaks, k.Cloak)
+		kits = append(kits, k)
+	}
+	return kits
+}
+
+// EquipStartingKit gives the drafted starting kit to the player.
+func (g *game) EquipStartingKit(k *startingKit) {
+	for i, mk := range k.Magaras {
+		g.Player.Magaras[i] = magara{Kind: mk, Charges: mk.DefaultCharges()}
+		g.GeneratedMagaras = append(g.GeneratedMagaras, mk)
+	}
+	g.Player.Inventory.Body = k.Cloak
+	g.GeneratedCloaks = append(g.GeneratedCloaks, k.Cloak)
+}
+
+// DraftMenu lets the player choose a starting kit before a new game. It
+// returns nil if the player keeps the usual random starting magara.
+func (ui *gameui) DraftMenu(kits []startingKit) *startingKit {
+	for {
+		ui.Clear()
+		ui.DrawColoredText(" Starting Kit ", 7, 2, ui.ColorYellow)
+		for i, k := range kits {
+			ui.DrawText(fmt.Sprintf("- (%c) %s", 'a'+i, k), 7, 4+i)
+		}
+		ui.DrawText("- (x) no draft: a random starting magara", 7, 4+len(kits))
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.interrupt || in.key == "x" || in.key == "X" || in.mouse && in.button == 0 && in.mouseY == 4+len(kits) {
+			return nil
+		}
+		for i := range kits {
+			if in.key == string('a'+rune(i)) || in.mouse && in.button == 0 && in.mouseY == 4+i {
+				return &kits[i]
+			}
+		}
+	}
+}
@@ -6,17 +6,12 @@ import (
 	"fmt"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
 )
 
-var (
-	UIWidth                = 100
-	UIHeight               = 26
-	DisableAnimations bool = false
-)
-
 type uicolor int
 
 const (
@@ -55,25 +50,102 @@ const (
 	Color16Green   uicolor = 2
 )
 
-// uicolors: http://ethanschoonover.com/solarized
-var (
-	ColorBase03  uicolor = Color256Base03
-	ColorBase02  uicolor = Color256Base02
-	ColorBase01  uicolor = Color256Base01
-	ColorBase00  uicolor = Color256Base00 // for dark on light background
-	ColorBase0   uicolor = Color256Base0
-	ColorBase1   uicolor = Color256Base1
-	ColorBase2   uicolor = Color256Base2
-	ColorBase3   uicolor = Color256Base3
-	ColorYellow  uicolor = Color256Yellow
-	ColorOrange  uicolor = Color256Orange
-	ColorRed     uicolor = Color256Red
-	ColorMagenta uicolor = Color256Magenta
-	ColorViolet  uicolor = Color256Violet
-	ColorBlue    uicolor = Color256Blue
-	ColorCyan    uicolor = Color256Cyan
-	ColorGreen   uicolor = Color256Green
-)
+// palette holds the colors used by an interface.
+type palette struct {
+	// uicolors: http://ethanschoonover.com/solarized
+	ColorBase03,
+	ColorBase02,
+	ColorBase01,
+	ColorBase00, // for dark on light background
+	ColorBase0,
+	ColorBase1,
+	ColorBase2,
+	ColorBase3,
+	ColorYellow,
+	ColorOrange,
+	ColorRed,
+	ColorMagenta,
+	ColorViolet,
+	ColorBlue,
+	ColorCyan,
+	ColorGreen uicolor
+
+	ColorBg,
+	ColorBgBorder,
+	ColorBgDark,
+	ColorBgLOS,
+	ColorFg,
+	ColorFgObject,
+	ColorFgTree,
+	ColorFgConfusedMonster,
+	ColorFgLignifiedMonster,
+	ColorFgParalysedMonster,
+	ColorFgDark,
+	ColorFgExcluded,
+	ColorFgExplosionEnd,
+	ColorFgExplosionStart,
+	ColorFgExplosionWallEnd,
+	ColorFgExplosionWallStart,
+	ColorFgHPcritical,
+	ColorFgHPok,
+	ColorFgHPwounded,
+	ColorFgLOS,
+	ColorFgLOSLight,
+	ColorFgMPcritical,
+	ColorFgMPok,
+	ColorFgMPpartial,
+	ColorFgMagicPlace,
+	ColorFgMonster,
+	ColorFgNote,
+	ColorFgPlace,
+	ColorFgPlayer,
+	ColorFgBananas,
+	ColorFgSleepingMonster,
+	ColorFgStatusBad,
+	ColorFgStatusGood,
+	ColorFgStatusExpire,
+	ColorFgStatusOther,
+	ColorFgWanderingMonster uicolor
+	Only8Colors bool
+}
+
+// defaultPalette styles the cells of games without interface.
+var defaultPalette palette
+
+func init() {
+	defaultPalette.Xterm256Palette()
+	defaultPalette.LinkColors()
+	defaultPalette.ApplyDarkLOS()
+}
+
+// Palette returns the colors of the game interface.
+func (g *game) Palette() *palette {
+	if g.ui == nil {
+		return &defaultPalette
+	}
+	return &g.ui.palette
+}
+
+// Xterm256Palette uses the xterm 256-color approximation of the solarized
+// palette.
+func (p *palette) Xterm256Palette() {
+	p.ColorBase03 = Color256Base03
+	p.ColorBase02 = Color256Base02
+	p.ColorBase01 = Color256Base01
+	p.ColorBase00 = Color256Base00
+	p.ColorBase0 = Color256Base0
+	p.ColorBase1 = Color256Base1
+	p.ColorBase2 = Color256Base2
+	p.ColorBase3 = Color256Base3
+	p.ColorYellow = Color256Yellow
+	p.ColorOrange = Color256Orange
+	p.ColorRed = Color256Red
+	p.ColorMagenta = Color256Magenta
+	p.ColorViolet = Color256Violet
+	p.ColorBlue = Color256Blue
+	p.ColorCyan = Color256Cyan
+	p.ColorGreen = Color256Green
+}
 
 func (ui *gameui) Map256ColorTo16(c uicolor) uicolor {
 	switch c {
@@ -153,132 +225,95 @@ func (ui *gameui) Map16ColorTo256(c uicolor) uicolor {
 	}
 }
 
-var (
-	ColorBg,
-	ColorBgBorder,
-	ColorBgDark,
-	ColorBgLOS,
-	ColorFg,
-	ColorFgObject,
-	ColorFgTree,
-	ColorFgConfusedMonster,
-	ColorFgLignifiedMonster,
-	ColorFgParalysedMonster,
-	ColorFgDark,
-	ColorFgExcluded,
-	ColorFgExplosionEnd,
-	ColorFgExplosionStart,
-	ColorFgExplosionWallEnd,
-	ColorFgExplosionWallStart,
-	ColorFgHPcritical,
-	ColorFgHPok,
-	ColorFgHPwounded,
-	ColorFgLOS,
-	ColorFgLOSLight,
-	ColorFgMPcritical,
-	ColorFgMPok,
-	ColorFgMPpartial,
-	ColorFgMagicPlace,
-	ColorFgMonster,
-	ColorFgPlace,
-	ColorFgPlayer,
-	ColorFgBananas,
-	ColorFgSleepingMonster,
-	ColorFgStatusBad,
-	ColorFgStatusGood,
-	ColorFgStatusExpire,
-	ColorFgStatusOther,
-	ColorFgWanderingMonster uicolor
-)
-
-func LinkColors() {
-	ColorBg = ColorBase03
-	ColorBgBorder = ColorBase02
-	ColorBgDark = ColorBase03
-	ColorBgLOS = ColorBase3
-	ColorFg = ColorBase0
-	ColorFgDark = ColorBase01
-	ColorFgLOS = ColorBase0
-	ColorFgLOSLight = ColorBase1
-	ColorFgObject = ColorYellow
-	ColorFgTree = ColorGreen
-	ColorFgConfusedMonster = ColorGreen
-	ColorFgLignifiedMonster = ColorYellow
-	ColorFgParalysedMonster = ColorCyan
-	ColorFgExcluded = ColorRed
-	ColorFgExplosionEnd = ColorOrange
-	ColorFgExplosionStart = ColorYellow
-	ColorFgExplosionWallEnd = ColorMagenta
-	ColorFgExplosionWallStart = ColorViolet
-	ColorFgHPcritical = ColorRed
-	ColorFgHPok = ColorGreen
-	ColorFgHPwounded = ColorYellow
-	ColorFgMPcritical = ColorMagenta
-	ColorFgMPok = ColorBlue
-	ColorFgMPpartial = ColorViolet
-	ColorFgMagicPlace = ColorCyan
-	ColorFgMonster = ColorRed
-	ColorFgPlace = ColorMagenta
-	ColorFgPlayer = ColorBlue
-	ColorFgBananas = ColorYellow
-	ColorFgSleepingMonster = ColorViolet
-	ColorFgStatusBad = ColorRed
-	ColorFgStatusGood = ColorBlue
-	ColorFgStatusExpire = ColorViolet
-	ColorFgStatusOther = ColorYellow
-	ColorFgWanderingMonster = ColorOrange
-}
-
-func ApplyDarkLOS() {
-	ColorBg = ColorBase03
-	ColorBgBorder = ColorBase02
-	ColorBgDark = ColorBase03
-	ColorBgLOS = ColorBase02
-	ColorFgDark = ColorBase01
-	ColorFg = ColorBase0
-	if Only8Colors {
-		ColorFgLOS = ColorGreen
-		ColorFgLOSLight = ColorYellow
+func (p *palette) LinkColors() {
+	p.ColorBg = p.ColorBase03
+	p.ColorBgBorder = p.ColorBase02
+	p.ColorBgDark = p.ColorBase03
+	p.ColorBgLOS = p.ColorBase3
+	p.ColorFg = p.ColorBase0
+	p.ColorFgDark = p.ColorBase01
+	p.ColorFgLOS = p.ColorBase0
+	p.ColorFgLOSLight = p.ColorBase1
+	p.ColorFgObject = p.ColorYellow
+	p.ColorFgTree = p.ColorGreen
+	p.ColorFgConfusedMonster = p.ColorGreen
+	p.ColorFgLignifiedMonster = p.ColorYellow
+	p.ColorFgParalysedMonster = p.ColorCyan
+	p.ColorFgExcluded = p.ColorRed
+	p.ColorFgExplosionEnd = p.ColorOrange
+	p.ColorFgExplosionStart = p.ColorYellow
+	p.ColorFgExplosionWallEnd = p.ColorMagenta
+	p.ColorFgExplosionWallStart = p.ColorViolet
+	p.ColorFgHPcritical = p.ColorRed
+	p.ColorFgHPok = p.ColorGreen
+	p.ColorFgHPwounded = p.ColorYellow
+	p.ColorFgMPcritical = p.ColorMagenta
+	p.ColorFgMPok = p.ColorBlue
+	p.ColorFgMPpartial = p.ColorViolet
+	p.ColorFgMagicPlace = p.ColorCyan
+	p.ColorFgMonster = p.ColorRed
+	p.ColorFgNote = p.ColorCyan
+	p.ColorFgPlace = p.ColorMagenta
+	p.ColorFgPlayer = p.ColorBlue
+	p.ColorFgBananas = p.ColorYellow
+	p.ColorFgSleepingMonster = p.ColorViolet
+	p.ColorFgStatusBad = p.ColorRed
+	p.ColorFgStatusGood = p.ColorBlue
+	p.ColorFgStatusExpire = p.ColorViolet
+	p.ColorFgStatusOther = p.ColorYellow
+	p.ColorFgWanderingMonster = p.ColorOrange
+}
+
+func (p *palette) ApplyDarkLOS() {
+	p.ColorBg = p.ColorBase03
+	p.ColorBgBorder = p.ColorBase02
+	p.ColorBgDark = p.ColorBase03
+	p.ColorBgLOS = p.ColorBase02
+	p.ColorFgDark = p.ColorBase01
+	p.ColorFg = p.ColorBase0
+	if p.Only8Colors {
+		p.ColorFgLOS = p.ColorGreen
+		p.ColorFgLOSLight = p.ColorYellow
 	} else {
-		ColorFgLOS = ColorBase0
-		//ColorFgLOSLight = ColorBase1
-		ColorFgLOSLight = ColorYellow
+		p.ColorFgLOS = p.ColorBase0
+		//p.ColorFgLOSLight = p.ColorBase1
+		p.ColorFgLOSLight = p.ColorYellow
 	}
 }
 
-func ApplyLightLOS() {
-	if Only8Colors {
-		ApplyDarkLOS()
-		ColorBgLOS = ColorBase2
-		ColorFgLOS = ColorBase00
+func (p *palette) ApplyLightLOS() {
+	if p.Only8Colors {
+		p.ApplyDarkLOS()
+		p.ColorBgLOS = p.ColorBase2
+		p.ColorFgLOS = p.ColorBase00
 	} else {
-		ColorBg = ColorBase3
-		ColorBgBorder = ColorBase2
-		ColorBgDark = ColorBase3
-		ColorBgLOS = ColorBase2
-		ColorFgDark = ColorBase1
-		ColorFgLOS = ColorBase00
-		ColorFg = ColorBase00
-	}
-}
-
-func SolarizedPalette() {
-	ColorBase03 = Color16Base03
-	ColorBase02 = Color16Base02
-	ColorBase01 = Color16Base01
-	ColorBase00 = Color16Base00
-	ColorBase0 = Color16Base0
-	ColorBase1 = Color16Base1
-	ColorBase2 = Color16Base2
-	ColorBase3 = Color16Base3
-	ColorYellow = Color16Yellow
-	ColorOrange = Color16Orange
-	ColorRed = Color16Red
-	ColorMagenta = Color16Magenta
-	ColorViolet = Color16Violet
-	ColorBlue = Color16Blue
-	ColorCyan = Color16Cyan
-	ColorGreen = Color16Green
+		p.ColorBg = p.ColorBase3
+		p.ColorBgBorder = p.ColorBase2
+		p.ColorBgDark = p.ColorBase3
+		p.ColorBgLOS = p.ColorBase2
+		p.ColorFgDark = p.ColorBase1
+		p.ColorFgLOS = p.ColorBase00
+		p.ColorFg = p.ColorBase00
+	}
+}
+
+func (p *palette) SolarizedPalette() {
+	p.ColorBase03 = Color16Base03
+	p.ColorBase02 = Color16Base02
+	p.ColorBase01 = Color16Base01
+	p.ColorBase00 = Color16Base00
+	p.ColorBase0 = Color16Base0
+	p.ColorBase1 = Color16Base1
+	p.ColorBase2 = Color16Base2
+	p.ColorBase3 = Color16Base3
+	p.ColorYellow = Color16Yellow
+	p.ColorOrange = Color16Orange
+	p.ColorRed = Color16Red
+	p.ColorMagenta = Color16Magenta
+	p.ColorViolet = Color16Violet
+	p.ColorBlue = Color16Blue
+	p.ColorCyan = Color16Cyan
+	p.ColorGreen = Color16Green
 }
 
 const (
@@ -331,10 +366,8 @@ func Map16ColorTo8Color(c uicolor) uicolor {
 	}
 }
 
-var Only8Colors bool
-
-func Simple8ColorPalette() {
-	Only8Colors = true
+func (p *palette) Simple8ColorPalette() {
+	p.Only8Colors = true
 }
 
 type drawFrame struct {
@@ -354,7 +387,7 @@ func (ui *gameui) SetCell(x, y int, r rune, fg, bg uicolor) {
 
 func (ui *gameui) SetGenCell(x, y int, r rune, fg, bg uicolor, inmap bool) {
 	i := ui.GetIndex(x, y)
-	if i >= UIHeight*UIWidth {
+	if i >= ui.UIHeight*ui.UIWidth {
 		return
 	}
 	c := UICell{R: r, Fg: fg, Bg: bg, InMap: inmap}
@@ -381,6 +414,9 @@ func (ui *gameui) DrawLogFrame() {
 		ui.g.DrawLog[last].Draws = append(ui.g.DrawLog[last].Draws, cdraw)
 		ui.g.drawBackBuffer[i] = c
 	}
+	if ui.spectators != nil {
+		ui.spectators.Send(ui.g.DrawLog[len(ui.g.DrawLog)-1])
+	}
 }
 
 func (ui *gameui) DrawWelcomeCommon() int {
@@ -394,98 +430,110 @@ func (ui *gameui) DrawWelcomeCommon() int {
 	p.NewLine()
 	p.DrawText(strings.Repeat("─", 23))
 	p.NewLine()
-	p.DrawDark(" #", ColorFgDark)
-	p.DrawLOS("##", ColorFgLOS)
-	p.DrawDark("###############", ColorViolet)
-	p.DrawDark("### ", ColorFgDark)
+	p.DrawDark(" #", ui.ColorFgDark)
+	p.DrawLOS("##", ui.ColorFgLOS)
+	p.DrawDark("###############", ui.ColorViolet)
+	p.DrawDark("### ", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawDark("#.", ColorFgDark)
-	p.DrawLOS("..", ColorFgLOSLight)
-	p.DrawLOS("#", ColorViolet)
+	p.DrawDark("#.", ui.ColorFgDark)
+	p.DrawLOS("..", ui.ColorFgLOSLight)
+	p.DrawLOS("#", ui.ColorViolet)
 	p.DrawText("  HARMONIST  ")
-	p.DrawDark("#", ColorViolet)
-	p.DrawDark(".", ColorFgDark)
-	p.DrawDark(")", ColorFgBananas)
-	p.DrawDark("t", ColorFgSleepingMonster)
-	p.DrawDark("#", ColorFgDark)
+	p.DrawDark("#", ui.ColorViolet)
+	p.DrawDark(".", ui.ColorFgDark)
+	p.DrawDark(")", ui.ColorFgBananas)
+	p.DrawDark("t", ui.ColorFgSleepingMonster)
+	p.DrawDark("#", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawDark("#.", ColorFgDark)
-	p.DrawLOS("b", ColorFgPlayer)
-	p.DrawLOS(".", ColorFgLOSLight)
-	p.DrawLOS("####", ColorViolet)
-	p.DrawDark("###########", ColorViolet)
-	p.DrawDark(".## ", ColorFgDark)
+	p.DrawDark("#.", ui.ColorFgDark)
+	p.DrawLOS("b", ui.ColorFgPlayer)
+	p.DrawLOS(".", ui.ColorFgLOSLight)
+	p.DrawLOS("####", ui.ColorViolet)
+	p.DrawDark("###########", ui.ColorViolet)
+	p.DrawDark(".## ", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawDark(" #", ColorFgDark)
-	p.DrawLOS("...", ColorFgLOSLight)
-	p.DrawLOS("...", ColorFgWanderingMonster)
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawDark("#", ColorFgDark)
-	p.DrawDark("π", ColorFgObject)
-	p.DrawDark(".", ColorFgDark)
-	p.DrawDark(">", ColorFgPlace)
-	p.DrawDark("##....", ColorFgDark)
-	p.DrawDark(".#  ", ColorFgDark)
+	p.DrawDark(" #", ui.ColorFgDark)
+	p.DrawLOS("...", ui.ColorFgLOSLight)
+	p.DrawLOS("...", ui.ColorFgWanderingMonster)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawDark("#", ui.ColorFgDark)
+	p.DrawDark("π", ui.ColorFgObject)
+	p.DrawDark(".", ui.ColorFgDark)
+	p.DrawDark(">", ui.ColorFgPlace)
+	p.DrawDark("##....", ui.ColorFgDark)
+	p.DrawDark(".#  ", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawDark(" ", ColorFgDark)
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawLOS("..", ColorFgLOSLight)
-	p.DrawLOS(".", ColorFgWanderingMonster)
-	p.DrawLOS("g", ColorFgWanderingMonster)
-	p.DrawLOS("..+", ColorFgWanderingMonster)
-	p.DrawDark("..", ColorFgDark)
-	p.DrawDark("G", ColorFgWanderingMonster)
-	p.DrawDark("..", ColorFgDark)
-	p.DrawDark("+", ColorFgPlace)
-	p.DrawDark("....", ColorFgDark)
-	p.DrawDark(".#  ", ColorFgDark)
+	p.DrawDark(" ", ui.ColorFgDark)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawLOS("..", ui.ColorFgLOSLight)
+	p.DrawLOS(".", ui.ColorFgWanderingMonster)
+	p.DrawLOS("g", ui.ColorFgWanderingMonster)
+	p.DrawLOS("..+", ui.ColorFgWanderingMonster)
+	p.DrawDark("..", ui.ColorFgDark)
+	p.DrawDark("G", ui.ColorFgWanderingMonster)
+	p.DrawDark("..", ui.ColorFgDark)
+	p.DrawDark("+", ui.ColorFgPlace)
+	p.DrawDark("....", ui.ColorFgDark)
+	p.DrawDark(".#  ", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawLOS("@", ColorFgPlayer)
-	p.DrawLOS(".", ColorFgLOSLight)
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawDark("≈", ColorFgDark)
-	p.DrawDark("♫", ColorFgWanderingMonster)
-	p.DrawDark("..##", ColorFgDark)
-	p.DrawDark("☼", ColorFgObject)
-	p.DrawDark(".", ColorFgDark)
-	p.DrawDark("&", ColorFgObject)
-	p.DrawDark("##..", ColorFgDark)
-	p.DrawDark("♣", ColorFgTree)
-	p.DrawDark(".\".##", ColorFgDark)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawLOS("@", ui.ColorFgPlayer)
+	p.DrawLOS(".", ui.ColorFgLOSLight)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawDark("≈", ui.ColorFgDark)
+	p.DrawDark("♫", ui.ColorFgWanderingMonster)
+	p.DrawDark("..##", ui.ColorFgDark)
+	p.DrawDark("☼", ui.ColorFgObject)
+	p.DrawDark(".", ui.ColorFgDark)
+	p.DrawDark("&", ui.ColorFgObject)
+	p.DrawDark("##..", ui.ColorFgDark)
+	p.DrawDark("♣", ui.ColorFgTree)
+	p.DrawDark(".\".##", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawLOS(".", ColorFgLOSLight)
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawDark("#≈≈≈..##", ColorFgDark)
-	p.DrawDark("+", ColorFgPlace)
-	p.DrawDark("##..", ColorFgDark)
-	p.DrawDark("h", ColorFgWanderingMonster)
-	p.DrawDark(".\"#.", ColorFgDark)
-	p.DrawDark("_", ColorFgMagicPlace)
-	p.DrawDark("#", ColorFgDark)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawLOS(".", ui.ColorFgLOSLight)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawDark("#≈≈≈..##", ui.ColorFgDark)
+	p.DrawDark("+", ui.ColorFgPlace)
+	p.DrawDark("##..", ui.ColorFgDark)
+	p.DrawDark("h", ui.ColorFgWanderingMonster)
+	p.DrawDark(".\"#.", ui.ColorFgDark)
+	p.DrawDark("_", ui.ColorFgMagicPlace)
+	p.DrawDark("#", ui.ColorFgDark)
 	p.NewLine()
-	p.DrawLOS("#", ColorFgLOS)
-	p.DrawLOS("..", ColorFgLOSLight)
-	p.DrawDark("##≈≈≈.........\"\"\"\"##", ColorFgDark)
+	p.DrawLOS("#", ui.ColorFgLOS)
+	p.DrawLOS("..", ui.ColorFgLOSLight)
+	p.DrawDark("##≈≈≈.........\"\"\"\"##", ui.ColorFgDark)
 	p.NewLine()
 	p.DrawText(strings.Repeat("─", 23))
 	p.NewLine()
 	line = p.line
 	line++
-	if runtime.GOARCH == "wasm" {
-		ui.DrawDark("- (P)lay", col-3, line, ColorFg, false)
-		ui.DrawDark("- (W)atch replay", col-3, line+1, ColorFg, false)
-	} else {
-		ui.DrawDark("───Press any key to continue───", col-3, line, ColorFg, false)
+	actions := StartMenuActions()
+	for i, a := range actions {
+		ui.DrawDark("- "+a.String(), col-3, line+i, ui.ColorFg, false)
+	}
+	if runtime.GOARCH != "wasm" {
+		ui.DrawDark("───Press any other key to play───", col-3, line+len(actions)+1, ui.ColorFg, false)
 	}
 	ui.Flush()
 	return line
 }
 
-func (ui *gameui) DrawWelcome() {
-	ui.DrawWelcomeCommon()
-	ui.PressAnyKey()
+// DrawWelcome draws the welcome screen with the start menu, and returns true
+// if the player chose to start the daily challenge.
+func (ui *gameui) DrawWelcome() (daily bool) {
+	for {
+		l := ui.DrawWelcomeCommon()
+		switch ui.StartMenu(l) {
+		case StartDaily:
+			if ui.DailyMenu() {
+				return true
+			}
+		default:
+			return false
+		}
+	}
 }
 
 func (ui *gameui) RestartDrawBuffers() {
@@ -507,9 +555,9 @@ func (ui *gameui) DrawDark(text string, x, y int, fg uicolor, inmap bool) int {
 	col := 0
 	for _, r := range text {
 		if inmap {
-			ui.SetMapCell(x+col, y, r, fg, ColorBgDark)
+			ui.SetMapCell(x+col, y, r, fg, ui.ColorBgDark)
 		} else {
-			ui.SetCell(x+col, y, r, fg, ColorBgDark)
+			ui.SetCell(x+col, y, r, fg, ui.ColorBgDark)
 		}
 		col++
 	}
@@ -532,7 +580,7 @@ func (p *pencil) DrawDark(text string, fg uicolor) {
 }
 
 func (p *pencil) DrawText(text string) {
-	p.col += p.ui.DrawDark(text, p.col, p.line, ColorGreen, false)
+	p.col += p.ui.DrawDark(text, p.col, p.line, p.ui.ColorGreen, false)
 }
 
 func (p *pencil) NewLine() {
@@ -544,9 +592,9 @@ func (ui *gameui) DrawLOS(text string, x, y int, fg uicolor, inmap bool) int {
 	col := 0
 	for _, r := range text {
 		if inmap {
-			ui.SetMapCell(x+col, y, r, fg, ColorBgLOS)
+			ui.SetMapCell(x+col, y, r, fg, ui.ColorBgLOS)
 		} else {
-			ui.SetCell(x+col, y, r, fg, ColorBgLOS)
+			ui.SetCell(x+col, y, r, fg, ui.ColorBgLOS)
 		}
 		col++
 	}
@@ -556,7 +604,7 @@ func (ui *gameui) DrawLOS(text string, x, y int, fg uicolor, inmap bool) int {
 func (ui *gameui) DrawKeysDescription(title string, actions []string) {
 	ui.DrawDungeonView(NoFlushMode)
 
-	if CustomKeys {
+	if ui.CustomKeys {
 		ui.DrawStyledTextLine(fmt.Sprintf(" Default %s ", title), 0, HeaderLine)
 	} else {
 		ui.DrawStyledTextLine(fmt.Sprintf(" %s ", title), 0, HeaderLine)
@@ -565,7 +613,7 @@ func (ui *gameui) DrawKeysDescription(title string, actions []string) {
 		if actions[i+1] != "" {
 			bg := ui.ListItemBG(i / 2)
 			ui.ClearLineWithColor(i/2+1, bg)
-			ui.DrawColoredTextOnBG(fmt.Sprintf(" %-36s %s", actions[i], actions[i+1]), 0, i/2+1, ColorFg, bg)
+			ui.DrawColoredTextOnBG(fmt.Sprintf(" %-36s %s", actions[i], actions[i+1]), 0, i/2+1, ui.ColorFg, bg)
 		} else {
 			ui.DrawStyledTextLine(fmt.Sprintf(" %s ", actions[i]), i/2+1, HeaderLine)
 		}
@@ -590,6 +638,7 @@ func (ui *gameui) KeysHelp() {
 		"Save and Quit", "S",
 		"View previous messages", "m",
 		"Go to nearest stairs", "G",
+		"Travel to landmark", "T",
 		"Autoexplore (use with caution)", "o",
 		"Write game statistics to file", "#",
 		"Quit without saving", "Q",
@@ -606,6 +655,8 @@ func (ui *gameui) ExamineHelp() {
 		"Cycle through stairs", ">",
 		"Cycle through objects", "o",
 		"Toggle exclude area from auto-travel", "e or mouse middle",
+		"Write or remove map note", "n",
+		"Cycle through map notes", "N",
 	})
 }
 
@@ -638,6 +689,9 @@ func (ui *gameui) DescribePosition(pos position, targ Targeter) {
 		if g.Noise[pos] || g.NoiseIllusion[pos] {
 			desc += " Noise."
 		}
+		if note, ok := g.Notes[pos]; ok {
+			desc += fmt.Sprintf(" Note: “%s”.", note)
+		}
 		g.InfoEntry = desc
 		return
 	case !targ.Reachable(g, pos):
@@ -683,6 +737,9 @@ func (ui *gameui) DescribePosition(pos position, targ Targeter) {
 	if g.Noise[pos] || g.NoiseIllusion[pos] {
 		desc += ". Noise"
 	}
+	if note, ok := g.Notes[pos]; ok {
+		desc += fmt.Sprintf(". Note: “%s”", note)
+	}
 	g.InfoEntry = desc + "."
 }
 
@@ -726,10 +783,8 @@ func (ui *gameui) MonsterInfo(m *monster) string {
 	return strings.Join(infos, ", ")
 }
 
-var CenteredCamera bool
-
 func (ui *gameui) MapWidth() int {
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		//return DefaultLOSRange*2 + 5
 		return 55
 	}
@@ -781,7 +836,7 @@ func (ui *gameui) DrawAtPosition(pos position, targeting bool, r rune, fg, bg ui
 	if g.Highlight[pos] || pos == ui.cursor {
 		bg, fg = fg, bg
 	}
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		if !ui.InView(pos, targeting) {
 			return
 		}
@@ -793,7 +848,7 @@ func (ui *gameui) DrawAtPosition(pos position, targeting bool, r rune, fg, bg ui
 				if xo < 0 || xo >= ui.MapWidth() || yo < 0 || yo >= ui.MapHeight() {
 					continue
 				}
-				ui.SetMapCell(xo, yo, '#', ColorFg, ColorBgBorder)
+				ui.SetMapCell(xo, yo, '#', ui.ColorFg, ui.ColorBgBorder)
 			}
 		}
 		return
@@ -806,24 +861,24 @@ func (ui *gameui) DrawDungeonView(m uiMode) {
 	ui.Clear()
 	d := g.Dungeon
 	for i := 0; i < ui.MapWidth(); i++ {
-		ui.SetCell(i, ui.MapHeight(), '─', ColorFg, ColorBg)
+		ui.SetCell(i, ui.MapHeight(), '─', ui.ColorFg, ui.ColorBg)
 	}
 	for i := 0; i < ui.MapHeight(); i++ {
-		ui.SetCell(ui.MapWidth(), i, '│', ColorFg, ColorBg)
+		ui.SetCell(ui.MapWidth(), i, '│', ui.ColorFg, ui.ColorBg)
 	}
-	ui.SetCell(ui.MapWidth(), ui.MapHeight(), '┘', ColorFg, ColorBg)
-	if CenteredCamera {
+	ui.SetCell(ui.MapWidth(), ui.MapHeight(), '┘', ui.ColorFg, ui.ColorBg)
+	if ui.CenteredCamera {
 		for i := 0; i < DungeonWidth; i++ {
-			ui.SetCell(i, ui.MapHeight(), '─', ColorFg, ColorBg)
+			ui.SetCell(i, ui.MapHeight(), '─', ui.ColorFg, ui.ColorBg)
 		}
 		for i := 0; i < ui.MapHeight(); i++ {
-			ui.SetCell(DungeonWidth, i, '│', ColorFg, ColorBg)
+			ui.SetCell(DungeonWidth, i, '│', ui.ColorFg, ui.ColorBg)
 		}
-		ui.SetCell(DungeonWidth, ui.MapHeight(), '┘', ColorFg, ColorBg)
+		ui.SetCell(DungeonWidth, ui.MapHeight(), '┘', ui.ColorFg, ui.ColorBg)
 	}
 	for i := range d.Cells {
 		pos := idxtopos(i)
-		if CenteredCamera {
+		if ui.CenteredCamera {
 			x, y := ui.CameraOffset(pos, m == TargetingMode)
 			if x < 0 || x >= ui.MapWidth() || y < 0 || y >= ui.MapHeight() {
 				continue
@@ -855,30 +910,30 @@ func (ui *gameui) DrawDungeonView(m uiMode) {
 
 func (ui *gameui) DrawKeysBasics(m uiMode) {
 	line := ui.MapHeight() - 3
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		line -= 5
 	}
 	if m == TargetingMode {
-		ui.SetCell(ui.MapWidth()+3, line, '↑', ColorFgPlayer, ColorBg)
-		ui.DrawColoredText("←.→", ui.MapWidth()+2, line+1, ColorFgPlayer)
-		ui.DrawColoredText(" ↓ ", ui.MapWidth()+2, line+2, ColorFgPlayer)
-		ui.SetCell(ui.MapWidth()+2, line+3, 'v', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+4, 'x', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+5, '?', ColorFgPlayer, ColorBg)
+		ui.SetCell(ui.MapWidth()+3, line, '↑', ui.ColorFgPlayer, ui.ColorBg)
+		ui.DrawColoredText("←.→", ui.MapWidth()+2, line+1, ui.ColorFgPlayer)
+		ui.DrawColoredText(" ↓ ", ui.MapWidth()+2, line+2, ui.ColorFgPlayer)
+		ui.SetCell(ui.MapWidth()+2, line+3, 'v', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+4, 'x', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+5, '?', ui.ColorFgPlayer, ui.ColorBg)
 		const margin = 6
 		ui.DrawText("move cursor/go", ui.MapWidth()+margin, line+1)
 		ui.DrawText("view info", ui.MapWidth()+margin, line+3)
 		ui.DrawText("close mode", ui.MapWidth()+margin, line+4)
 		ui.DrawText("examine help", ui.MapWidth()+margin, line+5)
 	} else if m == NormalMode || m == AnimationMode {
-		ui.SetCell(ui.MapWidth()+3, line, '↑', ColorFgPlayer, ColorBg)
-		ui.DrawColoredText("←.→", ui.MapWidth()+2, line+1, ColorFgPlayer)
-		ui.DrawColoredText(" ↓ ", ui.MapWidth()+2, line+2, ColorFgPlayer)
-		ui.SetCell(ui.MapWidth()+2, line+3, 'e', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+4, 'v', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+5, 'i', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+6, 'x', ColorFgPlayer, ColorBg)
-		ui.SetCell(ui.MapWidth()+2, line+7, '?', ColorFgPlayer, ColorBg)
+		ui.SetCell(ui.MapWidth()+3, line, '↑', ui.ColorFgPlayer, ui.ColorBg)
+		ui.DrawColoredText("←.→", ui.MapWidth()+2, line+1, ui.ColorFgPlayer)
+		ui.DrawColoredText(" ↓ ", ui.MapWidth()+2, line+2, ui.ColorFgPlayer)
+		ui.SetCell(ui.MapWidth()+2, line+3, 'e', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+4, 'v', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+5, 'i', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+6, 'x', ui.ColorFgPlayer, ui.ColorBg)
+		ui.SetCell(ui.MapWidth()+2, line+7, '?', ui.ColorFgPlayer, ui.ColorBg)
 		const margin = 6
 		ui.DrawText("move/jump/wait", ui.MapWidth()+margin, line+1)
 		ui.DrawText("interact", ui.MapWidth()+margin, line+3)
@@ -896,22 +951,22 @@ func (ui *gameui) DrawLoading() {
 func (ui *gameui) DrawMessage(s string) {
 	ui.DrawDungeonView(NoFlushMode)
 	line := ui.MapHeight() - 2
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		line = ui.MapHeight() - 5
 	}
-	ui.DrawColoredText(s, ui.MapWidth()+2, line+1, ColorCyan)
+	ui.DrawColoredText(s, ui.MapWidth()+2, line+1, ui.ColorCyan)
 	ui.Flush()
 	Sleep(AnimDurShort)
 }
 
 func (ui *gameui) DrawSelectDescBasics() {
 	line := ui.MapHeight() - 2
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		line = ui.MapHeight() - 5
 	}
-	ui.DrawColoredText("[a-z]", ui.MapWidth()+2, line+1, ColorFgPlayer)
-	ui.SetCell(ui.MapWidth()+2, line+2, '?', ColorFgPlayer, ColorBg)
-	ui.SetCell(ui.MapWidth()+2, line+3, 'x', ColorFgPlayer, ColorBg)
+	ui.DrawColoredText("[a-z]", ui.MapWidth()+2, line+1, ui.ColorFgPlayer)
+	ui.SetCell(ui.MapWidth()+2, line+2, '?', ui.ColorFgPlayer, ui.ColorBg)
+	ui.SetCell(ui.MapWidth()+2, line+3, 'x', ui.ColorFgPlayer, ui.ColorBg)
 	const margin = 7
 	ui.DrawText("select", ui.MapWidth()+margin, line+1)
 	ui.DrawText("use/desc", ui.MapWidth()+margin, line+2)
@@ -920,11 +975,11 @@ func (ui *gameui) DrawSelectDescBasics() {
 
 func (ui *gameui) DrawSelectBasics() {
 	line := ui.MapHeight() - 2
-	if CenteredCamera {
+	if ui.CenteredCamera {
 		line = ui.MapHeight() - 5
 	}
-	ui.DrawColoredText("[a-z]", ui.MapWidth()+2, line+1, ColorFgPlayer)
-	ui.SetCell(ui.MapWidth()+2, line+2, 'x', ColorFgPlayer, ColorBg)
+	ui.DrawColoredText("[a-z]", ui.MapWidth()+2, line+1, ui.ColorFgPlayer)
+	ui.SetCell(ui.MapWidth()+2, line+2, 'x', ui.ColorFgPlayer, ui.ColorBg)
 	const margin = 7
 	ui.DrawText("select", ui.MapWidth()+margin, line+1)
 	ui.DrawText("close", ui.MapWidth()+margin, line+2)
@@ -934,33 +989,37 @@ func (ui *gameui) PositionDrawing(pos position) (r rune, fgColor, bgColor uicolo
 	g := ui.g
 	m := g.Dungeon
 	c := m.Cell(pos)
-	fgColor = ColorFg
-	bgColor = ColorBg
+	fgColor = ui.ColorFg
+	bgColor = ui.ColorBg
 	if !c.Explored && (!g.Wizard || g.WizardMode == WizardNormal) {
 		r = ' '
-		bgColor = ColorBgDark
+		bgColor = ui.ColorBgDark
 		if g.HasNonWallExploredNeighbor(pos) {
 			r = '¤'
-			fgColor = ColorFgDark
+			fgColor = ui.ColorFgDark
 		}
 		if mons, ok := g.LastMonsterKnownAt[pos]; ok && !mons.Seen {
 			r = '☻'
-			fgColor = ColorFgSleepingMonster
+			fgColor = ui.ColorFgSleepingMonster
+		}
+		if _, ok := g.Notes[pos]; ok {
+			r = '¤'
+			fgColor = ui.ColorFgNote
 		}
 		if g.Noise[pos] {
 			r = '♫'
-			fgColor = ColorFgWanderingMonster
+			fgColor = ui.ColorFgWanderingMonster
 		} else if g.NoiseIllusion[pos] {
 			r = '♪'
-			fgColor = ColorFgMagicPlace
+			fgColor = ui.ColorFgMagicPlace
 		}
 		return
 	}
 	if g.Wizard && g.WizardMode != WizardNormal {
 		if !c.Explored && g.HasNonWallExploredNeighbor(pos) && g.WizardMode == WizardSeeAll {
 			r = '¤'
-			fgColor = ColorFgDark
-			bgColor = ColorBgDark
+			fgColor = ui.ColorFgDark
+			bgColor = ui.ColorBgDark
 			return
 		}
 		if c.T == WallCell {
@@ -971,52 +1030,55 @@ func (ui *gameui) PositionDrawing(pos position) (r rune, fgColor, bgColor uicolo
 		}
 	}
 	if g.Player.Sees(pos) && !(g.Wizard && g.WizardMode == WizardMap) {
-		fgColor = ColorFgLOS
-		bgColor = ColorBgLOS
+		fgColor = ui.ColorFgLOS
+		bgColor = ui.ColorBgLOS
 	} else {
-		fgColor = ColorFgDark
-		bgColor = ColorBgDark
+		fgColor = ui.ColorFgDark
+		bgColor = ui.ColorBgDark
 	}
 	if g.ExclusionsMap[pos] && c.T.IsPlayerPassable() {
-		fgColor = ColorFgExcluded
+		fgColor = ui.ColorFgExcluded
 	}
 	if trkn, okTrkn := g.TerrainKnowledge[pos]; okTrkn && (!g.Wizard || g.WizardMode == WizardNormal) {
 		c.T = trkn
 	}
+	if _, ok := g.Notes[pos]; ok && c.T.IsPlayerPassable() {
+		fgColor = ui.ColorFgNote
+	}
 	var fgTerrain uicolor
 	switch {
 	case c.CoversPlayer():
 		r, fgTerrain = c.Style(g, pos)
 		if pos == g.Player.Pos {
-			fgColor = ColorFgPlayer
-		} else if fgTerrain != ColorFgLOS {
+			fgColor = ui.ColorFgPlayer
+		} else if fgTerrain != ui.ColorFgLOS {
 			fgColor = fgTerrain
 		}
 		if _, ok := g.MagicalBarriers[pos]; ok {
-			fgColor = ColorFgMagicPlace
+			fgColor = ui.ColorFgMagicPlace
 		}
 	case pos == g.Player.Pos && !(g.Wizard && g.WizardMode == WizardMap):
 		r = '@'
-		fgColor = ColorFgPlayer
+		fgColor = ui.ColorFgPlayer
 	default:
 		// TODO: maybe some wrong knowledge issues
 		r, fgTerrain = c.Style(g, pos)
-		if fgTerrain != ColorFgLOS {
+		if fgTerrain != ui.ColorFgLOS {
 			fgColor = fgTerrain
 		}
 		if g.MonsterTargLOS != nil {
 			if g.MonsterTargLOS[pos] {
-				fgColor = ColorFgWanderingMonster
+				fgColor = ui.ColorFgWanderingMonster
 			}
 		} else if g.MonsterLOS[pos] {
-			fgColor = ColorFgWanderingMonster
+			fgColor = ui.ColorFgWanderingMonster
 		}
 		if cld, ok := g.Clouds[pos]; ok && g.Player.Sees(pos) {
 			r = '§'
 			if cld == CloudFire {
-				fgColor = ColorFgWanderingMonster
+				fgColor = ui.ColorFgWanderingMonster
 			} else if cld == CloudNight {
-				fgColor = ColorFgSleepingMonster
+				fgColor = ui.ColorFgSleepingMonster
 			}
 		}
 		if g.Player.Sees(pos) || (g.Wizard && g.WizardMode == WizardSeeAll) {
@@ -1024,44 +1086,49 @@ func (ui *gameui) PositionDrawing(pos position) (r rune, fgColor, bgColor uicolo
 			if m.Exists() {
 				r = m.Kind.Letter()
 				if m.Status(MonsLignified) {
-					fgColor = ColorFgLignifiedMonster
+					fgColor = ui.ColorFgLignifiedMonster
 				} else if m.Status(MonsConfused) {
-					fgColor = ColorFgConfusedMonster
+					fgColor = ui.ColorFgConfusedMonster
 				} else if m.Status(MonsParalysed) {
-					fgColor = ColorFgParalysedMonster
+					fgColor = ui.ColorFgParalysedMonster
 				} else if m.State == Resting {
-					fgColor = ColorFgSleepingMonster
+					fgColor = ui.ColorFgSleepingMonster
 				} else if m.State == Hunting {
-					fgColor = ColorFgMonster
+					fgColor = ui.ColorFgMonster
 				} else if m.Peaceful(g) {
-					fgColor = ColorFgPlayer
+					fgColor = ui.ColorFgPlayer
 				} else {
-					fgColor = ColorFgWanderingMonster
+					fgColor = ui.ColorFgWanderingMonster
 				}
 			}
 		} else if (!g.Wizard || g.WizardMode == WizardNormal) && g.Noise[pos] {
 			r = '♫'
-			fgColor = ColorFgWanderingMonster
+			fgColor = ui.ColorFgWanderingMonster
 		} else if g.NoiseIllusion[pos] {
 			r = '♪'
-			fgColor = ColorFgMagicPlace
+			fgColor = ui.ColorFgMagicPlace
 		} else if mons, ok := g.LastMonsterKnownAt[pos]; (!g.Wizard || g.WizardMode == WizardNormal) && ok {
 			if !mons.Seen {
 				r = '☻'
-				fgColor = ColorFgWanderingMonster
+				fgColor = ui.ColorFgWanderingMonster
 			} else {
 				r = mons.Kind.Letter()
 				if mons.LastSeenState == Resting {
-					fgColor = ColorFgSleepingMonster
+					fgColor = ui.ColorFgSleepingMonster
 				} else if mons.Kind.Peaceful() {
-					fgColor = ColorFgPlayer
+					fgColor = ui.ColorFgPlayer
 				} else {
-					fgColor = ColorFgWanderingMonster
+					fgColor = ui.ColorFgWanderingMonster
 				}
 			}
 		}
-		if fgColor == ColorFgLOS && g.Illuminated[pos.idx()] && c.IsIlluminable() {
-			fgColor = ColorFgLOSLight
+		if s := g.ScentAt(pos); g.Wizard && g.WizardScent && s > 0 && !g.MonsterAt(pos).Exists() {
+			// scent strength from 0 to 9
+			r = rune('0' + 9*s/ScentDuration)
+			fgColor = ui.ColorFgMagicPlace
+		}
+		if fgColor == ui.ColorFgLOS && g.Illuminated[pos.idx()] && c.IsIlluminable() {
+			fgColor = ui.ColorFgLOSLight
 		}
 	}
 	return
@@ -1093,10 +1160,10 @@ func (ui *gameui) DrawStatusBar(line int) {
 	if hp < 0 {
 		hp = 0
 	}
-	if !GameConfig.ShowNumbers {
+	if !g.config.ShowNumbers {
 		ui.DrawColoredText(strings.Repeat("♥", hp), BarCol+4, line, hpColor)
-		ui.DrawColoredText(strings.Repeat("♥", g.Player.HPbonus), BarCol+4+hp, line, ColorCyan) // TODO: define color variables
-		ui.DrawColoredText(strings.Repeat("♥", nWounds), BarCol+4+hp+g.Player.HPbonus, line, ColorFg)
+		ui.DrawColoredText(strings.Repeat("♥", g.Player.HPbonus), BarCol+4+hp, line, ui.ColorCyan) // TODO: define color variables
+		ui.DrawColoredText(strings.Repeat("♥", nWounds), BarCol+4+hp+g.Player.HPbonus, line, ui.ColorFg)
 	} else {
 		if g.Player.HPbonus > 0 {
 			ui.DrawColoredText(fmt.Sprintf("%d+%d/%d", hp, g.Player.HPbonus, g.Player.HPMax()), BarCol+4, line, hpColor)
@@ -1114,9 +1181,9 @@ func (ui *gameui) DrawStatusBar(line int) {
 		MPspent = 0
 	}
 	ui.DrawColoredText("MP: ", BarCol, line, mpColor)
-	if !GameConfig.ShowNumbers {
+	if !g.config.ShowNumbers {
 		ui.DrawColoredText(strings.Repeat("♥", g.Player.MP), BarCol+4, line, mpColor)
-		ui.DrawColoredText(strings.Repeat("♥", MPspent), BarCol+4+g.Player.MP, line, ColorFg)
+		ui.DrawColoredText(strings.Repeat("♥", MPspent), BarCol+4+g.Player.MP, line, ui.ColorFg)
 	} else {
 		ui.DrawColoredText(fmt.Sprintf("%d/%d", g.Player.MP, g.Player.MPMax()), BarCol+4, line, mpColor)
 	}
@@ -1128,22 +1195,30 @@ func (ui *gameui) DrawStatusBar(line int) {
 	if g.Depth == -1 {
 		ui.DrawText("Depth: Out!", BarCol, line)
 	} else {
-		ui.DrawText(fmt.Sprintf("Depth: %d/%d", g.Depth, MaxDepth), BarCol, line)
+		if g.Params.Plan.Endless {
+			ui.DrawText(fmt.Sprintf("Depth: %d/∞", g.Depth), BarCol, line)
+		} else {
+			ui.DrawText(fmt.Sprintf("Depth: %d/%d", g.Depth, MaxDepth), BarCol, line)
+		}
 	}
 	line++
 	ui.DrawText(fmt.Sprintf("Turns: %d", g.Turn), BarCol, line)
 	line++
+	if g.LastNoise > 0 {
+		ui.DrawColoredText(fmt.Sprintf("Noise: %d", g.LastNoise), BarCol, line, ui.ColorFgWanderingMonster)
+		line++
+	}
 	for _, st := range sts {
-		fg := ColorFgStatusOther
+		fg := ui.ColorFgStatusOther
 		if st.Good() {
-			fg = ColorFgStatusGood
+			fg = ui.ColorFgStatusGood
 			t := DurationTurn
 			exp, ok := g.Player.Expire[st]
 			if ok && exp >= g.Ev.Rank() && exp-g.Ev.Rank() <= t {
-				fg = ColorFgStatusExpire
+				fg = ui.ColorFgStatusExpire
 			}
 		} else if st.Bad() {
-			fg = ColorFgStatusBad
+			fg = ui.ColorFgStatusBad
 		}
 		if !st.Flag() {
 			ui.DrawColoredText(fmt.Sprintf("%s(%d)", st, g.Player.Statuses[st]/DurationStatusStep), BarCol, line, fg)
@@ -1156,24 +1231,24 @@ func (ui *gameui) DrawStatusBar(line int) {
 
 func (ui *gameui) HPColor() uicolor {
 	g := ui.g
-	hpColor := ColorFgHPok
+	hpColor := ui.ColorFgHPok
 	switch g.Player.HP + g.Player.HPbonus {
 	case 1, 2:
-		hpColor = ColorFgHPcritical
+		hpColor = ui.ColorFgHPcritical
 	case 3, 4:
-		hpColor = ColorFgHPwounded
+		hpColor = ui.ColorFgHPwounded
 	}
 	return hpColor
 }
 
 func (ui *gameui) MPColor() uicolor {
 	g := ui.g
-	mpColor := ColorFgMPok
+	mpColor := ui.ColorFgMPok
 	switch g.Player.MP {
 	case 1, 2:
-		mpColor = ColorFgMPcritical
+		mpColor = ui.ColorFgMPcritical
 	case 3, 4:
-		mpColor = ColorFgMPpartial
+		mpColor = ui.ColorFgMPpartial
 	}
 	return mpColor
 }
@@ -1220,12 +1295,12 @@ func (ui *gameui) DrawStatusLine() {
 	if hp < 0 {
 		hp = 0
 	}
-	if !GameConfig.ShowNumbers {
+	if !g.config.ShowNumbers {
 		ui.DrawColoredText(strings.Repeat("♥", hp), col, line, hpColor)
 		col += hp
-		ui.DrawColoredText(strings.Repeat("♥", g.Player.HPbonus), col, line, ColorCyan) // TODO: define color variables
+		ui.DrawColoredText(strings.Repeat("♥", g.Player.HPbonus), col, line, ui.ColorCyan) // TODO: define color variables
 		col += g.Player.HPbonus
-		ui.DrawColoredText(strings.Repeat("♥", nWounds), col, line, ColorFg)
+		ui.DrawColoredText(strings.Repeat("♥", nWounds), col, line, ui.ColorFg)
 		col += nWounds
 	} else {
 		if g.Player.HPbonus > 0 {
@@ -1243,11 +1318,11 @@ func (ui *gameui) DrawStatusLine() {
 	}
 	mpColor := ui.MPColor()
 	ui.DrawColoredText(" MP:", col, line, mpColor)
-	if !GameConfig.ShowNumbers {
+	if !g.config.ShowNumbers {
 		col += 4
 		ui.DrawColoredText(strings.Repeat("♥", g.Player.MP), col, line, mpColor)
 		col += g.Player.MP
-		ui.DrawColoredText(strings.Repeat("♥", MPspent), col, line, ColorFg)
+		ui.DrawColoredText(strings.Repeat("♥", MPspent), col, line, ui.ColorFg)
 		col += MPspent
 	} else {
 		col += 4
@@ -1255,12 +1330,12 @@ func (ui *gameui) DrawStatusLine() {
 		col += 3
 	}
 
-	ui.SetMapCell(col, line, ' ', ColorFg, ColorBg)
+	ui.SetMapCell(col, line, ' ', ui.ColorFg, ui.ColorBg)
 	col++
-	ui.SetMapCell(col, line, ')', ColorYellow, ColorBg)
+	ui.SetMapCell(col, line, ')', ui.ColorYellow, ui.ColorBg)
 	col++
 	banana := fmt.Sprintf(":%1d/%1d ", g.Player.Bananas, MaxBananas)
-	ui.DrawColoredText(banana, col, line, ColorFg)
+	ui.DrawColoredText(banana, col, line, ui.ColorFg)
 	col += utf8.RuneCountInString(banana)
 
 	if len(sts) > 0 {
@@ -1268,15 +1343,15 @@ func (ui *gameui) DrawStatusLine() {
 		col += 2
 	}
 	for _, st := range sts {
-		fg := ColorFgStatusOther
+		fg := ui.ColorFgStatusOther
 		if st.Good() {
-			fg = ColorFgStatusGood
+			fg = ui.ColorFgStatusGood
 			t := DurationTurn
 			if g.Player.Expire[st] >= g.Ev.Rank() && g.Player.Expire[st]-g.Ev.Rank() <= t {
-				fg = ColorFgStatusExpire
+				fg = ui.ColorFgStatusExpire
 			}
 		} else if st.Bad() {
-			fg = ColorFgStatusBad
+			fg = ui.ColorFgStatusBad
 		}
 		var sttext string
 		if !st.Flag() {
@@ -1290,20 +1365,20 @@ func (ui *gameui) DrawStatusLine() {
 }
 
 func (ui *gameui) LogColor(e logEntry) uicolor {
-	fg := ColorFg
+	fg := ui.ColorFg
 	switch e.Style {
 	case logCritic:
-		fg = ColorRed
+		fg = ui.ColorRed
 	case logPlayerHit:
-		fg = ColorGreen
+		fg = ui.ColorGreen
 	case logMonsterHit:
-		fg = ColorOrange
+		fg = ui.ColorOrange
 	case logSpecial:
-		fg = ColorMagenta
+		fg = ui.ColorMagenta
 	case logStatusEnd:
-		fg = ColorViolet
+		fg = ui.ColorViolet
 	case logError:
-		fg = ColorRed
+		fg = ui.ColorRed
 	}
 	return fg
 }
@@ -1347,7 +1422,7 @@ func (ui *gameui) DrawLog(lines int) {
 			e := g.Log[ln]
 			fguicolor := ui.LogColor(e)
 			if e.Tick {
-				ui.DrawColoredText("•", 0, ui.MapHeight()+i, ColorYellow)
+				ui.DrawColoredText("•", 0, ui.MapHeight()+i, ui.ColorYellow)
 				col += 2
 			}
 			ui.DrawColoredText(e.String(), col, ui.MapHeight()+i, fguicolor)
@@ -1368,12 +1443,12 @@ func InRuneSlice(r rune, s []rune) bool {
 
 func (ui *gameui) RunesForKeyAction(k action) string {
 	runes := []rune{}
-	for r, ka := range GameConfig.RuneNormalModeKeys {
+	for r, ka := range ui.g.config.RuneNormalModeKeys {
 		if k == ka && !InRuneSlice(r, runes) {
 			runes = append(runes, r)
 		}
 	}
-	for r, ka := range GameConfig.RuneTargetModeKeys {
+	for r, ka := range ui.g.config.RuneTargetModeKeys {
 		if k == ka && !InRuneSlice(r, runes) {
 			runes = append(runes, r)
 		}
@@ -1422,9 +1497,9 @@ loop:
 			ui.ClearLineWithColor(i-n, bg)
 			desc = fmt.Sprintf(" %-36s %s", desc, ui.RunesForKeyAction(ka))
 			if i == s {
-				ui.DrawColoredTextOnBG(desc, 0, i-n, ColorYellow, bg)
+				ui.DrawColoredTextOnBG(desc, 0, i-n, ui.ColorYellow, bg)
 			} else {
-				ui.DrawColoredTextOnBG(desc, 0, i-n, ColorFg, bg)
+				ui.DrawColoredTextOnBG(desc, 0, i-n, ui.ColorFg, bg)
 			}
 		}
 		ui.ClearLine(lines)
@@ -1457,17 +1532,17 @@ loop:
 				g.Printf("You cannot rebind “%c”.", r)
 				continue loop
 			}
-			CustomKeys = true
+			ui.CustomKeys = true
 			ka := ConfigurableKeyActions[s]
 			if ka.NormalModeAction() {
-				GameConfig.RuneNormalModeKeys[r] = ka
+				g.config.RuneNormalModeKeys[r] = ka
 			} else {
-				delete(GameConfig.RuneNormalModeKeys, r)
+				delete(g.config.RuneNormalModeKeys, r)
 			}
 			if ka.TargetingModeAction() {
-				GameConfig.RuneTargetModeKeys[r] = ka
+				g.config.RuneTargetModeKeys[r] = ka
 			} else {
-				delete(GameConfig.RuneTargetModeKeys, r)
+				delete(g.config.RuneTargetModeKeys, r)
 			}
 			err := g.SaveConfig()
 			if err != nil {
@@ -1476,7 +1551,7 @@ loop:
 		case QuitKeyConfig:
 			break loop
 		case ResetKeys:
-			ApplyDefaultKeyBindings()
+			ui.ApplyDefaultKeyBindings()
 			err := g.SaveConfig()
 			//err := g.RemoveDataFile("config.gob")
 			if err != nil {
@@ -1493,11 +1568,15 @@ func (ui *gameui) DrawPreviousLogs() {
 		bottom = 2
 	}
 	lines := ui.MapHeight() + bottom
-	nmax := len(g.Log) - lines
+	filter := LogFilterNone
+	search := ""
+	entries := g.Log
+	nmax := len(entries) - lines
 	n := nmax
 loop:
 	for {
 		ui.DrawDungeonView(NoFlushMode)
+		nmax = len(entries) - lines
 		if n >= nmax {
 			n = nmax
 		}
@@ -1505,43 +1584,78 @@ loop:
 			n = 0
 		}
 		to := n + lines
-		if to >= len(g.Log) {
-			to = len(g.Log)
+		if to >= len(entries) {
+			to = len(entries)
 		}
 		for i := 0; i < bottom; i++ {
-			ui.SetCell(DungeonWidth, ui.MapHeight()+i, '│', ColorFg, ColorBg)
+			ui.SetCell(DungeonWidth, ui.MapHeight()+i, '│', ui.ColorFg, ui.ColorBg)
 		}
 		for i := n; i < to; i++ {
-			e := g.Log[i]
+			e := entries[i]
 			fguicolor := ui.LogColor(e)
 			ui.ClearLine(i - n)
-			rc := utf8.RuneCountInString(e.String())
+			stamp := fmt.Sprintf("%2d|%5d ", e.Depth, e.Turn)
+			col := utf8.RuneCountInString(stamp)
+			rc := col + utf8.RuneCountInString(e.String())
 			if e.Tick {
 				rc += 2
 			}
 			if rc >= DungeonWidth {
 				for j := DungeonWidth; j < 103; j++ {
-					ui.SetCell(j, i-n, ' ', ColorFg, ColorBg)
+					ui.SetCell(j, i-n, ' ', ui.ColorFg, ui.ColorBg)
 				}
 			}
+			ui.DrawColoredText(stamp, 0, i-n, ui.ColorFgDark)
 			if e.Tick {
-				ui.DrawColoredText("•", 0, i-n, ColorYellow)
-				ui.DrawColoredText(e.String(), 2, i-n, fguicolor)
+				ui.DrawColoredText("•", col, i-n, ui.ColorYellow)
+				ui.DrawColoredText(e.String(), col+2, i-n, fguicolor)
 			} else {
-				ui.DrawColoredText(e.String(), 0, i-n, fguicolor)
+				ui.DrawColoredText(e.String(), col, i-n, fguicolor)
 			}
 		}
-		for i := len(g.Log); i < ui.MapHeight()+bottom; i++ {
+		for i := len(entries); i < ui.MapHeight()+bottom; i++ {
 			ui.ClearLine(i - n)
 		}
 		ui.ClearLine(lines)
-		s := fmt.Sprintf(" half-page up/down (u/d) quit (x) — (%d/%d) \n", len(g.Log)-to, len(g.Log))
+		s := fmt.Sprintf(" up/down (u/d) search (/) filter (F) turn (t) quit (x) — (%d/%d) ", len(entries)-to, len(entries))
+		if filter != LogFilterNone || search != "" {
+			s = fmt.Sprintf(" [%s] “%s” (/ F t x) — (%d/%d) ", filter, search, len(entries)-to, len(entries))
+		}
 		ui.DrawStyledTextLine(s, lines, FooterLine)
 		ui.Flush()
-		var quit bool
-		n, quit = ui.Scroll(n)
-		if quit {
+		var action logViewAction
+		n, action = ui.Scroll(n)
+		switch action {
+		case QuitLog:
 			break loop
+		case SearchLog:
+			text, ok := ui.ReadString("search: ", lines)
+			if !ok {
+				continue loop
+			}
+			search = text
+			entries = g.FilteredLog(filter, search)
+			n = len(entries) - lines
+		case FilterLog:
+			filter = filter.Next()
+			entries = g.FilteredLog(filter, search)
+			n = len(entries) - lines
+		case JumpToTurnLog:
+			text, ok := ui.ReadString("turn: ", lines)
+			if !ok {
+				continue loop
+			}
+			turn, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil {
+				continue loop
+			}
+			n = len(entries) - lines
+			for i, e := range entries {
+				if e.Turn >= turn {
+					n = i
+					break
+				}
+			}
 		}
 	}
 }
@@ -1596,11 +1710,11 @@ func (ui *gameui) DrawDescription(desc string, title string) {
 }
 
 func (ui *gameui) DrawText(text string, x, y int) {
-	ui.DrawColoredText(text, x, y, ColorFg)
+	ui.DrawColoredText(text, x, y, ui.ColorFg)
 }
 
 func (ui *gameui) DrawColoredText(text string, x, y int, fg uicolor) {
-	ui.DrawColoredTextOnBG(text, x, y, fg, ColorBg)
+	ui.DrawColoredTextOnBG(text, x, y, fg, ui.ColorBg)
 }
 
 func (ui *gameui) DrawColoredTextOnBG(text string, x, y int, fg, bg uicolor) {
@@ -1611,7 +1725,7 @@ func (ui *gameui) DrawColoredTextOnBG(text string, x, y int, fg, bg uicolor) {
 			col = 0
 			continue
 		}
-		if x+col >= UIWidth {
+		if x+col >= ui.UIWidth {
 			break
 		}
 		ui.SetCell(x+col, y, r, fg, bg)
@@ -1621,9 +1735,9 @@ func (ui *gameui) DrawColoredTextOnBG(text string, x, y int, fg, bg uicolor) {
 
 func (ui *gameui) DrawLine(lnum int) {
 	for i := 0; i < DungeonWidth; i++ {
-		ui.SetCell(i, lnum, '─', ColorFg, ColorBg)
+		ui.SetCell(i, lnum, '─', ui.ColorFg, ui.ColorBg)
 	}
-	ui.SetCell(DungeonWidth, lnum, '┤', ColorFg, ColorBg)
+	ui.SetCell(DungeonWidth, lnum, '┤', ui.ColorFg, ui.ColorBg)
 }
 
 func (ui *gameui) DrawTextLine(text string, lnum int) {
@@ -1639,59 +1753,59 @@ const (
 )
 
 func (ui *gameui) DrawInfoLine(text string) {
-	ui.ClearLineWithColor(ui.MapHeight()+1, ColorBgBorder)
-	ui.DrawColoredTextOnBG(text, 0, ui.MapHeight()+1, ColorBlue, ColorBgBorder)
+	ui.ClearLineWithColor(ui.MapHeight()+1, ui.ColorBgBorder)
+	ui.DrawColoredTextOnBG(text, 0, ui.MapHeight()+1, ui.ColorBlue, ui.ColorBgBorder)
 }
 
 func (ui *gameui) DrawStyledTextLine(text string, lnum int, st linestyle) {
 	nchars := utf8.RuneCountInString(text)
 	dist := (DungeonWidth - nchars) / 2
 	for i := 0; i < dist; i++ {
-		ui.SetCell(i, lnum, '─', ColorFg, ColorBg)
+		ui.SetCell(i, lnum, '─', ui.ColorFg, ui.ColorBg)
 	}
 	switch st {
 	case HeaderLine:
-		ui.DrawColoredText(text, dist, lnum, ColorYellow)
+		ui.DrawColoredText(text, dist, lnum, ui.ColorYellow)
 	case FooterLine:
-		ui.DrawColoredText(text, dist, lnum, ColorCyan)
+		ui.DrawColoredText(text, dist, lnum, ui.ColorCyan)
 	default:
-		ui.DrawColoredText(text, dist, lnum, ColorFg)
+		ui.DrawColoredText(text, dist, lnum, ui.ColorFg)
 	}
 	for i := dist + nchars; i < DungeonWidth; i++ {
-		ui.SetCell(i, lnum, '─', ColorFg, ColorBg)
+		ui.SetCell(i, lnum, '─', ui.ColorFg, ui.ColorBg)
 	}
 	switch st {
 	case HeaderLine:
 		if lnum == 0 {
-			ui.SetCell(DungeonWidth, lnum, '┐', ColorFg, ColorBg)
+			ui.SetCell(DungeonWidth, lnum, '┐', ui.ColorFg, ui.ColorBg)
 		} else {
-			ui.SetCell(DungeonWidth, lnum, '┤', ColorFg, ColorBg)
+			ui.SetCell(DungeonWidth, lnum, '┤', ui.ColorFg, ui.ColorBg)
 		}
 	case FooterLine:
-		ui.SetCell(DungeonWidth, lnum, '┘', ColorFg, ColorBg)
+		ui.SetCell(DungeonWidth, lnum, '┘', ui.ColorFg, ui.ColorBg)
 	default:
-		ui.SetCell(DungeonWidth, lnum, '┤', ColorFg, ColorBg)
+		ui.SetCell(DungeonWidth, lnum, '┤', ui.ColorFg, ui.ColorBg)
 	}
 }
 
 func (ui *gameui) ClearLine(lnum int) {
 	for i := 0; i < DungeonWidth; i++ {
-		ui.SetCell(i, lnum, ' ', ColorFg, ColorBg)
+		ui.SetCell(i, lnum, ' ', ui.ColorFg, ui.ColorBg)
 	}
-	ui.SetCell(DungeonWidth, lnum, '│', ColorFg, ColorBg)
+	ui.SetCell(DungeonWidth, lnum, '│', ui.ColorFg, ui.ColorBg)
 }
 
 func (ui *gameui) ClearLineWithColor(lnum int, bg uicolor) {
 	for i := 0; i < DungeonWidth; i++ {
-		ui.SetCell(i, lnum, ' ', ColorFg, bg)
+		ui.SetCell(i, lnum, ' ', ui.ColorFg, bg)
 	}
-	ui.SetCell(DungeonWidth, lnum, '│', ColorFg, ColorBg)
+	ui.SetCell(DungeonWidth, lnum, '│', ui.ColorFg, ui.ColorBg)
 }
 
 func (ui *gameui) ListItemBG(i int) uicolor {
-	bg := ColorBg
+	bg := ui.ColorBg
 	if i%2 == 1 {
-		bg = ColorBgBorder
+		bg = ui.ColorBgBorder
 	}
 	return bg
 }
@@ -1710,20 +1824,20 @@ func (ui *gameui) SelectMagara() error {
 		magaras := g.Player.Magaras
 		ui.ClearLine(0)
 		if !ui.Small() {
-			ui.DrawColoredText(MenuEvoke.String(), MenuCols[MenuEvoke][0], ui.MapHeight(), ColorCyan)
+			ui.DrawColoredText(MenuEvoke.String(), ui.MenuCols[MenuEvoke][0], ui.MapHeight(), ui.ColorCyan)
 			ui.DrawSelectDescBasics()
 		}
 		if desc {
-			ui.DrawColoredText("Describe", 0, 0, ColorBlue)
+			ui.DrawColoredText("Describe", 0, 0, ui.ColorBlue)
 			col := utf8.RuneCountInString("Describe")
 			ui.DrawText(" which magara? (press ? or click here for evocation menu)", col, 0)
 		} else {
-			ui.DrawColoredText("Evoke", 0, 0, ColorCyan)
+			ui.DrawColoredText("Evoke", 0, 0, ui.ColorCyan)
 			col := utf8.RuneCountInString("Evoke")
 			ui.DrawText(" which magara? (press ? or click here for description menu)", col, 0)
 		}
 		for i, r := range magaras {
-			ui.MagaraItem(i, i+1, r, ColorFg)
+			ui.MagaraItem(i, i+1, r, ui.ColorFg)
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(magaras)+1)
 		ui.Flush()
@@ -1733,7 +1847,7 @@ func (ui *gameui) SelectMagara() error {
 			continue
 		}
 		if err == nil {
-			ui.MagaraItem(index, index+1, magaras[index], ColorYellow)
+			ui.MagaraItem(index, index+1, magaras[index], ui.ColorYellow)
 			ui.Flush()
 			Sleep(AnimDurMedium)
 			if desc {
@@ -1754,20 +1868,20 @@ func (ui *gameui) EquipMagara() error {
 		magaras := g.Player.Magaras
 		ui.ClearLine(0)
 		if !ui.Small() {
-			ui.DrawColoredText(MenuInteract.String(), MenuCols[MenuInteract][0], ui.MapHeight(), ColorCyan)
+			ui.DrawColoredText(MenuInteract.String(), ui.MenuCols[MenuInteract][0], ui.MapHeight(), ui.ColorCyan)
 			ui.DrawSelectDescBasics()
 		}
 		if desc {
-			ui.DrawColoredText("Describe", 0, 0, ColorBlue)
+			ui.DrawColoredText("Describe", 0, 0, ui.ColorBlue)
 			col := utf8.RuneCountInString("Describe")
 			ui.DrawText(" which magara? (press ? or click here for equip menu)", col, 0)
 		} else {
-			ui.DrawColoredText("Equip", 0, 0, ColorCyan)
+			ui.DrawColoredText("Equip", 0, 0, ui.ColorCyan)
 			col := utf8.RuneCountInString("Evoke")
 			ui.DrawText(" instead of which magara? (press ? or click here for description menu)", col, 0)
 		}
 		for i, r := range magaras {
-			ui.MagaraItem(i, i+1, r, ColorFg)
+			ui.MagaraItem(i, i+1, r, ui.ColorFg)
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(magaras)+1)
 		ui.Flush()
@@ -1777,7 +1891,7 @@ func (ui *gameui) EquipMagara() error {
 			continue
 		}
 		if err == nil {
-			ui.MagaraItem(index, index+1, magaras[index], ColorYellow)
+			ui.MagaraItem(index, index+1, magaras[index], ui.ColorYellow)
 			ui.Flush()
 			Sleep(AnimDurMedium)
 			if desc {
@@ -1804,14 +1918,14 @@ func (ui *gameui) SelectItem() error {
 	for {
 		ui.ClearLine(0)
 		if !ui.Small() {
-			ui.DrawColoredText(MenuInventory.String(), MenuCols[MenuInventory][0], ui.MapHeight(), ColorCyan)
+			ui.DrawColoredText(MenuInventory.String(), ui.MenuCols[MenuInventory][0], ui.MapHeight(), ui.ColorCyan)
 			ui.DrawSelectBasics()
 		}
-		ui.DrawColoredText("Inventory", 0, 0, ColorCyan)
+		ui.DrawColoredText("Inventory", 0, 0, ui.ColorCyan)
 		col := utf8.RuneCountInString("Inventory")
 		ui.DrawText(" (select to see description)", col, 0)
 		for i := 0; i < len(items); i++ {
-			ui.InventoryItem(i, i+1, items[i], ColorFg, parts[i])
+			ui.InventoryItem(i, i+1, items[i], ui.ColorFg, parts[i])
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(items)+1)
 		ui.Flush()
@@ -1820,7 +1934,7 @@ func (ui *gameui) SelectItem() error {
 			continue
 		}
 		if err == nil {
-			ui.InventoryItem(index, index+1, items[index], ColorYellow, parts[index])
+			ui.InventoryItem(index, index+1, items[index], ui.ColorYellow, parts[index])
 			ui.Flush()
 			Sleep(AnimDurMedium)
 			ui.DrawDescription(items[index].Desc(g), "Item Description")
@@ -1843,12 +1957,7 @@ func (ui *gameui) ReadScroll() error {
 			ui.g.StoryPrint("Read lore message")
 		}
 		ui.g.Stats.Lore[ui.g.Depth] = true
-		if len(ui.g.Stats.Lore) == 4 {
-			AchLoreStudent.Get(ui.g)
-		}
-		if len(ui.g.Stats.Lore) == len(ui.g.Params.Lore) {
-			AchLoremaster.Get(ui.g)
-		}
+		ui.g.CheckAchievements(CounterHook)
 	default:
 		ui.DrawDescription(sc.Text(ui.g), "Story Message")
 	}
@@ -1865,8 +1974,59 @@ func (ui *gameui) ActionItem(i, lnum int, ka action, fg uicolor) {
 	ui.DrawColoredTextOnBG(fmt.Sprintf("%c - %s", rune(i+97), desc), 0, lnum, fg, bg)
 }
 
+func (ui *gameui) LandmarkItem(i, lnum int, lm landmark, fg uicolor) {
+	bg := ui.ListItemBG(i)
+	ui.ClearLineWithColor(lnum, bg)
+	ui.DrawColoredTextOnBG(fmt.Sprintf("%c - %s (%d steps)", rune(i+97), lm.Kind, lm.Dist), 0, lnum, fg, bg)
+}
+
+func (ui *gameui) TravelToLandmark() error {
+	g := ui.g
+	lms := g.Landmarks()
+	if len(lms) == 0 {
+		return errors.New("You do not know any reachable landmarks.")
+	}
+	max := ui.MapHeight() - 2
+	if max > 26 {
+		max = 26
+	}
+	if len(lms) > max {
+		lms = lms[:max]
+	}
+	ui.DrawDungeonView(NoFlushMode)
+	for {
+		ui.ClearLine(0)
+		if !ui.Small() {
+			ui.DrawSelectBasics()
+		}
+		ui.DrawColoredText("Travel", 0, 0, ui.ColorCyan)
+		col := utf8.RuneCountInString("Travel")
+		ui.DrawText(" to which landmark?", col, 0)
+		for i, lm := range lms {
+			ui.LandmarkItem(i, i+1, lm, ui.ColorFg)
+		}
+		ui.DrawTextLine(" press (x) to cancel ", len(lms)+1)
+		ui.Flush()
+		index, alt, err := ui.Select(len(lms))
+		if alt {
+			continue
+		}
+		if err != nil {
+			ui.DrawDungeonView(NoFlushMode)
+			return err
+		}
+		ui.LandmarkItem(index, index+1, lms[index], ui.ColorYellow)
+		ui.Flush()
+		Sleep(AnimDurMedium)
+		ui.DrawDungeonView(NoFlushMode)
+		return g.GoToLandmark(lms[index].Pos)
+	}
+}
+
 var menuActions = []action{
+	ActionTravel,
 	ActionLogs,
+	ActionConducts,
 	ActionMenuCommandHelp,
 	ActionMenuTargetingHelp,
 	ActionConfigure,
@@ -1879,14 +2039,14 @@ func (ui *gameui) SelectAction(actions []action) (action, error) {
 	for {
 		ui.ClearLine(0)
 		if !ui.Small() {
-			ui.DrawColoredText(MenuOther.String(), MenuCols[MenuOther][0], ui.MapHeight(), ColorCyan)
+			ui.DrawColoredText(MenuOther.String(), ui.MenuCols[MenuOther][0], ui.MapHeight(), ui.ColorCyan)
 			ui.DrawSelectBasics()
 		}
-		ui.DrawColoredText("Choose", 0, 0, ColorCyan)
+		ui.DrawColoredText("Choose", 0, 0, ui.ColorCyan)
 		col := utf8.RuneCountInString("Choose")
 		ui.DrawText(" which action?", col, 0)
 		for i, r := range actions {
-			ui.ActionItem(i, i+1, r, ColorFg)
+			ui.ActionItem(i, i+1, r, ui.ColorFg)
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(actions)+1)
 		ui.Flush()
@@ -1898,7 +2058,7 @@ func (ui *gameui) SelectAction(actions []action) (action, error) {
 			ui.DrawDungeonView(NoFlushMode)
 			return ActionExamine, err
 		}
-		ui.ActionItem(index, index+1, actions[index], ColorYellow)
+		ui.ActionItem(index, index+1, actions[index], ui.ColorYellow)
 		ui.Flush()
 		Sleep(AnimDurMedium)
 		ui.DrawDungeonView(NoFlushMode)
@@ -1914,6 +2074,8 @@ const (
 	toggleLayout
 	toggleTiles
 	toggleShowNumbers
+	toggleLevelDebrief
+	toggleStealthPaths
 )
 
 func (s setting) String() (text string) {
@@ -1928,6 +2090,10 @@ func (s setting) String() (text string) {
 		text = "Toggle tiles/ascii display"
 	case toggleShowNumbers:
 		text = "Toggle hearts/numbers"
+	case toggleLevelDebrief:
+		text = "Toggle level debrief on descent"
+	case toggleStealthPaths:
+		text = "Toggle shortest/stealthy travel paths"
 	}
 	return text
 }
@@ -1937,6 +2103,8 @@ var settingsActions = []setting{
 	invertLOS,
 	toggleLayout,
 	toggleShowNumbers,
+	toggleLevelDebrief,
+	toggleStealthPaths,
 }
 
 func (ui *gameui) ConfItem(i, lnum int, s setting, fg uicolor) {
@@ -1949,11 +2117,11 @@ func (ui *gameui) SelectConfigure(actions []setting) (setting, error) {
 	ui.DrawDungeonView(NoFlushMode)
 	for {
 		ui.ClearLine(0)
-		ui.DrawColoredText("Perform", 0, 0, ColorCyan)
+		ui.DrawColoredText("Perform", 0, 0, ui.ColorCyan)
 		col := utf8.RuneCountInString("Perform")
 		ui.DrawText(" which change?", col, 0)
 		for i, r := range actions {
-			ui.ConfItem(i, i+1, r, ColorFg)
+			ui.ConfItem(i, i+1, r, ui.ColorFg)
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(actions)+1)
 		if !ui.Small() {
@@ -1968,7 +2136,7 @@ func (ui *gameui) SelectConfigure(actions []setting) (setting, error) {
 			ui.DrawDungeonView(NoFlushMode)
 			return setKeys, err
 		}
-		ui.ConfItem(index, index+1, actions[index], ColorYellow)
+		ui.ConfItem(index, index+1, actions[index], ui.ColorYellow)
 		ui.Flush()
 		Sleep(AnimDurMedium)
 		ui.DrawDungeonView(NoFlushMode)
@@ -1986,15 +2154,15 @@ func (ui *gameui) HandleSettingAction() error {
 	case setKeys:
 		ui.ChangeKeys()
 	case invertLOS:
-		GameConfig.DarkLOS = !GameConfig.DarkLOS
+		g.config.DarkLOS = !g.config.DarkLOS
 		err := g.SaveConfig()
 		if err != nil {
 			g.Print(err.Error())
 		}
-		if GameConfig.DarkLOS {
-			ApplyDarkLOS()
+		if g.config.DarkLOS {
+			ui.ApplyDarkLOS()
 		} else {
-			ApplyLightLOS()
+			ui.ApplyLightLOS()
 		}
 	case toggleLayout:
 		ui.ApplyToggleLayout()
@@ -2009,7 +2177,20 @@ func (ui *gameui) HandleSettingAction() error {
 			g.Print(err.Error())
 		}
 	case toggleShowNumbers:
-		GameConfig.ShowNumbers = !GameConfig.ShowNumbers
+		g.config.ShowNumbers = !g.config.ShowNumbers
+		err := g.SaveConfig()
+		if err != nil {
+			g.Print(err.Error())
+		}
+	case toggleLevelDebrief:
+		g.config.LevelDebrief = !g.config.LevelDebrief
+		err := g.SaveConfig()
+		if err != nil {
+			g.Print(err.Error())
+		}
+	case toggleStealthPaths:
+		g.config.StealthPaths = !g.config.StealthPaths
+		g.DijkstraMapRebuild = true
 		err := g.SaveConfig()
 		if err != nil {
 			g.Print(err.Error())
@@ -2027,11 +2208,11 @@ func (ui *gameui) WizardItem(i, lnum int, s wizardAction, fg uicolor) {
 func (ui *gameui) SelectWizardMagic(actions []wizardAction) (wizardAction, error) {
 	for {
 		ui.ClearLine(0)
-		ui.DrawColoredText("Evoke", 0, 0, ColorCyan)
+		ui.DrawColoredText("Evoke", 0, 0, ui.ColorCyan)
 		col := utf8.RuneCountInString("Evoke")
 		ui.DrawText(" which magic?", col, 0)
 		for i, r := range actions {
-			ui.WizardItem(i, i+1, r, ColorFg)
+			ui.WizardItem(i, i+1, r, ui.ColorFg)
 		}
 		ui.DrawTextLine(" press (x) to cancel ", len(actions)+1)
 		if !ui.Small() {
@@ -2046,7 +2227,7 @@ func (ui *gameui) SelectWizardMagic(actions []wizardAction) (wizardAction, error
 			ui.DrawDungeonView(NoFlushMode)
 			return WizardInfoAction, err
 		}
-		ui.WizardItem(index, index+1, actions[index], ColorYellow)
+		ui.WizardItem(index, index+1, actions[index], ui.ColorYellow)
 		ui.Flush()
 		Sleep(AnimDurMedium)
 		ui.DrawDungeonView(NoFlushMode)
@@ -2056,12 +2237,12 @@ func (ui *gameui) SelectWizardMagic(actions []wizardAction) (wizardAction, error
 
 func (ui *gameui) DrawMenus() {
 	line := ui.MapHeight()
-	for i, cols := range MenuCols[0 : len(MenuCols)-1] {
+	for i, cols := range ui.MenuCols[0 : len(ui.MenuCols)-1] {
 		if cols[0] >= 0 {
 			if menu(i) == ui.menuHover {
-				ui.DrawColoredText(menu(i).String(), cols[0], line, ColorBlue)
+				ui.DrawColoredText(menu(i).String(), cols[0], line, ui.ColorBlue)
 			} else {
-				ui.DrawColoredText(menu(i).String(), cols[0], line, ColorViolet)
+				ui.DrawColoredText(menu(i).String(), cols[0], line, ui.ColorViolet)
 			}
 		}
 	}
@@ -2069,11 +2250,11 @@ func (ui *gameui) DrawMenus() {
 	if interactMenu == "" {
 		return
 	}
-	i := len(MenuCols) - 1
-	cols := MenuCols[i]
+	i := len(ui.MenuCols) - 1
+	cols := ui.MenuCols[i]
 	if menu(i) == ui.menuHover {
-		ui.DrawColoredText(interactMenu, cols[0], line, ColorBlue)
+		ui.DrawColoredText(interactMenu, cols[0], line, ui.ColorBlue)
 	} else {
-		ui.DrawColoredText(interactMenu, cols[0], line, ColorViolet)
+		ui.DrawColoredText(interactMenu, cols[0], line, ui.ColorViolet)
 	}
 }
@@ -49,12 +49,24 @@ func (g *game) SortedKilledMonsters() monsSlice {
 	return ms
 }
 
+const DumpLogMessages = 20
+
 func (g *game) Dump() string {
 	buf := &bytes.Buffer{}
 	fmt.Fprintf(buf, " -- Harmonist version %s character file --\n\n", Version)
 	if g.Wizard {
 		fmt.Fprintf(buf, "**WIZARD MODE**\n")
 	}
+	if g.Practice {
+		fmt.Fprintf(buf, "**PRACTICE MODE** (%d rewinds)\n", g.Rewinds)
+	}
+	if g.Daily != "" {
+		fmt.Fprintf(buf, "Daily challenge of %s.\n", g.Daily)
+	}
+	fmt.Fprintf(buf, "Difficulty: %s.\n", g.Params.Difficulty)
+	if g.Draft != nil {
+		fmt.Fprintf(buf, "Drafted starting kit: %s.\n", g.Draft)
+	}
 	if g.Player.HP > 0 && g.Depth == -1 {
 		fmt.Fprintf(buf, "You escaped from Dayoriah Clan's domain alive!\n")
 	} else if g.Player.HP <= 0 {
@@ -94,6 +106,9 @@ func (g *game) Dump() string {
 		fmt.Fprintf(buf, "- %s (neck)\n", g.Player.Inventory.Neck.ShortDesc(g))
 	}
 	fmt.Fprintf(buf, "\n")
+	fmt.Fprintf(buf, "Conducts:\n")
+	fmt.Fprint(buf, g.ConductsReport())
+	fmt.Fprintf(buf, "\n")
 	fmt.Fprintf(buf, "Miscellaneous:\n")
 	if g.Stats.Killed > 0 {
 		fmt.Fprintf(buf, "%d monsters died.\n", g.Stats.Killed)
@@ -104,12 +119,16 @@ func (g *game) Dump() string {
 	if maxDepth == 1 {
 		s = ""
 	}
-	fmt.Fprintf(buf, "You explored %d level%s out of %d.\n", maxDepth, s, MaxDepth)
+	if g.Params.Plan.Endless {
+		fmt.Fprintf(buf, "You explored %d level%s in endless descent mode (score: %d).\n", maxDepth, s, g.EndlessScore())
+	} else {
+		fmt.Fprintf(buf, "You explored %d level%s out of %d.\n", maxDepth, s, MaxDepth)
+	}
 	fmt.Fprintf(buf, "\n")
 	fmt.Fprintf(buf, "Last messages:\n")
-	for i := len(g.Log) - 10; i < len(g.Log); i++ {
+	for i := len(g.Log) - DumpLogMessages; i < len(g.Log); i++ {
 		if i >= 0 {
-			fmt.Fprintf(buf, "%s\n", g.Log[i])
+			fmt.Fprintf(buf, "%s\n", g.Log[i].Stamped())
 		}
 	}
 	fmt.Fprintf(buf, "\n")
@@ -118,6 +137,10 @@ func (g *game) Dump() string {
 	buf.WriteString(g.DumpDungeon())
 	fmt.Fprintf(buf, "└%s┘\n", strings.Repeat("─", DungeonWidth))
 	fmt.Fprintf(buf, "\n")
+	if len(g.Notes) > 0 {
+		fmt.Fprint(buf, g.DumpNotes())
+		fmt.Fprintf(buf, "\n")
+	}
 	if g.Stats.Killed > 0 {
 		fmt.Fprint(buf, g.DumpedKilledMonsters())
 		fmt.Fprintf(buf, "\n")
@@ -165,6 +188,9 @@ func (g *game) DetailedStatistics(w io.Writer) {
 	fmt.Fprintf(w, "You opened %d doors.\n", g.Stats.DoorsOpened)
 	fmt.Fprintf(w, "You moved %d times.\n", g.Stats.Moves)
 	fmt.Fprintf(w, "You waited %d times.\n", g.Stats.Waits)
+	if g.Stats.Alarms > 0 {
+		fmt.Fprintf(w, "Guards raised the alarm %d times.\n", g.Stats.Alarms)
+	}
 	if g.Stats.Extinguishments > 0 {
 		fmt.Fprintf(w, "You extinguished %d campfires.\n", g.Stats.Extinguishments)
 	}
@@ -180,11 +206,11 @@ func (g *game) DetailedStatistics(w io.Writer) {
 	fmt.Fprintf(w, "You spent %d%% turns wounded with monsters in sight.\n", g.Stats.TMWounded*100/(g.Stats.Turns+1))
 	maxDepth := Max(g.Depth-1, g.ExploredLevels)
 	if g.Player.HP <= 0 {
-		maxDepth++
+		maxDepth = Max(maxDepth, g.Depth)
 	}
 	if maxDepth >= MaxDepth+1 {
-		// should not happen
-		maxDepth = -1
+		// levels of the endless descent are not detailed
+		maxDepth = MaxDepth
 	}
 	fmt.Fprintf(w, "\n")
 	hfmt := "%-23s"
@@ -282,6 +308,35 @@ func (g *game) DetailedStatistics(w io.Writer) {
 	}
 }
 
+func (g *game) LevelDebrief() string {
+	buf := &bytes.Buffer{}
+	depth := g.Depth
+	unnoticed := 0
+	for _, mons := range g.Monsters {
+		if mons.Exists() && !mons.Alerted {
+			unnoticed++
+		}
+	}
+	fmt.Fprintf(buf, "You explored %d%% of depth %d.\n", g.Stats.DExplPerc[depth], depth)
+	fmt.Fprintf(buf, "You were spotted %d times by %d monsters, while %d monsters never noticed you.\n",
+		g.Stats.DSpotted[depth], g.Stats.DUSpotted[depth], unnoticed)
+	fmt.Fprintf(buf, "You left %d%% of monsters sleeping.\n", g.Stats.DSleepingPerc[depth])
+	fmt.Fprintf(buf, "You evoked magaras %d times and activated %d magical stones.\n",
+		g.Stats.DMagaraUses[depth], g.Stats.DUsedStones[depth])
+	fmt.Fprintf(buf, "You rested %d times.\n", g.Stats.DRests[depth])
+	fmt.Fprintf(buf, "You endured %d damage.\n", g.Stats.DDamage[depth])
+	fmt.Fprintf(buf, "\n")
+	if len(g.Stats.DAchievements[depth]) == 0 {
+		fmt.Fprintf(buf, "You did not unlock any achievements on this level.\n")
+	} else {
+		fmt.Fprintf(buf, "Achievements:\n")
+		for _, achv := range g.Stats.DAchievements[depth] {
+			fmt.Fprintf(buf, "- %s\n", achv)
+		}
+	}
+	return buf.String()
+}
+
 func (g *game) DumpStory() string {
 	return strings.Join(g.Stats.Story, "\n")
 }
@@ -348,6 +403,16 @@ func (g *game) SimplifedDump(err error) string {
 	if g.Wizard {
 		fmt.Fprintf(buf, "**WIZARD MODE**\n")
 	}
+	if g.Practice {
+		fmt.Fprintf(buf, "**PRACTICE MODE** (%d rewinds)\n", g.Rewinds)
+	}
+	if g.Daily != "" {
+		fmt.Fprintf(buf, "Daily challenge of %s.\n", g.Daily)
+	}
+	fmt.Fprintf(buf, "Difficulty: %s.\n", g.Params.Difficulty)
+	if g.Draft != nil {
+		fmt.Fprintf(buf, "Drafted starting kit: %s.\n", g.Draft)
+	}
 	if g.Player.HP > 0 && g.Depth == -1 {
 		fmt.Fprintf(buf, "You escaped from Dayoriah Clan's domain alive!\n")
 	} else if g.Player.HP <= 0 {
@@ -371,7 +436,11 @@ func (g *game) SimplifedDump(err error) string {
 	if maxDepth == 1 {
 		s = ""
 	}
-	fmt.Fprintf(buf, "You explored %d level%s out of %d.\n", maxDepth, s, MaxDepth)
+	if g.Params.Plan.Endless {
+		fmt.Fprintf(buf, "You explored %d level%s in endless descent mode (score: %d).\n", maxDepth, s, g.EndlessScore())
+	} else {
+		fmt.Fprintf(buf, "You explored %d level%s out of %d.\n", maxDepth, s, MaxDepth)
+	}
 	fmt.Fprintf(buf, "\n")
 	if err != nil {
 		fmt.Fprintf(buf, "Error writing dump: %v.\n", err)
@@ -59,15 +59,15 @@ func (d *dungeon) WallAreaCount(area []position, pos position, radius int) int {
 	return count
 }
 
-func (d *dungeon) FreePassableCell() position {
+func (d *dungeon) FreePassableCell(g *game) position {
 	count := 0
 	for {
 		count++
 		if count > 1000 {
 			panic("FreeCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := d.Cell(pos)
 		if c.IsPassable() {
@@ -76,15 +76,15 @@ func (d *dungeon) FreePassableCell() position {
 	}
 }
 
-func (d *dungeon) WallCell() position {
+func (d *dungeon) WallCell(g *game) position {
 	count := 0
 	for {
 		count++
 		if count > 1000 {
 			panic("WallCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := d.Cell(pos)
 		if c.T == WallCell {
@@ -178,8 +178,8 @@ func (d *dungeon) Connected(pos position, nf func(position) bool) (map[position]
 	return conn, count
 }
 
-func (d *dungeon) connex() bool {
-	pos := d.FreePassableCell()
+func (d *dungeon) connex(g *game) bool {
+	pos := d.FreePassableCell(g)
 	conn, _ := d.Connected(pos, d.NotWallCell)
 	for i, c := range d.Cells {
 		if c.IsPassable() && !conn[idxtopos(i)] {
@@ -270,6 +270,7 @@ func (rs roomSlice) Less(i, j int) bool {
 }
 
 type dgen struct {
+	g       *game
 	d       *dungeon
 	tunnel  map[position]bool
 	room    map[position]bool
@@ -278,6 +279,7 @@ type dgen struct {
 	special specialRoom
 	layout  maplayout
 	cc      []int
+	common  bool // placing common monster bands
 }
 
 func (dg *dgen) WallAreaCount(area []position, pos position, radius int) int {
@@ -300,7 +302,7 @@ func (dg *dgen) WallAreaCount(area []position, pos position, radius int) int {
 
 // UnusedEntry returns an unused entry, if possible, or a random entry
 // otherwise.
-func (r *room) UnusedEntry() int {
+func (r *room) UnusedEntry(g *game) int {
 	ens := []int{}
 	for i, e := range r.entries {
 		if !e.used {
@@ -308,9 +310,9 @@ func (r *room) UnusedEntry() int {
 		}
 	}
 	if len(ens) == 0 {
-		return RandInt(len(r.entries))
+		return g.RandInt(len(r.entries))
 	}
-	return ens[RandInt(len(ens))]
+	return ens[g.RandInt(len(ens))]
 }
 
 func (dg *dgen) ConnectRoomsShortestPath(i, j int) bool {
@@ -321,12 +323,12 @@ func (dg *dgen) ConnectRoomsShortestPath(i, j int) bool {
 	r2 := dg.rooms[j]
 	var e1pos, e2pos position
 	var e1i, e2i int
-	e1i = r1.UnusedEntry()
+	e1i = r1.UnusedEntry(dg.g)
 	e1pos = r1.entries[e1i].pos
-	e2i = r2.UnusedEntry()
+	e2i = r2.UnusedEntry(dg.g)
 	e2pos = r2.entries[e2i].pos
 	tp := &tunnelPath{dg: dg}
-	path, _, found := AstarPath(tp, e1pos, e2pos)
+	path, _, found := dg.g.AstarPath(tp, e1pos, e2pos)
 	if !found {
 		log.Println(fmt.Sprintf("no path from %v to %v", e1pos, e2pos))
 		return false
@@ -445,17 +447,17 @@ func (r *room) Dig(dg *dgen) {
 		case 'B':
 			// obstacle
 			t := WallCell
-			switch RandInt(9) {
+			switch dg.g.RandInt(9) {
 			case 0, 6:
 				t = TreeCell
 			case 1:
-				if RandInt(2) == 0 {
+				if dg.g.RandInt(2) == 0 {
 					t = QueenRockCell
 				} else {
 					t = LightCell
 				}
 			case 2:
-				if RandInt(2) == 0 {
+				if dg.g.RandInt(2) == 0 {
 					t = ChasmCell
 				} else {
 					t = TableCell
@@ -576,8 +578,8 @@ func (dg *dgen) NewRoom(rpos position, kind string) *room {
 			drev = 4
 		}
 	}
-	if RandInt(drev) == 0 {
-		switch RandInt(4) {
+	if dg.g.RandInt(drev) == 0 {
+		switch dg.g.RandInt(4) {
 		case 0:
 			r.DRev()
 		case 1:
@@ -588,7 +590,7 @@ func (dg *dgen) NewRoom(rpos position, kind string) *room {
 			r.DVRev()
 		}
 	} else {
-		switch RandInt(4) {
+		switch dg.g.RandInt(4) {
 		case 0:
 			r.VRev()
 		case 1:
@@ -630,7 +632,7 @@ func (dg *dgen) nearRoom(i int) (k int) {
 		}
 		nd := roomDistance(r, nextRoom)
 		if nd < d {
-			n := RandInt(5)
+			n := dg.g.RandInt(5)
 			if n > 0 {
 				d = nd
 				k = j
@@ -693,7 +695,7 @@ func (dg *dgen) PutHoledWalls(g *game, n int) {
 		return
 	}
 	for i := 0; i < n; i++ {
-		pos := candidates[RandInt(len(candidates))]
+		pos := candidates[g.RandInt(len(candidates))]
 		g.Dungeon.SetCell(pos, HoledWallCell)
 	}
 }
@@ -710,7 +712,7 @@ func (dg *dgen) PutWindows(g *game, n int) {
 		return
 	}
 	for i := 0; i < n; i++ {
-		pos := candidates[RandInt(len(candidates))]
+		pos := candidates[g.RandInt(len(candidates))]
 		g.Dungeon.SetCell(pos, WindowCell)
 	}
 }
@@ -749,17 +751,17 @@ func (dg *dgen) GenRooms(templates []string, n int, pl placement) (ps []position
 			count--
 			switch pl {
 			case PlacementRandom:
-				pos = position{RandInt(DungeonWidth - 1), RandInt(DungeonHeight - 1)}
+				pos = position{dg.g.RandInt(DungeonWidth - 1), dg.g.RandInt(DungeonHeight - 1)}
 			case PlacementCenter:
-				pos = position{DungeonWidth/2 - 4 + RandInt(5), DungeonHeight/2 - 3 + RandInt(4)}
+				pos = position{DungeonWidth/2 - 4 + dg.g.RandInt(5), DungeonHeight/2 - 3 + dg.g.RandInt(4)}
 			case PlacementEdge:
-				if RandInt(2) == 0 {
-					pos = position{RandInt(DungeonWidth / 4), RandInt(DungeonHeight - 1)}
+				if dg.g.RandInt(2) == 0 {
+					pos = position{dg.g.RandInt(DungeonWidth / 4), dg.g.RandInt(DungeonHeight - 1)}
 				} else {
-					pos = position{3*DungeonWidth/4 + RandInt(DungeonWidth/4) - 1, RandInt(DungeonHeight - 1)}
+					pos = position{3*DungeonWidth/4 + dg.g.RandInt(DungeonWidth/4) - 1, dg.g.RandInt(DungeonHeight - 1)}
 				}
 			}
-			tpl = templates[RandInt(len(templates))]
+			tpl = templates[dg.g.RandInt(len(templates))]
 			r = dg.NewRoom(pos, tpl)
 		}
 		if r != nil {
@@ -855,7 +857,7 @@ func (dg *dgen) GenArtifactPlace(g *game) {
 }
 
 func (g *game) GenRoomTunnels(ml maplayout) {
-	dg := dgen{}
+	dg := dgen{g: g}
 	dg.layout = ml
 	d := &dungeon{}
 	d.Cells = make([]cell, DungeonNCells)
@@ -873,7 +875,7 @@ func (g *game) GenRoomTunnels(ml maplayout) {
 	case RandomSmallWalkCaveUrbanised:
 		dg.GenCaveMap(20 * 10)
 	case NaturalCave:
-		if RandInt(3) == 0 {
+		if g.RandInt(3) == 0 {
 			dg.GenCellularAutomataCaveMap()
 		} else {
 			dg.GenCaveMap(21 * 47)
@@ -884,7 +886,7 @@ func (g *game) GenRoomTunnels(ml maplayout) {
 	if sr := g.Params.Special[g.Depth]; sr != noSpecialRoom {
 		nspecial--
 		pl := PlacementEdge
-		if RandInt(3) == 0 {
+		if g.RandInt(3) == 0 {
 			pl = PlacementCenter
 		}
 		dg.special = sr
@@ -943,19 +945,19 @@ func (g *game) GenRoomTunnels(ml maplayout) {
 	dg.PutDoors(g)
 	dg.PlayerStartCell(g, places)
 	dg.ClearUnconnected(g)
-	if RandInt(10) > 0 {
+	if g.RandInt(10) > 0 {
 		var t terrain
-		if RandInt(5) > 1 {
+		if g.RandInt(5) > 1 {
 			t = ChasmCell
 		} else {
 			t = WaterCell
 		}
 		dg.GenLake(t)
-		if RandInt(5) == 0 {
+		if g.RandInt(5) == 0 {
 			dg.GenLake(t)
 		}
 	}
-	if g.Depth < MaxDepth {
+	if g.Depth < MaxDepth || g.Params.Plan.Endless {
 		if g.Params.Blocked[g.Depth] {
 			dg.GenStairs(g, BlockedStair)
 		} else {
@@ -963,7 +965,7 @@ func (g *game) GenRoomTunnels(ml maplayout) {
 		}
 		dg.GenFakeStairs(g)
 	}
-	for i := 0; i < 4+RandInt(2); i++ {
+	for i := 0; i < 4+g.RandInt(2); i++ {
 		dg.GenBarrel(g)
 	}
 	dg.AddSpecial(g, ml)
@@ -972,7 +974,7 @@ func (g *game) GenRoomTunnels(ml maplayout) {
 	})
 	dg.GenMonsters(g)
 	dg.PutCavernCells(g)
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		dg.GenQueenRock()
 	}
 }
@@ -1021,72 +1023,76 @@ func (dg *dgen) AddSpecial(g *game, ml maplayout) {
 	ntables := 4
 	switch ml {
 	case AutomataCave, RandomWalkCave, NaturalCave:
-		if RandInt(3) == 0 {
+		if g.RandInt(3) == 0 {
 			ntables++
-		} else if RandInt(10) == 0 {
+		} else if g.RandInt(10) == 0 {
 			ntables--
 		}
 	case RandomWalkTreeCave:
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			ntables++
 		}
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			ntables++
 		}
 	case RandomSmallWalkCaveUrbanised:
 		ntables += 2
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			ntables++
 		}
 	}
 	if g.Params.Tables[g.Depth] {
-		ntables += 2 + RandInt(2)
+		ntables += 2 + g.RandInt(2)
 	}
 	for i := 0; i < ntables; i++ {
 		dg.GenTable(g)
 	}
 	dg.GenLight(g)
+	nalarms := 1 + g.RandInt(2)
+	for i := 0; i < nalarms; i++ {
+		dg.GenAlarm(g)
+	}
 	ntrees := 1
 	switch ml {
 	case AutomataCave:
-		if RandInt(4) == 0 {
+		if g.RandInt(4) == 0 {
 			ntrees++
-		} else if RandInt(8) == 0 {
+		} else if g.RandInt(8) == 0 {
 			ntrees--
 		}
 	case RandomWalkCave:
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			ntrees++
 		}
-		if RandInt(8) == 0 {
+		if g.RandInt(8) == 0 {
 			ntrees++
 		}
 	case NaturalCave:
 		ntrees++
-		if RandInt(2) > 0 {
+		if g.RandInt(2) > 0 {
 			ntrees++
 		}
 	case RandomWalkTreeCave, RandomSmallWalkCaveUrbanised:
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			ntrees--
 		}
 	}
 	if g.Params.Trees[g.Depth] {
-		ntrees += 2 + RandInt(2)
+		ntrees += 2 + g.RandInt(2)
 	}
 	for i := 0; i < ntrees; i++ {
 		dg.GenTree(g)
 	}
 	nhw := 1
-	if RandInt(3) > 0 {
+	if g.RandInt(3) > 0 {
 		nhw++
 	}
 	if g.Params.Holes[g.Depth] {
-		nhw += 3 + RandInt(2)
+		nhw += 3 + g.RandInt(2)
 	}
 	switch ml {
 	case RandomSmallWalkCaveUrbanised:
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			nhw++
 		}
 	}
@@ -1096,11 +1102,11 @@ func (dg *dgen) AddSpecial(g *game, ml maplayout) {
 		nwin++
 	}
 	if g.Params.Windows[g.Depth] {
-		nwin += 4 + RandInt(3)
+		nwin += 4 + g.RandInt(3)
 	}
 	switch ml {
 	case RandomSmallWalkCaveUrbanised:
-		if RandInt(4) > 0 {
+		if g.RandInt(4) > 0 {
 			nhw++
 		}
 	}
@@ -1118,7 +1124,7 @@ func (dg *dgen) PutLore(g *game) {
 		if count > 2000 {
 			panic("PutLore1")
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceItem)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceItem)
 	}
 	count = 0
 	for {
@@ -1126,7 +1132,7 @@ func (dg *dgen) PutLore(g *game) {
 		if count > 1000 {
 			panic("PutLore2")
 		}
-		i := RandInt(len(LoreMessages))
+		i := g.RandInt(len(LoreMessages))
 		if g.GeneratedLore[i] {
 			continue
 		}
@@ -1144,18 +1150,18 @@ func (dg *dgen) GenLight(g *game) {
 	ni := 8
 	switch dg.layout {
 	case NaturalCave:
-		no += RandInt(2)
-		ni += RandInt(3)
+		no += g.RandInt(2)
+		ni += g.RandInt(3)
 	case AutomataCave, RandomWalkCave:
-		ni += RandInt(4)
+		ni += g.RandInt(4)
 	case RandomWalkTreeCave:
 		no--
-		ni += RandInt(4)
+		ni += g.RandInt(4)
 	case RandomSmallWalkCaveUrbanised:
 		no--
-		no -= RandInt(2)
+		no -= g.RandInt(2)
 		ni += 2
-		ni += RandInt(4)
+		ni += g.RandInt(4)
 	}
 	for i := 0; i < no; i++ {
 		pos := dg.OutsideGroundCell(g)
@@ -1163,11 +1169,11 @@ func (dg *dgen) GenLight(g *game) {
 		lights = append(lights, pos)
 	}
 	for i := 0; i < ni; i++ {
-		pos := dg.rooms[RandInt(len(dg.rooms))].RandomPlaces(PlaceSpecialOrStatic)
+		pos := dg.rooms[g.RandInt(len(dg.rooms))].RandomPlaces(g, PlaceSpecialOrStatic)
 		if pos != InvalidPos {
 			g.Dungeon.SetCell(pos, LightCell)
 			lights = append(lights, pos)
-		} else if RandInt(10) > 0 {
+		} else if g.RandInt(10) > 0 {
 			i--
 		}
 	}
@@ -1177,7 +1183,7 @@ func (dg *dgen) GenLight(g *game) {
 	g.ComputeLights()
 }
 
-func (r *room) RandomPlace(kind placeKind) position {
+func (r *room) RandomPlace(g *game, kind placeKind) position {
 	var p []int
 	for i, pl := range r.places {
 		if pl.kind == kind && !pl.used {
@@ -1187,17 +1193,17 @@ func (r *room) RandomPlace(kind placeKind) position {
 	if len(p) == 0 {
 		return InvalidPos
 	}
-	j := p[RandInt(len(p))]
+	j := p[g.RandInt(len(p))]
 	r.places[j].used = true
 	return r.places[j].pos
 }
 
 var PlaceSpecialOrStatic = []placeKind{PlaceSpecialStatic, PlaceStatic}
 
-func (r *room) RandomPlaces(kinds []placeKind) position {
+func (r *room) RandomPlaces(g *game, kinds []placeKind) position {
 	pos := InvalidPos
 	for _, kind := range kinds {
-		pos = r.RandomPlace(kind)
+		pos = r.RandomPlace(g, kind)
 		if pos != InvalidPos {
 			break
 		}
@@ -1218,7 +1224,7 @@ loop:
 			}
 		}
 	}
-	g.Player.Pos = r.RandomPlace(PlacePatrol)
+	g.Player.Pos = r.RandomPlace(g, PlacePatrol)
 	switch g.Depth {
 	case 1, 4:
 	default:
@@ -1227,7 +1233,7 @@ loop:
 	itpos := InvalidPos
 	neighbors := g.Dungeon.FreeNeighbors(g.Player.Pos)
 	for i := 0; i < len(neighbors); i++ {
-		j := RandInt(len(neighbors) - i)
+		j := g.RandInt(len(neighbors) - i)
 		neighbors[i], neighbors[j] = neighbors[j], neighbors[i]
 	}
 loopnb:
@@ -1244,10 +1250,10 @@ loopnb:
 		}
 	}
 	if itpos == InvalidPos {
-		itpos = r.RandomPlace(PlaceItem)
+		itpos = r.RandomPlace(g, PlaceItem)
 	}
 	if itpos == InvalidPos {
-		itpos = r.RandomPlaces(PlaceSpecialOrStatic)
+		itpos = r.RandomPlaces(g, PlaceSpecialOrStatic)
 		if itpos == InvalidPos {
 			panic("no item")
 		}
@@ -1268,8 +1274,8 @@ func (dg *dgen) GenBanana(g *game) {
 		if count > 1000 {
 			panic("GenBanana")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := dg.d.Cell(pos)
 		if c.T == GroundCell && !dg.room[pos] {
@@ -1288,7 +1294,7 @@ func (dg *dgen) GenPotion(g *game, p potion) {
 		if count > 1000 {
 			return
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceItem)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceItem)
 	}
 	dg.d.SetCell(pos, PotionCell)
 	g.Objects.Potions[pos] = p
@@ -1301,8 +1307,8 @@ func (dg *dgen) OutsideGroundCell(g *game) position {
 		if count > 1500 {
 			panic("OutsideGroundCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() {
@@ -1322,8 +1328,8 @@ func (dg *dgen) OutsideCavernMiddleCell(g *game) position {
 		if count > 2500 {
 			return InvalidPos
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() {
@@ -1346,8 +1352,8 @@ func (dg *dgen) SatowalgaCell(g *game) position {
 		if count > 2000 {
 			return g.FreeCellForMonster()
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() {
@@ -1370,8 +1376,8 @@ func (dg *dgen) FoliageCell(g *game) position {
 		if count > 1500 {
 			return dg.OutsideCell(g)
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		if pos.Distance(g.Player.Pos) < DefaultLOSRange {
 			continue
@@ -1394,8 +1400,8 @@ func (dg *dgen) OutsideCell(g *game) position {
 		if count > 1500 {
 			panic("OutsideCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() {
@@ -1415,8 +1421,8 @@ func (dg *dgen) InsideCell(g *game) position {
 		if count > 1500 {
 			panic("InsideCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() {
@@ -1433,6 +1439,9 @@ func (dg *dgen) InsideCell(g *game) position {
 }
 
 func (dg *dgen) GenItem(g *game) {
+	if g.Depth > MaxDepth {
+		return
+	}
 	plan := g.GenPlan[g.Depth]
 	if plan != GenAmulet && plan != GenCloak {
 		return
@@ -1444,7 +1453,7 @@ func (dg *dgen) GenItem(g *game) {
 		if count > 1000 {
 			panic("GenItem")
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceItem)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceItem)
 	}
 	g.Dungeon.SetCell(pos, ItemCell)
 	var it item
@@ -1467,7 +1476,7 @@ func (dg *dgen) GenBarrierStone(g *game) {
 		if count > 1000 {
 			panic("GenBarrierStone")
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlaces(PlaceSpecialOrStatic)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlaces(g, PlaceSpecialOrStatic)
 	}
 	g.Dungeon.SetCell(pos, StoneCell)
 	g.Objects.Stones[pos] = SealStone
@@ -1481,7 +1490,7 @@ func (dg *dgen) GenMagara(g *game) {
 		if count > 1000 {
 			panic("GenMagara")
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceItem)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceItem)
 	}
 	g.Dungeon.SetCell(pos, MagaraCell)
 	mag := g.RandomMagara()
@@ -1494,7 +1503,7 @@ func (dg *dgen) GenStairs(g *game, st stair) {
 	best := 0
 	for i, r := range dg.rooms {
 		for j, pl := range r.places {
-			score := pl.pos.Distance(g.Player.Pos) + RandInt(20)
+			score := pl.pos.Distance(g.Player.Pos) + g.RandInt(20)
 			if !pl.used && pl.kind == PlaceSpecialStatic && score > best {
 				ri = i
 				pj = j
@@ -1524,7 +1533,7 @@ loop:
 			}
 		}
 		for j, pl := range r.places {
-			score := pl.pos.Distance(g.Player.Pos) + RandInt(20)
+			score := pl.pos.Distance(g.Player.Pos) + g.RandInt(20)
 			if !pl.used && pl.kind == PlaceSpecialStatic && score > best {
 				ri = i
 				pj = j
@@ -1548,12 +1557,25 @@ func (dg *dgen) GenBarrel(g *game) {
 		if count > 500 {
 			return
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceSpecialStatic)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceSpecialStatic)
 	}
 	g.Dungeon.SetCell(pos, BarrelCell)
 	g.Objects.Barrels[pos] = true
 }
 
+func (dg *dgen) GenAlarm(g *game) {
+	pos := InvalidPos
+	count := 0
+	for pos == InvalidPos {
+		count++
+		if count > 500 {
+			return
+		}
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlaces(g, PlaceSpecialOrStatic)
+	}
+	g.Dungeon.SetCell(pos, AlarmCell)
+}
+
 func (dg *dgen) GenTable(g *game) {
 	pos := InvalidPos
 	count := 0
@@ -1562,7 +1584,7 @@ func (dg *dgen) GenTable(g *game) {
 		if count > 500 {
 			return
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlaces(PlaceSpecialOrStatic)
+		pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlaces(g, PlaceSpecialOrStatic)
 	}
 	g.Dungeon.SetCell(pos, TableCell)
 }
@@ -1581,8 +1603,8 @@ func (dg *dgen) CaveGroundCell(g *game) position {
 		if count > 1000 {
 			panic("CaveGroundCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := dg.d.Cell(pos)
 		if (c.T == GroundCell || c.T == CavernCell || c.T == QueenRockCell) && !dg.room[pos] {
@@ -1603,11 +1625,11 @@ func (dg *dgen) RandomInStone(g *game) stone {
 		TeleportStone,
 		SensingStone,
 	}
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		// fog stone less often inside
 		instones = append(instones, FogStone)
 	}
-	return instones[RandInt(len(instones))]
+	return instones[g.RandInt(len(instones))]
 }
 
 func (dg *dgen) RandomOutStone(g *game) stone {
@@ -1619,18 +1641,18 @@ func (dg *dgen) RandomOutStone(g *game) stone {
 		TreeStone,
 		TeleportStone,
 	}
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		// sensing stone less often outside
 		instones = append(instones, SensingStone)
 	}
-	return instones[RandInt(len(instones))]
+	return instones[g.RandInt(len(instones))]
 }
 
 func (dg *dgen) GenStones(g *game) {
 	// Magical Stones
 	// TODO: move into dungeon generation
 	nstones := 3
-	switch RandInt(8) {
+	switch g.RandInt(8) {
 	case 1, 2, 3, 4, 5:
 		nstones++
 	case 6, 7:
@@ -1638,7 +1660,7 @@ func (dg *dgen) GenStones(g *game) {
 	}
 	inroom := 2
 	if g.Params.Stones[g.Depth] {
-		nstones += 4 + RandInt(3)
+		nstones += 4 + g.RandInt(3)
 		inroom += 2
 	}
 	if dg.layout == RandomSmallWalkCaveUrbanised {
@@ -1655,7 +1677,7 @@ func (dg *dgen) GenStones(g *game) {
 					pos = dg.CaveGroundCell(g)
 					break
 				}
-				pos = dg.rooms[RandInt(len(dg.rooms))].RandomPlace(PlaceStatic)
+				pos = dg.rooms[g.RandInt(len(dg.rooms))].RandomPlace(g, PlaceStatic)
 			}
 			st = dg.RandomInStone(g)
 		} else {
@@ -1686,7 +1708,7 @@ func (dg *dgen) GenCellularAutomataCaveMap() {
 func (dg *dgen) RunCellularAutomataCave() bool {
 	d := dg.d // TODO: reset
 	for i := range d.Cells {
-		r := RandInt(100)
+		r := dg.g.RandInt(100)
 		pos := idxtopos(i)
 		if r >= 45 {
 			d.SetCell(pos, GroundCell)
@@ -1720,8 +1742,8 @@ func (dg *dgen) RunCellularAutomataCave() bool {
 
 func (dg *dgen) GenLake(t terrain) {
 	walls := []position{}
-	xshift := 10 + RandInt(5)
-	yshift := 5 + RandInt(3)
+	xshift := 10 + dg.g.RandInt(5)
+	yshift := 5 + dg.g.RandInt(3)
 	for i := 0; i < DungeonNCells; i++ {
 		pos := idxtopos(i)
 		if pos.X < xshift || pos.Y < yshift || pos.X > DungeonWidth-xshift || pos.Y > DungeonHeight-yshift {
@@ -1733,13 +1755,13 @@ func (dg *dgen) GenLake(t terrain) {
 		}
 	}
 	count := 0
-	var bestpos = walls[RandInt(len(walls))]
+	var bestpos = walls[dg.g.RandInt(len(walls))]
 	var bestsize int
 	d := dg.d
 	for {
-		pos := walls[RandInt(len(walls))]
+		pos := walls[dg.g.RandInt(len(walls))]
 		_, size := d.Connected(pos, func(npos position) bool {
-			return npos.valid() && dg.d.Cell(npos).T == WallCell && !dg.room[npos] && pos.Distance(npos) < 10+RandInt(10)
+			return npos.valid() && dg.d.Cell(npos).T == WallCell && !dg.room[npos] && pos.Distance(npos) < 10+dg.g.RandInt(10)
 		})
 		count++
 		if Abs(bestsize-90) > Abs(size-90) {
@@ -1751,7 +1773,7 @@ func (dg *dgen) GenLake(t terrain) {
 		}
 	}
 	conn, _ := d.Connected(bestpos, func(npos position) bool {
-		return npos.valid() && dg.d.Cell(npos).T == WallCell && !dg.room[npos] && bestpos.Distance(npos) < 10+RandInt(10)
+		return npos.valid() && dg.d.Cell(npos).T == WallCell && !dg.room[npos] && bestpos.Distance(npos) < 10+dg.g.RandInt(10)
 	})
 	for pos := range conn {
 		d.SetCell(pos, t)
@@ -1770,10 +1792,10 @@ func (dg *dgen) GenQueenRock() {
 	if len(cavern) == 0 {
 		return
 	}
-	for i := 0; i < 1+RandInt(2); i++ {
-		pos := cavern[RandInt(len(cavern))]
+	for i := 0; i < 1+dg.g.RandInt(2); i++ {
+		pos := cavern[dg.g.RandInt(len(cavern))]
 		conn, _ := dg.d.Connected(pos, func(npos position) bool {
-			return npos.valid() && dg.d.Cell(npos).T == CavernCell && npos.Distance(pos) < 15+RandInt(5)
+			return npos.valid() && dg.d.Cell(npos).T == CavernCell && npos.Distance(pos) < 15+dg.g.RandInt(5)
 		})
 		for pos := range conn {
 			dg.d.SetCell(pos, QueenRockCell)
@@ -1792,7 +1814,7 @@ func (dg *dgen) Foliage(less bool) {
 		limit = 45
 	}
 	for i := range d.Cells {
-		r := RandInt(100)
+		r := dg.g.RandInt(100)
 		pos := idxtopos(i)
 		if r >= limit {
 			d.SetCell(pos, WallCell)
@@ -1845,14 +1867,14 @@ func (dg *dgen) GenCaveMap(size int) {
 	max := size
 	cells := 0
 	for cells < max {
-		pos := d.WallCell()
+		pos := d.WallCell(dg.g)
 		d.SetCell(pos, GroundCell)
 		cells++
 		curcells := 1
 		notValid := 0
 		lastValid := pos
 		for cells < max && curcells < 150 {
-			npos := pos.RandomNeighbor(false)
+			npos := pos.RandomNeighbor(dg.g, false)
 			if !pos.valid() && npos.valid() && d.Cell(npos).T == WallCell {
 				pos = lastValid
 				continue
@@ -1877,14 +1899,14 @@ func (dg *dgen) GenCaveMap(size int) {
 	dg.Foliage(false)
 }
 
-func (d *dungeon) DigBlock(block []position) []position {
-	pos := d.WallCell()
+func (d *dungeon) DigBlock(g *game, block []position) []position {
+	pos := d.WallCell(g)
 	block = block[:0]
 	count := 0
 	for {
 		count++
 		if count > 3000 && count%500 == 0 {
-			pos = d.WallCell()
+			pos = d.WallCell(g)
 			block = block[:0]
 		}
 		if count > 10000 {
@@ -1894,10 +1916,10 @@ func (d *dungeon) DigBlock(block []position) []position {
 		if d.HasFreeNeighbor(pos) {
 			break
 		}
-		pos = pos.RandomNeighbor(false)
+		pos = pos.RandomNeighbor(g, false)
 		if !pos.valid() {
 			block = block[:0]
-			pos = d.WallCell()
+			pos = d.WallCell(g)
 			continue
 		}
 		if !pos.valid() {
@@ -1924,7 +1946,7 @@ func (dg *dgen) GenTreeCaveMap() {
 	block := make([]position, 0, 64)
 loop:
 	for cells < max {
-		block = d.DigBlock(block)
+		block = d.DigBlock(dg.g, block)
 		if len(block) == 0 {
 			continue loop
 		}
@@ -1966,10 +1988,10 @@ loop:
 			break
 		}
 		for i := 0; i < 20; i++ {
-			r := dg.rooms[RandInt(len(dg.rooms)-1)]
+			r := dg.rooms[g.RandInt(len(dg.rooms)-1)]
 			for _, e := range r.places {
 				if e.kind == PlaceSpecialStatic {
-					pos = r.RandomPlace(pl)
+					pos = r.RandomPlace(g, pl)
 					break
 				}
 			}
@@ -1977,8 +1999,8 @@ loop:
 				break loop
 			}
 		}
-		r := dg.rooms[RandInt(len(dg.rooms)-1)]
-		pos = r.RandomPlace(pl)
+		r := dg.rooms[g.RandInt(len(dg.rooms)-1)]
+		pos = r.RandomPlace(g, pl)
 	}
 	bandinfo.Path = append(bandinfo.Path, pos)
 	bandinfo.Beh = BehGuard
@@ -1994,7 +2016,7 @@ func (dg *dgen) BandInfoGuardSpecial(g *game, band monsterBand) bandInfo {
 		if count > 1 {
 			panic("guard special")
 		}
-		pos = r.RandomPlace(PlacePatrolSpecial)
+		pos = r.RandomPlace(g, PlacePatrolSpecial)
 		if pos != InvalidPos {
 			break
 		}
@@ -2014,7 +2036,7 @@ func (dg *dgen) BandInfoPatrol(g *game, band monsterBand, pl placeKind) bandInfo
 			pos = dg.InsideCell(g)
 			break
 		}
-		pos = dg.rooms[RandInt(len(dg.rooms)-1)].RandomPlace(pl)
+		pos = dg.rooms[g.RandInt(len(dg.rooms)-1)].RandomPlace(g, pl)
 	}
 	target := InvalidPos
 	count = 0
@@ -2025,7 +2047,7 @@ func (dg *dgen) BandInfoPatrol(g *game, band monsterBand, pl placeKind) bandInfo
 			target = dg.InsideCell(g)
 			break
 		}
-		target = dg.rooms[RandInt(len(dg.rooms)-1)].RandomPlace(pl)
+		target = dg.rooms[g.RandInt(len(dg.rooms)-1)].RandomPlace(g, pl)
 	}
 	bandinfo.Path = append(bandinfo.Path, pos)
 	bandinfo.Path = append(bandinfo.Path, target)
@@ -2042,7 +2064,7 @@ func (dg *dgen) BandInfoPatrolSpecial(g *game, band monsterBand) bandInfo {
 		if count > 1 {
 			panic("patrol special")
 		}
-		pos = r.RandomPlace(PlacePatrolSpecial)
+		pos = r.RandomPlace(g, PlacePatrolSpecial)
 		if pos != InvalidPos {
 			break
 		}
@@ -2054,7 +2076,7 @@ func (dg *dgen) BandInfoPatrolSpecial(g *game, band monsterBand) bandInfo {
 		if count > 1 {
 			panic("patrol special")
 		}
-		target = r.RandomPlace(PlacePatrolSpecial)
+		target = r.RandomPlace(g, PlacePatrolSpecial)
 		if target != InvalidPos {
 			break
 		}
@@ -2130,7 +2152,7 @@ func (dg *dgen) PutMonsterBand(g *game, band monsterBand) bool {
 	if monsters == nil {
 		return false
 	}
-	awake := RandInt(5) > 0
+	awake := g.RandInt(5) > 0
 	var bdinf bandInfo
 	switch band {
 	case LoneYack, LoneWorm, PairYack:
@@ -2151,12 +2173,12 @@ func (dg *dgen) PutMonsterBand(g *game, band monsterBand) bool {
 		bdinf = dg.BandInfoSatowalga(g, band)
 	case SpecialLoneVampire, SpecialLoneNixe, SpecialLoneMilfid, SpecialLoneOricCelmist, SpecialLoneHarmonicCelmist, SpecialLoneHighGuard,
 		SpecialLoneHarpy, SpecialLoneTreeMushroom, SpecialLoneMirrorSpecter, SpecialLoneHazeCat, SpecialLoneSpider, SpecialLoneAcidMound, SpecialLoneDog, SpecialLoneExplosiveNadre, SpecialLoneYack, SpecialLoneBlinkingFrog:
-		if RandInt(5) > 0 {
+		if g.RandInt(5) > 0 {
 			bdinf = dg.BandInfoPatrolSpecial(g, band)
 		} else {
 			bdinf = dg.BandInfoGuardSpecial(g, band)
 		}
-		if !awake && RandInt(2) > 0 {
+		if !awake && g.RandInt(2) > 0 {
 			awake = true
 		}
 	case UniqueCrazyImp:
@@ -2179,7 +2201,7 @@ func (dg *dgen) PutMonsterBand(g *game, band monsterBand) bool {
 			mons.State = Wandering
 		}
 		g.Monsters = append(g.Monsters, mons)
-		mons.Init()
+		mons.Init(g)
 		mons.Index = len(g.Monsters) - 1
 		mons.Band = len(g.Bands) - 1
 		mons.PlaceAt(g, pos)
@@ -2190,12 +2212,15 @@ func (dg *dgen) PutMonsterBand(g *game, band monsterBand) bool {
 }
 
 func (dg *dgen) PutRandomBand(g *game, bands []monsterBand) bool {
-	return dg.PutMonsterBand(g, bands[RandInt(len(bands))])
+	return dg.PutMonsterBand(g, bands[g.RandInt(len(bands))])
 }
 
 func (dg *dgen) PutRandomBandN(g *game, bands []monsterBand, n int) {
+	if dg.common {
+		n = g.Params.Plan.ScaleBands(g, n)
+	}
 	for i := 0; i < n; i++ {
-		dg.PutMonsterBand(g, bands[RandInt(len(bands))])
+		dg.PutMonsterBand(g, bands[g.RandInt(len(bands))])
 	}
 }
 
@@ -2242,7 +2267,7 @@ func (dg *dgen) GenMonsters(g *game) {
 		case roomFrogs:
 			dg.PutRandomBandN(g, []monsterBand{SpecialLoneBlinkingFrog}, 2)
 		case roomMilfids:
-			switch RandInt(6) {
+			switch g.RandInt(6) {
 			case 0:
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneYack}, 2)
 			case 1:
@@ -2251,7 +2276,7 @@ func (dg *dgen) GenMonsters(g *game) {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneMilfid}, 2)
 			}
 		case roomCelmists:
-			switch RandInt(3) {
+			switch g.RandInt(3) {
 			case 0:
 				bandOricCelmists := []monsterBand{SpecialLoneOricCelmist}
 				dg.PutRandomBandN(g, bandOricCelmists, 2)
@@ -2265,19 +2290,19 @@ func (dg *dgen) GenMonsters(g *game) {
 				dg.PutRandomBandN(g, bandOricCelmists, 1)
 			}
 		case roomHarpies:
-			if RandInt(3) > 0 {
+			if g.RandInt(3) > 0 {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneHarpy}, 2)
 			} else {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneSpider}, 2)
 			}
 		case roomTreeMushrooms:
-			if RandInt(3) > 0 {
+			if g.RandInt(3) > 0 {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneTreeMushroom}, 2)
 			} else {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneHazeCat}, 2)
 			}
 		case roomMirrorSpecters:
-			switch RandInt(6) {
+			switch g.RandInt(6) {
 			case 0:
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneAcidMound}, 2)
 			case 1:
@@ -2286,13 +2311,13 @@ func (dg *dgen) GenMonsters(g *game) {
 				dg.PutRandomBandN(g, []monsterBand{SpecialLoneMirrorSpecter}, 2)
 			}
 		case roomShaedra:
-			if RandInt(3) > 0 {
+			if g.RandInt(3) > 0 {
 				dg.PutRandomBand(g, []monsterBand{SpecialLoneHighGuard})
 			} else {
 				dg.PutRandomBand(g, []monsterBand{SpecialLoneOricCelmist})
 			}
 		case roomArtifact:
-			switch RandInt(3) {
+			switch g.RandInt(3) {
 			case 0:
 				dg.PutRandomBand(g, []monsterBand{SpecialLoneHarmonicCelmist})
 			case 1:
@@ -2309,6 +2334,8 @@ func (dg *dgen) GenMonsters(g *game) {
 	if g.Depth == g.Params.CrazyImp {
 		dg.PutRandomBand(g, []monsterBand{UniqueCrazyImp})
 	}
+	dg.common = true
+	defer func() { dg.common = false }()
 	dg.PutRandomBandN(g, bandsButterfly, 2)
 	if dg.layout == RandomSmallWalkCaveUrbanised {
 		dg.PutRandomBandN(g, bandsGuard, 1+(g.Depth+1)/4)
@@ -2316,8 +2343,8 @@ func (dg *dgen) GenMonsters(g *game) {
 	switch g.Depth {
 	case 1:
 		// 8-9
-		if RandInt(2) == 0 {
-			if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandsGuard, 5)
 			} else {
 				dg.PutRandomBandN(g, bandsGuard, 4)
@@ -2326,7 +2353,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsAnimals, 3)
 		} else {
 			dg.PutRandomBandN(g, bandsGuard, 4)
-			if RandInt(5) > 0 {
+			if g.RandInt(5) > 0 {
 				dg.PutRandomBandN(g, bandsAnimals, 5)
 			} else {
 				dg.PutRandomBandN(g, bandsAnimals, 3)
@@ -2336,7 +2363,7 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 2:
 		// 10-11
 		dg.PutRandomBandN(g, bandsGuard, 3)
-		switch RandInt(5) {
+		switch g.RandInt(5) {
 		case 0, 1:
 			// 7
 			dg.PutRandomBandN(g, bandsBipeds, 1)
@@ -2351,7 +2378,7 @@ func (dg *dgen) GenMonsters(g *game) {
 		case 4:
 			// 8
 			dg.PutRandomBandN(g, bandsPlants, 3)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandFrog, 5)
 			} else {
 				dg.PutRandomBandN(g, bandYack, 5)
@@ -2361,10 +2388,10 @@ func (dg *dgen) GenMonsters(g *game) {
 		// 11-12
 		dg.PutRandomBandN(g, bandsHighGuard, 2)
 		dg.PutRandomBandN(g, bandsGuard, 4)
-		switch RandInt(5) {
+		switch g.RandInt(5) {
 		case 0, 1:
 			// 5
-			if RandInt(3) == 0 {
+			if g.RandInt(3) == 0 {
 				dg.PutRandomBandN(g, bandDog, 3)
 			} else {
 				dg.PutRandomBandN(g, bandsAnimals, 3)
@@ -2383,7 +2410,7 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 4:
 		// 12-13
 		dg.PutRandomBandN(g, bandsHighGuard, 2)
-		switch RandInt(5) {
+		switch g.RandInt(5) {
 		case 0, 1:
 			// 10
 			dg.PutRandomBandN(g, bandsGuard, 4)
@@ -2399,7 +2426,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsPlants, 1)
 		case 4:
 			dg.PutRandomBandN(g, bandsGuard, 4)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandOricCelmist, 4)
 			} else {
 				dg.PutRandomBandN(g, bandHarmonicCelmist, 4)
@@ -2409,9 +2436,9 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 5:
 		// 13-14
 		dg.PutRandomBandN(g, bandsHighGuard, 2)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 11
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandsGuard, 2)
 				dg.PutRandomBandN(g, bandGuardPair, 1)
 			} else {
@@ -2426,7 +2453,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsGuard, 2)
 			dg.PutRandomBandN(g, bandsAnimals, 3)
 			dg.PutRandomBandN(g, bandsBipeds, 2)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandOricCelmistPair, 1)
 			} else {
 				dg.PutRandomBandN(g, bandHarmonicCelmistPair, 1)
@@ -2437,13 +2464,13 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 6:
 		// 15-17
 		dg.PutRandomBandN(g, bandsHighGuard, 1)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 14
 			dg.PutRandomBandN(g, bandsGuard, 3)
 			dg.PutRandomBandN(g, bandsAnimals, 2)
 			dg.PutRandomBandN(g, bandsRare, 3)
 			dg.PutRandomBandN(g, bandsBipeds, 1)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandYackPair, 1)
 			} else {
 				dg.PutRandomBandN(g, bandWingedMilfidPair, 1)
@@ -2452,15 +2479,15 @@ func (dg *dgen) GenMonsters(g *game) {
 		} else {
 			// 16
 			dg.PutRandomBandN(g, bandsGuard, 2)
-			if RandInt(2) == 0 {
-				if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, bandYack, 8)
 				} else {
 					dg.PutRandomBandN(g, bandFrog, 8)
 				}
 			} else {
 				dg.PutRandomBandN(g, bandsRare, 2)
-				if RandInt(3) == 0 {
+				if g.RandInt(3) == 0 {
 					dg.PutRandomBandN(g, bandsAnimals, 4)
 					dg.PutRandomBandN(g, []monsterBand{PairWorm}, 1)
 				} else {
@@ -2473,10 +2500,10 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 7:
 		// 19
 		dg.PutRandomBandN(g, bandsHighGuard, 1)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 18
 			dg.PutRandomBandN(g, bandsGuard, 4)
-			if RandInt(3) == 0 {
+			if g.RandInt(3) == 0 {
 				dg.PutRandomBandN(g, bandDog, 4)
 				dg.PutRandomBandN(g, bandsAnimals, 2)
 			} else {
@@ -2491,11 +2518,11 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsGuard, 1)
 			dg.PutRandomBandN(g, bandsRare, 4)
 			dg.PutRandomBandN(g, bandsButterfly, 1)
-			if RandInt(3) == 0 {
+			if g.RandInt(3) == 0 {
 				dg.PutRandomBandN(g, bandNadre, 7)
 			} else {
 				dg.PutRandomBandN(g, bandsAnimals, 5)
-				if RandInt(2) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, []monsterBand{PairFrog}, 1)
 				} else {
 					dg.PutRandomBandN(g, []monsterBand{PairDog}, 1)
@@ -2506,12 +2533,12 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 8:
 		// 18-19
 		dg.PutRandomBandN(g, bandsHighGuard, 4)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 14
 			dg.PutRandomBandN(g, bandsGuard, 5)
 			dg.PutRandomBandN(g, bandsRare, 1)
-			if RandInt(3) == 0 {
-				if RandInt(2) == 0 {
+			if g.RandInt(3) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, bandOricCelmist, 6)
 				} else {
 					dg.PutRandomBandN(g, bandMadNixe, 6)
@@ -2532,11 +2559,11 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 9:
 		// 20-24
 		dg.PutRandomBandN(g, bandsHighGuard, 2)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 18
 			dg.PutRandomBandN(g, bandsGuard, 3)
-			if RandInt(2) == 0 {
-				switch RandInt(4) {
+			if g.RandInt(2) == 0 {
+				switch g.RandInt(4) {
 				case 0:
 					dg.PutRandomBandN(g, bandTreeMushroom, 4)
 					dg.PutRandomBandN(g, []monsterBand{PairTreeMushroom}, 1)
@@ -2563,7 +2590,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsButterfly, 2)
 			dg.PutRandomBandN(g, bandsGuard, 2)
 			dg.PutRandomBandN(g, bandsAnimals, 8)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandExplosiveNadrePair, 2)
 			} else {
 				dg.PutRandomBandN(g, bandYackPair, 2)
@@ -2575,12 +2602,12 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 10:
 		// 22
 		dg.PutRandomBandN(g, bandsHighGuard, 3)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 19
 			dg.PutRandomBandN(g, bandsGuard, 7)
 			dg.PutRandomBandN(g, bandGuardPair, 1)
 			dg.PutRandomBandN(g, bandsRare, 2)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandsBipeds, 8)
 			} else {
 				dg.PutRandomBandN(g, bandsBipeds, 4)
@@ -2589,7 +2616,7 @@ func (dg *dgen) GenMonsters(g *game) {
 		} else {
 			// 19
 			dg.PutRandomBandN(g, bandGuardPair, 1)
-			if RandInt(3) == 0 {
+			if g.RandInt(3) == 0 {
 				dg.PutRandomBandN(g, bandsGuard, 4)
 				dg.PutRandomBandN(g, bandVampire, 4)
 				dg.PutRandomBandN(g, []monsterBand{PairVampire}, 1)
@@ -2597,7 +2624,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			} else {
 				dg.PutRandomBandN(g, bandsGuard, 6)
 				dg.PutRandomBandN(g, bandsBipeds, 3)
-				if RandInt(2) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, []monsterBand{PairNixe}, 1)
 				} else {
 					dg.PutRandomBandN(g, []monsterBand{PairOricCelmist}, 1)
@@ -2611,12 +2638,12 @@ func (dg *dgen) GenMonsters(g *game) {
 	case 11:
 		// 26
 		dg.PutRandomBandN(g, bandsHighGuard, 5)
-		if RandInt(2) == 0 {
+		if g.RandInt(2) == 0 {
 			// 21
 			dg.PutRandomBandN(g, bandsGuard, 5)
 			dg.PutRandomBandN(g, bandsRare, 2)
-			if RandInt(3) == 0 {
-				if RandInt(2) == 0 {
+			if g.RandInt(3) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, bandOricCelmist, 5)
 				} else {
 					dg.PutRandomBandN(g, bandHarmonicCelmist, 5)
@@ -2625,10 +2652,10 @@ func (dg *dgen) GenMonsters(g *game) {
 			} else {
 				dg.PutRandomBandN(g, bandsBipeds, 10)
 			}
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandVampirePair, 1)
 			} else {
-				if RandInt(2) == 0 {
+				if g.RandInt(2) == 0 {
 					dg.PutRandomBandN(g, bandOricCelmistPair, 1)
 				} else {
 					dg.PutRandomBandN(g, bandHarmonicCelmistPair, 1)
@@ -2642,7 +2669,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, []monsterBand{PairGuard}, 1)
 			dg.PutRandomBandN(g, bandsRare, 1)
 			dg.PutRandomBandN(g, bandsBipeds, 7)
-			if RandInt(2) == 0 {
+			if g.RandInt(2) == 0 {
 				dg.PutRandomBandN(g, bandHarmonicCelmistPair, 1)
 			} else {
 				dg.PutRandomBandN(g, bandNixePair, 1)
@@ -2650,5 +2677,7 @@ func (dg *dgen) GenMonsters(g *game) {
 			dg.PutRandomBandN(g, bandsAnimals, 1)
 			dg.PutRandomBandN(g, bandsPlants, 1)
 		}
+	default:
+		dg.GenEndlessBands(g)
 	}
 }
@@ -29,7 +29,7 @@ func TestAutomataCave(t *testing.T) {
 		g.InitFirstLevel()
 		g.InitLevelStructures()
 		g.GenRoomTunnels(AutomataCave)
-		if !g.Dungeon.connex() {
+		if !g.Dungeon.connex(g) {
 			t.Errorf("Not connex:\n%s\n", g.Dungeon.String())
 		}
 	}
@@ -41,7 +41,7 @@ func TestRandomWalkCave(t *testing.T) {
 		g.InitFirstLevel()
 		g.InitLevelStructures()
 		g.GenRoomTunnels(RandomWalkCave)
-		if !g.Dungeon.connex() {
+		if !g.Dungeon.connex(g) {
 			t.Errorf("Not connex:\n%s\n", g.Dungeon.String())
 		}
 	}
@@ -53,7 +53,7 @@ func TestRandomWalkTreeCave(t *testing.T) {
 		g.InitFirstLevel()
 		g.InitLevelStructures()
 		g.GenRoomTunnels(RandomWalkTreeCave)
-		if !g.Dungeon.connex() {
+		if !g.Dungeon.connex(g) {
 			t.Errorf("Not connex:\n%s\n", g.Dungeon.String())
 		}
 	}
@@ -65,7 +65,7 @@ func TestRandomSmallWalkCaveUrbanised(t *testing.T) {
 		g.InitFirstLevel()
 		g.InitLevelStructures()
 		g.GenRoomTunnels(RandomSmallWalkCaveUrbanised)
-		if !g.Dungeon.connex() {
+		if !g.Dungeon.connex(g) {
 			t.Errorf("Not connex:\n%s\n", g.Dungeon.String())
 		}
 	}
@@ -77,7 +77,7 @@ func TestNaturalCave(t *testing.T) {
 		g.InitFirstLevel()
 		g.InitLevelStructures()
 		g.GenRoomTunnels(NaturalCave)
-		if !g.Dungeon.connex() {
+		if !g.Dungeon.connex(g) {
 			t.Errorf("Not connex:\n%s\n", g.Dungeon.String())
 		}
 	}
@@ -34,6 +34,8 @@ type config struct {
 	Tiles              bool
 	Version            string
 	ShowNumbers        bool
+	LevelDebrief       bool
+	StealthPaths       bool
 }
 
 func (c *config) ConfigSave() ([]byte, error) {
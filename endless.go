@@ -0,0 +1,117 @@
+package main
+
+// Endless descent: with the endless plan setting, stairs on the last level
+// lead to new levels beyond MaxDepth, generated one by one with growing
+// danger, until the player dies.
+
+const (
+	EndlessBaseDanger   = 200 // about the danger of common bands at MaxDepth
+	EndlessDangerStep   = 15  // danger percentage added with each level
+	EndlessDepthScore   = 100 // score for each level reached beyond MaxDepth
+	EndlessStealthScore = 50  // stealth bonus for each level beyond MaxDepth
+	EndlessSpottedMalus = 10  // stealth bonus lost each time the player is spotted
+)
+
+// Dangerousness returns the total dangerousness of the monsters of a band.
+func (band monsterBand) Dangerousness() int {
+	mbd := MonsBands[band]
+	if !mbd.Band {
+		return mbd.Monster.Dangerousness()
+	}
+	d := 0
+	for mk, n := range mbd.Distribution {
+		d += n * mk.Dangerousness()
+	}
+	return d
+}
+
+// EndlessDanger returns the dangerousness budget of common monster bands for
+// a level of the endless descent.
+func EndlessDanger(depth int) int {
+	return EndlessBaseDanger * (100 + EndlessDangerStep*(depth-MaxDepth)) / 100
+}
+
+var endlessBands = []monsterBand{
+	LoneGuard, LoneGuard, LoneGuard, LoneHighGuard, LoneHighGuard,
+	LoneYack, LoneWorm, LoneDog, LoneBlinkingFrog, LoneExplosiveNadre, LoneHarpy, LoneAcidMound,
+	LoneSatowalgaPlant,
+	LoneOricCelmist, LoneMirrorSpecter, LoneWingedMilfid, LoneMadNixe, LoneVampire, LoneHarmonicCelmist,
+	LoneTreeMushroom, LoneEarthDragon, LoneHazeCat, LoneSpider,
+	PairGuard, PairOricCelmist, PairHarmonicCelmist, PairVampire, PairNixe, PairWingedMilfid,
+}
+
+// GenEndlessBands places random common bands until the dangerousness budget
+// of the level is spent.
+func (dg *dgen) GenEndlessBands(g *game) {
+	budget := EndlessDanger(g.Depth) * g.Params.Plan.MonsterDensity / 100
+	for budget > 0 {
+		band := endlessBands[g.RandInt(len(endlessBands))]
+		dg.PutMonsterBand(g, band)
+		budget -= band.Dangerousness()
+	}
+}
+
+var endlessSpecialRooms = []specialRoom{
+	noSpecialRoom,
+	roomMilfids,
+	roomCelmists,
+	roomVampires,
+	roomHarpies,
+	roomTreeMushrooms,
+	roomNixes,
+	roomFrogs,
+	roomMirrorSpecters,
+}
+
+// PlanEndlessLevel adds to the start parameters the plan of the current
+// level, beyond MaxDepth. Deeper levels have fewer bananas and more often
+// several special events.
+func (g *game) PlanEndlessLevel() {
+	depth := g.Depth
+	for len(g.Params.Special) <= depth {
+		g.Params.Special = append(g.Params.Special, endlessSpecialRooms[g.RandInt(len(endlessSpecialRooms))])
+	}
+	beyond := depth - MaxDepth
+	if g.RandInt(3) > 0 {
+		ev := specialEvent(1 + g.RandInt(spEvMax))
+		g.Params.Event[depth] = ev
+		if g.RandInt(100) < Min(60, 10*beyond) {
+			other := specialEvent(1 + g.RandInt(spEvMax-1))
+			if other >= ev {
+				other++
+			}
+			if g.Params.ExtraEvent == nil {
+				g.Params.ExtraEvent = map[int]specialEvent{}
+			}
+			g.Params.ExtraEvent[depth] = other
+		}
+	}
+	if g.RandInt(100) < Min(75, 25*beyond) {
+		g.Params.ExtraBanana[depth] = -1
+	}
+	g.Params.Blocked[depth] = g.RandInt(4) == 0
+	g.Params.Stones[depth] = g.RandInt(3) == 0
+	g.Params.Trees[depth] = g.RandInt(4) == 0
+	g.Params.Tables[depth] = g.RandInt(4) == 0
+}
+
+// EndlessLevels returns the number of levels reached beyond MaxDepth.
+func (g *game) EndlessLevels() int {
+	return Max(0, Max(g.Depth, g.ExploredLevels)-MaxDepth)
+}
+
+// EndlessScore returns the score of an endless descent, based on the depth
+// reached and, for each level beyond MaxDepth, on how many unaware monsters
+// spotted the player there.
+func (g *game) EndlessScore() int {
+	levels := g.EndlessLevels()
+	score := EndlessDepthScore * levels
+	for depth := MaxDepth + 1; depth <= MaxDepth+levels; depth++ {
+		spotted := 0
+		if depth < len(g.Stats.DUSpotted) {
+			spotted = g.Stats.DUSpotted[depth]
+		}
+		score += Max(0, EndlessStealthScore-EndlessSpottedMalus*spotted)
+	}
+	return score
+}
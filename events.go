@@ -61,6 +61,7 @@ const (
 	TransparentEnd
 	DisguisedEnd
 	DispersalEnd
+	AlarmEnd
 )
 
 func (g *game) PushEvent(ev event) {
@@ -72,7 +73,7 @@ func (g *game) PushEvent(ev event) {
 // PushEventRandomIndex pushes a new even to the heap, with randomised Index.
 // Used so that monster turn order is not predictable.
 func (g *game) PushEventRandomIndex(ev event) {
-	iev := iEvent{Event: ev, Index: RandInt(10)}
+	iev := iEvent{Event: ev, Index: g.RandInt(10)}
 	heap.Push(g.Events, iev)
 }
 
@@ -120,6 +121,7 @@ var StatusEndMsgs = [...]string{
 	TransparentEnd:   "You are no longer transparent.",
 	DisguisedEnd:     "You are no longer disguised.",
 	DispersalEnd:     "You are no longer unstable.",
+	AlarmEnd:         "The alarm is over.",
 }
 
 var EndStatuses = [...]status{
@@ -134,6 +136,7 @@ var EndStatuses = [...]status{
 	TransparentEnd:   StatusTransparent,
 	DisguisedEnd:     StatusDisguised,
 	DispersalEnd:     StatusDispersal,
+	AlarmEnd:         StatusAlarm,
 }
 
 var StatusEndActions = [...]simpleAction{
@@ -148,6 +151,7 @@ var StatusEndActions = [...]simpleAction{
 	StatusTransparent:   TransparentEnd,
 	StatusDisguised:     DisguisedEnd,
 	StatusDispersal:     DispersalEnd,
+	StatusAlarm:         AlarmEnd,
 }
 
 func (sev *simpleEvent) Action(g *game) {
@@ -157,8 +161,11 @@ func (sev *simpleEvent) Action(g *game) {
 			g.ComputeNoise()
 			g.ComputeLOS() // TODO: optimize? most of the time almost redundant (unless on a tree)
 			g.ComputeMonsterLOS()
+			g.LayScent()
 		}
 		g.PlayerAgain = false
+		g.LastNoise = g.NoiseLevel
+		g.NoiseLevel = 0
 		g.LogNextTick = g.LogIndex
 		g.AutoNext = g.AutoPlayer(sev)
 		if g.AutoNext {
@@ -185,6 +192,7 @@ func (sev *simpleEvent) Action(g *game) {
 		g.Player.Statuses[st] -= DurationStatusStep
 		if g.Player.Statuses[st] <= 0 {
 			g.Player.Statuses[st] = 0
+			g.Emit(statusEnded{Status: st})
 			g.PrintStyled(StatusEndMsgs[sev.EAction], logStatusEnd)
 			g.ui.StatusEndAnimation()
 			switch sev.EAction {
@@ -215,6 +223,25 @@ const (
 	MonsLignificationEnd
 )
 
+func (ma monsterAction) String() string {
+	switch ma {
+	case MonsterTurn:
+		return "turn"
+	case MonsConfusionEnd:
+		return "confusion end"
+	case MonsExhaustionEnd:
+		return "exhaustion end"
+	case MonsParalysedEnd:
+		return "slowness end"
+	case MonsSatiatedEnd:
+		return "satiation end"
+	case MonsLignificationEnd:
+		return "lignification end"
+	default:
+		return "unknown"
+	}
+}
+
 type monsterEvent struct {
 	ERank   int
 	NMons   int
@@ -255,6 +282,7 @@ func (mev *monsterEvent) Action(g *game) {
 		mons := g.Monsters[mev.NMons]
 		if mons.Exists() {
 			mons.HandleTurn(g)
+			g.Emit(monsterActed{Mons: mons, Action: mev.EAction})
 		}
 	default:
 		mons := g.Monsters[mev.NMons]
@@ -271,6 +299,7 @@ func (mev *monsterEvent) Action(g *game) {
 		} else {
 			g.PushEvent(&monsterEvent{NMons: mev.NMons, ERank: mev.Rank() + DurationStatusStep, EAction: mev.EAction})
 		}
+		g.Emit(monsterActed{Mons: mons, Action: mev.EAction})
 	}
 }
 
@@ -329,14 +358,14 @@ func (cev *posEvent) Action(g *game) {
 			g.Printf("You see an oric barrier appear out of thin air.")
 			g.StopAuto()
 		}
-		g.PushEvent(&posEvent{ERank: cev.Rank() + DurationObstructionProgression + RandInt(DurationObstructionProgression/4),
+		g.PushEvent(&posEvent{ERank: cev.Rank() + DurationObstructionProgression + g.RandInt(DurationObstructionProgression/4),
 			EAction: ObstructionProgression})
 	case FireProgression:
 		if _, ok := g.Clouds[cev.Pos]; !ok {
 			break
 		}
 		for _, pos := range g.Dungeon.FreeNeighbors(cev.Pos) {
-			if RandInt(10) == 0 {
+			if g.RandInt(10) == 0 {
 				continue
 			}
 			g.Burn(pos)
@@ -359,7 +388,7 @@ func (cev *posEvent) Action(g *game) {
 	case MistProgression:
 		pos := g.FreePassableCell()
 		g.Fog(pos, 1)
-		g.PushEvent(&posEvent{ERank: cev.Rank() + DurationMistProgression + RandInt(DurationMistProgression/4),
+		g.PushEvent(&posEvent{ERank: cev.Rank() + DurationMistProgression + g.RandInt(DurationMistProgression/4),
 			EAction: MistProgression})
 	case Earthquake:
 		g.PrintStyled("The earth suddenly shakes with force!", logSpecial)
@@ -372,7 +401,7 @@ func (cev *posEvent) Action(g *game) {
 			if !c.T.IsDiggable() || !g.Dungeon.HasFreeNeighbor(pos) {
 				continue
 			}
-			if cev.Pos.Distance(pos) > RandInt(35) || RandInt(2) == 0 {
+			if cev.Pos.Distance(pos) > g.RandInt(35) || g.RandInt(2) == 0 {
 				continue
 			}
 			g.Dungeon.SetCell(pos, RubbleCell)
@@ -397,7 +426,7 @@ func (cev *posEvent) Action(g *game) {
 			g.NoiseIllusion[cev.Pos] = true
 			dij := &gridPath{dungeon: g.Dungeon}
 			g.MakeNoise(OricExplosionNoise, cev.Pos)
-			nm := Dijkstra(dij, []position{cev.Pos}, 7)
+			nm := g.Dijkstra(dij, []position{cev.Pos}, 7)
 			fogs := []position{}
 			terrains := []terrain{}
 			nm.iter(cev.Pos, func(n *node) {
@@ -405,6 +434,7 @@ func (cev *posEvent) Action(g *game) {
 				if !c.T.IsDiggable() {
 					return
 				}
+				g.Tamper(n.Pos)
 				g.Dungeon.SetCell(n.Pos, RubbleCell)
 				g.Stats.Digs++
 				if g.Player.Sees(n.Pos) {
@@ -430,7 +460,7 @@ func (cev *posEvent) Action(g *game) {
 
 func (g *game) NightFog(at position, radius int, ev event) {
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{at}, radius)
+	nm := g.Dijkstra(dij, []position{at}, radius)
 	nm.iter(at, func(n *node) {
 		pos := n.Pos
 		_, ok := g.Clouds[pos]
@@ -452,7 +482,7 @@ func (g *game) MakeCreatureSleep(pos position) {
 		return
 	}
 	mons := g.MonsterAt(pos)
-	if !mons.Exists() || (RandInt(2) == 0 && mons.Status(MonsExhausted)) {
+	if !mons.Exists() || (g.RandInt(2) == 0 && mons.Status(MonsExhausted)) {
 		// do not always make already exhausted monsters sleep (they were probably awaken)
 		return
 	}
@@ -462,7 +492,7 @@ func (g *game) MakeCreatureSleep(pos position) {
 	}
 	mons.State = Resting
 	mons.Dir = NoDir
-	mons.ExhaustTime(g, 4+RandInt(2))
+	mons.ExhaustTime(g, 4+g.RandInt(2))
 }
 
 func (g *game) Burn(pos position) {
@@ -474,6 +504,7 @@ func (g *game) Burn(pos position) {
 		return
 	}
 	g.Stats.Burns++
+	g.Tamper(pos)
 	switch c.T {
 	case DoorCell:
 		g.Print("The door vanishes in magical flames.")
@@ -529,6 +560,7 @@ const (
 	DurationTurn                   = 1
 	DurationStatusStep             = 1
 	DurationNightFog               = 15
+	DurationDoorSwing              = 2
 )
 
 func (g *game) RenewEvent(delay int) {
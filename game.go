@@ -1,8 +1,12 @@
 package main
 
-import "container/heap"
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+)
 
-var Version string = "v0.3"
+var Version string = "v0.4"
 
 type game struct {
 	Dungeon            *dungeon
@@ -21,6 +25,7 @@ type game struct {
 	Objects            objects
 	Clouds             map[position]cloud
 	MagicalBarriers    map[position]terrain
+	Tampered           map[position]tampering // cells changed by the player
 	GeneratedLore      map[int]bool
 	GeneratedMagaras   []magaraKind
 	GeneratedCloaks    []item
@@ -28,7 +33,11 @@ type game struct {
 	GenPlan            [MaxDepth + 1]genFlavour
 	TerrainKnowledge   map[position]terrain
 	ExclusionsMap      map[position]bool
+	Notes              map[position]string
 	Noise              map[position]bool
+	NoiseLevel         int   // loudest noise made at the player position this turn
+	LastNoise          int   // loudest noise made at the player position last turn
+	Scent              []int // turn at which the scent of the player vanishes
 	NoiseIllusion      map[position]bool
 	LastMonsterKnownAt map[position]*monster
 	MonsterLOS         map[position]bool
@@ -56,11 +65,27 @@ type game struct {
 	Quit               bool
 	Wizard             bool
 	WizardMode         wizardMode
+	WizardScent        bool
+	ID                 string           // identifier of the game, kept in saves
+	Daily              string           // date of the daily challenge, if any
+	Conducts           map[conduct]bool // declared conducts
+	Levels             map[int]*level   // levels left by the player, if they can be revisited
+	Draft              *startingKit     // starting kit chosen in the draft, if any
+	Practice           bool             // practice mode, with checkpoints and rewind
+	Checkpoints        []checkpoint
+	Rewinds            int
 	Version            string
 	Places             places
 	Params             startParams
 	//Opts                startOpts
 	ui                *gameui
+	config            config
+	pathCache         *pathCache
+	dataDir           string
+	observers         []observer
+	telemetry         *telemetry
+	rewind            int // checkpoint to rewind to after the turn, plus one
+	rng               *rand.Rand
 	LiberatedShaedra  bool
 	LiberatedArtifact bool
 	PlayerAgain       bool
@@ -82,6 +107,7 @@ type startParams struct {
 	Blocked      map[int]bool
 	Special      []specialRoom
 	Event        map[int]specialEvent
+	ExtraEvent   map[int]specialEvent // second event of some endless levels
 	Windows      map[int]bool
 	Trees        map[int]bool
 	Holes        map[int]bool
@@ -93,6 +119,8 @@ type startParams struct {
 	HealthPotion map[int]bool
 	MappingStone map[int]bool
 	CrazyImp     int
+	Difficulty   difficulty
+	Plan         runPlan
 }
 
 type places struct {
@@ -118,8 +146,8 @@ func (g *game) FreePassableCell() position {
 		if count > 1000 {
 			panic("FreeCell")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := d.Cell(pos)
 		if !c.IsPassable() {
@@ -157,8 +185,8 @@ func (g *game) FreeCellForMonster() position {
 		if count > 1000 {
 			panic("FreeCellForMonster")
 		}
-		x := RandInt(DungeonWidth)
-		y := RandInt(DungeonHeight)
+		x := g.RandInt(DungeonWidth)
+		y := g.RandInt(DungeonHeight)
 		pos := position{x, y}
 		c := d.Cell(pos)
 		if !c.IsPassable() {
@@ -183,7 +211,7 @@ func (g *game) FreeCellForBandMonster(pos position) position {
 			return g.FreeCellForMonster()
 		}
 		neighbors := g.Dungeon.FreeNeighbors(pos)
-		r := RandInt(len(neighbors))
+		r := g.RandInt(len(neighbors))
 		pos = neighbors[r]
 		if g.Player != nil && g.Player.Pos.Distance(pos) < 8 {
 			continue
@@ -210,27 +238,27 @@ func (g *game) GenDungeon() {
 	switch g.Depth {
 	case 2, 6, 7:
 		ml = RandomWalkCave
-		if RandInt(3) == 0 {
+		if g.RandInt(3) == 0 {
 			ml = NaturalCave
 		}
 	case 4, 10, 11:
 		ml = RandomWalkTreeCave
-		if RandInt(4) == 0 && g.Depth < 11 {
+		if g.RandInt(4) == 0 && g.Depth < 11 {
 			ml = RandomSmallWalkCaveUrbanised
-		} else if g.Depth == 11 && RandInt(2) == 0 {
+		} else if g.Depth == 11 && g.RandInt(2) == 0 {
 			ml = RandomSmallWalkCaveUrbanised
 		}
 	case 9:
-		switch RandInt(4) {
+		switch g.RandInt(4) {
 		case 0:
 			ml = NaturalCave
 		case 1:
 			ml = RandomWalkCave
 		}
 	default:
-		if RandInt(10) == 0 {
+		if g.RandInt(10) == 0 {
 			ml = RandomSmallWalkCaveUrbanised
-		} else if RandInt(10) == 0 {
+		} else if g.RandInt(10) == 0 {
 			ml = NaturalCave
 		}
 	}
@@ -254,8 +282,12 @@ func (g *game) InitPlayer() {
 		magara{},
 	}
 	g.GeneratedMagaras = []magaraKind{}
-	g.Player.Magaras[0] = g.RandomStartingMagara()
-	g.GeneratedMagaras = append(g.GeneratedMagaras, g.Player.Magaras[0].Kind)
+	if g.Draft != nil {
+		g.EquipStartingKit(g.Draft)
+	} else {
+		g.Player.Magaras[0] = g.RandomStartingMagara()
+		g.GeneratedMagaras = append(g.GeneratedMagaras, g.Player.Magaras[0].Kind)
+	}
 	g.Player.Inventory.Misc = MarevorMagara
 	// Testing
 	//g.Player.Magaras[1] = magara{Kind: DispersalMagara, Charges: 10}
@@ -272,9 +304,9 @@ const (
 	GenCloak
 )
 
-func PutRandomLevels(m map[int]bool, n int) {
+func (g *game) PutRandomLevels(m map[int]bool, n int) {
 	for i := 0; i < n; i++ {
-		j := 1 + RandInt(MaxDepth)
+		j := 1 + g.RandInt(MaxDepth)
 		if !m[j] {
 			m[j] = true
 		} else {
@@ -285,6 +317,9 @@ func PutRandomLevels(m map[int]bool, n int) {
 
 func (g *game) InitFirstLevel() {
 	g.Version = Version
+	if g.ID == "" {
+		g.ID = NewGameID()
+	}
 	g.Depth++ // start at 1
 	g.InitPlayer()
 	g.AutoTarget = InvalidPos
@@ -310,19 +345,23 @@ func (g *game) InitFirstLevel() {
 		10: GenCloak,
 		11: GenNothing,
 	}
+	if g.Params.Plan == (runPlan{}) {
+		g.SetDifficulty(g.Params.Difficulty, runPlan{})
+	}
+	plan := g.Params.Plan
 	g.Params.Lore = map[int]bool{}
-	PutRandomLevels(g.Params.Lore, 8)
+	g.PutRandomLevels(g.Params.Lore, plan.LoreLevels)
 	g.Params.HealthPotion = map[int]bool{}
-	PutRandomLevels(g.Params.HealthPotion, 5)
+	g.PutRandomLevels(g.Params.HealthPotion, plan.HealthPotions)
 	g.Params.MappingStone = map[int]bool{}
-	PutRandomLevels(g.Params.MappingStone, 3)
+	g.PutRandomLevels(g.Params.MappingStone, plan.MappingStones)
 	g.Params.Blocked = map[int]bool{}
-	if RandInt(10) > 0 {
-		g.Params.Blocked[2+RandInt(WinDepth-2)] = true
+	if g.RandInt(100) < plan.BlockedChance {
+		g.Params.Blocked[2+g.RandInt(WinDepth-2)] = true
 	}
-	if RandInt(10) == 0 {
+	if plan.BlockedChance > 0 && g.RandInt(10) == 0 {
 		// a second one sometimes!
-		g.Params.Blocked[2+RandInt(WinDepth-2)] = true
+		g.Params.Blocked[2+g.RandInt(WinDepth-2)] = true
 	}
 	g.Params.Special = []specialRoom{
 		noSpecialRoom, // unused (depth 0)
@@ -338,112 +377,121 @@ func (g *game) InitFirstLevel() {
 		roomMirrorSpecters,
 		roomArtifact,
 	}
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		g.Params.Special[5] = roomNixes
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		if g.Params.Special[5] == roomNixes {
 			g.Params.Special[9] = roomVampires
 		} else {
 			g.Params.Special[9] = roomNixes
 		}
 	}
-	if RandInt(4) == 0 {
-		if RandInt(2) == 0 {
+	if g.RandInt(4) == 0 {
+		if g.RandInt(2) == 0 {
 			g.Params.Special[3] = roomFrogs
 		} else {
 			g.Params.Special[7] = roomFrogs
 		}
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		g.Params.Special[10], g.Params.Special[5] = g.Params.Special[5], g.Params.Special[10]
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		g.Params.Special[6], g.Params.Special[7] = g.Params.Special[7], g.Params.Special[6]
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		g.Params.Special[3], g.Params.Special[4] = g.Params.Special[4], g.Params.Special[3]
 	}
 	g.Params.Event = map[int]specialEvent{}
 	for i := 0; i < 2; i++ {
-		g.Params.Event[2+5*i+RandInt(5)] = specialEvent(1 + RandInt(spEvMax))
+		g.Params.Event[2+5*i+g.RandInt(5)] = specialEvent(1 + g.RandInt(spEvMax))
 	}
-	g.Params.Event[2+RandInt(MaxDepth-1)] = NormalLevel
+	g.Params.Event[2+g.RandInt(MaxDepth-1)] = NormalLevel
 	g.Params.FakeStair = map[int]bool{}
-	if RandInt(MaxDepth) > 0 {
-		g.Params.FakeStair[2+RandInt(MaxDepth-2)] = true
-		if RandInt(MaxDepth) > MaxDepth/2 {
-			g.Params.FakeStair[2+RandInt(MaxDepth-2)] = true
-			if RandInt(MaxDepth) == 0 {
-				g.Params.FakeStair[2+RandInt(MaxDepth-2)] = true
+	if g.RandInt(MaxDepth) > 0 {
+		g.Params.FakeStair[2+g.RandInt(MaxDepth-2)] = true
+		if g.RandInt(MaxDepth) > MaxDepth/2 {
+			g.Params.FakeStair[2+g.RandInt(MaxDepth-2)] = true
+			if g.RandInt(MaxDepth) == 0 {
+				g.Params.FakeStair[2+g.RandInt(MaxDepth-2)] = true
 			}
 		}
 	}
 	g.Params.ExtraBanana = map[int]int{}
 	for i := 0; i < 2; i++ {
-		g.Params.ExtraBanana[1+5*i+RandInt(5)]++
+		g.Params.ExtraBanana[1+5*i+g.RandInt(5)]++
 	}
 	for i := 0; i < 2; i++ {
-		g.Params.ExtraBanana[1+5*i+RandInt(5)]--
+		g.Params.ExtraBanana[1+5*i+g.RandInt(5)]--
+	}
+	for i := 0; i < plan.ExtraBananas; i++ {
+		g.Params.ExtraBanana[1+g.RandInt(MaxDepth)]++
+	}
+	for i := 0; i > plan.ExtraBananas; i-- {
+		g.Params.ExtraBanana[1+g.RandInt(MaxDepth)]--
 	}
 
 	g.Params.Windows = map[int]bool{}
-	if RandInt(MaxDepth) > MaxDepth/2 {
-		g.Params.Windows[2+RandInt(MaxDepth-1)] = true
-		if RandInt(MaxDepth) == 0 {
-			g.Params.Windows[2+RandInt(MaxDepth-1)] = true
+	if g.RandInt(MaxDepth) > MaxDepth/2 {
+		g.Params.Windows[2+g.RandInt(MaxDepth-1)] = true
+		if g.RandInt(MaxDepth) == 0 {
+			g.Params.Windows[2+g.RandInt(MaxDepth-1)] = true
 		}
 	}
 	g.Params.Holes = map[int]bool{}
-	if RandInt(MaxDepth) > MaxDepth/2 {
-		g.Params.Holes[2+RandInt(MaxDepth-1)] = true
-		if RandInt(MaxDepth) == 0 {
-			g.Params.Holes[2+RandInt(MaxDepth-1)] = true
+	if g.RandInt(MaxDepth) > MaxDepth/2 {
+		g.Params.Holes[2+g.RandInt(MaxDepth-1)] = true
+		if g.RandInt(MaxDepth) == 0 {
+			g.Params.Holes[2+g.RandInt(MaxDepth-1)] = true
 		}
 	}
 	g.Params.Trees = map[int]bool{}
-	if RandInt(MaxDepth) > MaxDepth/2 {
-		g.Params.Trees[2+RandInt(MaxDepth-1)] = true
-		if RandInt(MaxDepth) == 0 {
-			g.Params.Trees[2+RandInt(MaxDepth-1)] = true
+	if g.RandInt(MaxDepth) > MaxDepth/2 {
+		g.Params.Trees[2+g.RandInt(MaxDepth-1)] = true
+		if g.RandInt(MaxDepth) == 0 {
+			g.Params.Trees[2+g.RandInt(MaxDepth-1)] = true
 		}
 	}
 	g.Params.Tables = map[int]bool{}
-	if RandInt(MaxDepth) > MaxDepth/2 {
-		g.Params.Tables[2+RandInt(MaxDepth-1)] = true
-		if RandInt(MaxDepth) == 0 {
-			g.Params.Tables[2+RandInt(MaxDepth-1)] = true
+	if g.RandInt(MaxDepth) > MaxDepth/2 {
+		g.Params.Tables[2+g.RandInt(MaxDepth-1)] = true
+		if g.RandInt(MaxDepth) == 0 {
+			g.Params.Tables[2+g.RandInt(MaxDepth-1)] = true
 		}
 	}
 	g.Params.NoMagara = map[int]bool{}
 	g.Params.NoMagara[WinDepth] = true
 	g.Params.Stones = map[int]bool{}
-	if RandInt(MaxDepth) > MaxDepth/2 {
-		g.Params.Stones[2+RandInt(MaxDepth-1)] = true
-		if RandInt(MaxDepth) == 0 {
-			g.Params.Stones[2+RandInt(MaxDepth-1)] = true
+	if g.RandInt(MaxDepth) > MaxDepth/2 {
+		g.Params.Stones[2+g.RandInt(MaxDepth-1)] = true
+		if g.RandInt(MaxDepth) == 0 {
+			g.Params.Stones[2+g.RandInt(MaxDepth-1)] = true
 		}
 	}
-	permi := RandInt(WinDepth - 1)
+	permi := g.RandInt(WinDepth - 1)
 	switch permi {
 	case 0, 1, 2, 3:
 		g.GenPlan[permi+1], g.GenPlan[permi+2] = g.GenPlan[permi+2], g.GenPlan[permi+1]
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		g.GenPlan[6], g.GenPlan[7] = g.GenPlan[7], g.GenPlan[6]
 	}
-	if RandInt(4) == 0 {
+	if g.RandInt(4) == 0 {
 		g.GenPlan[MaxDepth-1], g.GenPlan[MaxDepth] = g.GenPlan[MaxDepth], g.GenPlan[MaxDepth-1]
 	}
-	g.Params.CrazyImp = 2 + RandInt(MaxDepth-2)
+	g.Params.CrazyImp = 2 + g.RandInt(MaxDepth-2)
 }
 
 func (g *game) InitLevelStructures() {
 	g.MonstersPosCache = make([]int, DungeonNCells)
 	g.Noise = map[position]bool{}
+	g.Scent = make([]int, DungeonNCells)
 	g.TerrainKnowledge = map[position]terrain{}
 	g.ExclusionsMap = map[position]bool{}
+	g.Notes = map[position]string{}
 	g.MagicalBarriers = map[position]terrain{}
+	g.Tampered = map[position]tampering{}
 	g.LastMonsterKnownAt = map[position]*monster{}
 	g.Objects.Magaras = map[position]magara{}
 	g.Objects.Lore = map[position]int{}
@@ -470,11 +518,18 @@ func (g *game) InitLevel() {
 	} else if !Testing {
 		g.ui.DrawLoading()
 	}
+	g.Stats.GrowDepths(Max(g.Depth, MaxDepth))
 
 	g.InitLevelStructures()
+	if g.Depth > MaxDepth {
+		g.PlanEndlessLevel()
+	}
 
 	// Dungeon terrain
 	g.GenDungeon()
+	if g.Params.Plan.Revisits && g.Depth > 1 {
+		g.PutUpStair()
+	}
 
 	// Events
 	if g.Depth == 1 {
@@ -493,49 +548,77 @@ func (g *game) InitLevel() {
 	for i := range g.Monsters {
 		g.PushEventRandomIndex(&monsterEvent{ERank: g.Turn, EAction: MonsterTurn, NMons: i})
 	}
-	switch g.Params.Event[g.Depth] {
+	for _, ev := range g.LevelEvents() {
+		g.StartLevelEvent(ev)
+	}
+
+	// initialize LOS
+	if g.Depth == 1 {
+		g.PrintStyled("► Press ? for help on keys or use the mouse and [buttons].", logSpecial)
+	}
+	if g.Depth == WinDepth {
+		g.PrintStyled("Finally! Shaedra should be imprisoned somewhere around here.", logSpecial)
+	} else if g.Depth == MaxDepth {
+		g.PrintStyled("This the bottom floor, you now have to look for the artifact.", logSpecial)
+		if g.Params.Plan.Endless {
+			g.PrintStyled("Stairs lead further down to the endless descent.", logSpecial)
+		}
+	} else if g.Depth > MaxDepth {
+		g.PrintfStyled("Endless descent: depth %d.", logSpecial, g.Depth)
+		g.StoryPrintf("Reached depth %d of the endless descent", g.Depth)
+	}
+	g.ComputeLOS()
+	g.MakeMonstersAware()
+	g.Emit(levelEntered{Depth: g.Depth})
+}
+
+// LevelEvents returns the special events of the current level.
+func (g *game) LevelEvents() []specialEvent {
+	evs := []specialEvent{g.Params.Event[g.Depth]}
+	if ev, ok := g.Params.ExtraEvent[g.Depth]; ok {
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+// StartLevelEvent starts a special event of the current level.
+func (g *game) StartLevelEvent(ev specialEvent) {
+	switch ev {
 	case UnstableLevel:
 		g.PrintStyled("Uncontrolled oric magic fills the air on this level.", logSpecial)
 		g.StoryPrint("Special event: magically unstable level")
 		for i := 0; i < 7; i++ {
-			g.PushEvent(&posEvent{ERank: g.Turn + DurationObstructionProgression + RandInt(DurationObstructionProgression/2),
+			g.PushEvent(&posEvent{ERank: g.Turn + DurationObstructionProgression + g.RandInt(DurationObstructionProgression/2),
 				EAction: ObstructionProgression})
 		}
 	case MistLevel:
 		g.PrintStyled("The air seems dense on this level.", logSpecial)
 		g.StoryPrint("Special event: mist level")
 		for i := 0; i < 20; i++ {
-			g.PushEvent(&posEvent{ERank: g.Turn + DurationMistProgression + RandInt(DurationMistProgression/2),
+			g.PushEvent(&posEvent{ERank: g.Turn + DurationMistProgression + g.RandInt(DurationMistProgression/2),
 				EAction: MistProgression})
 		}
 	case EarthquakeLevel:
 		g.PushEvent(&posEvent{
-			ERank:   g.Turn + 10 + RandInt(50),
+			ERank:   g.Turn + 10 + g.RandInt(50),
 			EAction: Earthquake,
-			Pos:     position{DungeonWidth/2 - 15 + RandInt(30), DungeonHeight/2 - 5 + RandInt(10)},
+			Pos:     position{DungeonWidth/2 - 15 + g.RandInt(30), DungeonHeight/2 - 5 + g.RandInt(10)},
 		})
 	}
-
-	// initialize LOS
-	if g.Depth == 1 {
-		g.PrintStyled("► Press ? for help on keys or use the mouse and [buttons].", logSpecial)
-	}
-	if g.Depth == WinDepth {
-		g.PrintStyled("Finally! Shaedra should be imprisoned somewhere around here.", logSpecial)
-	} else if g.Depth == MaxDepth {
-		g.PrintStyled("This the bottom floor, you now have to look for the artifact.", logSpecial)
-	}
-	g.ComputeLOS()
-	g.MakeMonstersAware()
 }
 
 func (g *game) CleanEvents() {
 	evq := &eventQueue{}
 	for g.Events.Len() > 0 {
 		iev := g.PopIEvent()
-		switch iev.Event.(type) {
+		switch ev := iev.Event.(type) {
 		case *monsterEvent:
 		case *posEvent:
+		case *simpleEvent:
+			if ev.EAction != AlarmEnd {
+				// alarms only concern the level where they were raised
+				heap.Push(evq, iev)
+			}
 		default:
 			heap.Push(evq, iev)
 		}
@@ -566,61 +649,19 @@ const (
 
 func (g *game) Descend(style descendstyle) bool {
 	g.LevelStats()
-	if g.Stats.DUSpotted[g.Depth] < 3 {
-		AchStealthNovice.Get(g)
-	}
-	if g.Depth >= 3 {
-		if g.Stats.DRests[g.Depth] == 0 && g.Stats.DRests[g.Depth-1] == 0 {
-			AchInsomniaNovice.Get(g)
-		}
-	}
-	if g.Depth >= 5 {
-		if g.Stats.DRests[g.Depth] == 0 && g.Stats.DRests[g.Depth-1] == 0 && g.Stats.DRests[g.Depth-2] == 0 &&
-			g.Stats.DRests[g.Depth-3] == 0 {
-			AchInsomniaInitiate.Get(g)
-		}
-	}
-	if g.Depth >= 8 {
-		if g.Stats.DRests[g.Depth] == 0 && g.Stats.DRests[g.Depth-1] == 0 && g.Stats.DRests[g.Depth-2] == 0 &&
-			g.Stats.DRests[g.Depth-3] == 0 && g.Stats.DRests[g.Depth-4] == 0 && g.Stats.DRests[g.Depth-5] == 0 {
-			AchInsomniaMaster.Get(g)
-		}
-	}
-	if g.Depth >= 3 {
-		if g.Stats.DMagaraUses[g.Depth] == 0 && g.Stats.DMagaraUses[g.Depth-1] == 0 {
-			AchAntimagicNovice.Get(g)
-		}
-	}
-	if g.Depth >= 5 {
-		if g.Stats.DMagaraUses[g.Depth] == 0 && g.Stats.DMagaraUses[g.Depth-1] == 0 && g.Stats.DMagaraUses[g.Depth-2] == 0 &&
-			g.Stats.DMagaraUses[g.Depth-3] == 0 {
-			AchAntimagicInitiate.Get(g)
-		}
-	}
-	if g.Depth >= 8 {
-		if g.Stats.DMagaraUses[g.Depth] == 0 && g.Stats.DMagaraUses[g.Depth-1] == 0 && g.Stats.DMagaraUses[g.Depth-2] == 0 &&
-			g.Stats.DMagaraUses[g.Depth-3] == 0 && g.Stats.DMagaraUses[g.Depth-4] == 0 && g.Stats.DMagaraUses[g.Depth-5] == 0 {
-			AchAntimagicMaster.Get(g)
-		}
-	}
-	if g.Depth >= 5 {
-		if g.Stats.DUSpotted[g.Depth] < 3 && g.Stats.DSpotted[g.Depth-1] < 3 && g.Stats.DSpotted[g.Depth-2] < 3 {
-			AchStealthInitiate.Get(g)
-		}
-	}
-	if g.Depth >= 8 {
-		if g.Stats.DUSpotted[g.Depth] < 3 && g.Stats.DUSpotted[g.Depth-1] < 3 && g.Stats.DSpotted[g.Depth-2] < 3 &&
-			g.Stats.DSpotted[g.Depth-3] < 3 {
-			AchStealthMaster.Get(g)
-		}
-	}
+	g.CheckAchievements(LevelEndHook)
 	c := g.Dungeon.Cell(g.Player.Pos)
 	if c.T == StairCell && g.Objects.Stairs[g.Player.Pos] == WinStair {
 		g.StoryPrint("Escaped!")
-		g.ExploredLevels = g.Depth
+		g.Emit(gameEnded{Won: true})
+		g.ExploredLevels = Max(g.ExploredLevels, g.Depth)
 		g.Depth = -1
 		return true
 	}
+	var debrief string
+	if g.config.LevelDebrief {
+		debrief = g.LevelDebrief()
+	}
 	if style != DescendNormal {
 		// TODO: add animation?
 		g.Print("You fall into the abyss. It hurts!")
@@ -629,14 +670,32 @@ func (g *game) Descend(style descendstyle) bool {
 		g.Print("You descend deeper in the dungeon.")
 		g.StoryPrint("Descended stairs")
 	}
+	if g.Params.Plan.Revisits {
+		exit := InvalidPos
+		if style == DescendNormal {
+			exit = g.Player.Pos
+		}
+		g.StoreLevel(exit)
+	}
 	g.Depth++
 	g.DepthPlayerTurn = 0
 	g.Boredom = 0
 	if style != DescendFall {
 		g.PushEvent(&simpleEvent{ERank: g.Ev.Rank(), EAction: PlayerTurn})
 	}
-	g.InitLevel()
+	if lvl := g.Levels[g.Depth]; lvl != nil {
+		arrival := InvalidPos
+		if style == DescendNormal {
+			arrival = lvl.Stairs(func(st stair) bool { return st == UpStair })
+		}
+		g.RestoreLevel(arrival)
+	} else {
+		g.InitLevel()
+	}
 	g.Save()
+	if debrief != "" && !Testing {
+		g.ui.DrawDescription(debrief, fmt.Sprintf("Depth %d Debrief", g.Depth-1))
+	}
 	return false
 }
 
@@ -654,9 +713,7 @@ func (g *game) ApplyRest() {
 	g.Stats.DRests[g.Depth]++
 	g.PrintStyled("You feel fresh again after eating banana and sleeping.", logStatusEnd)
 	g.StoryPrintf("Rested in barrel (bananas: %d)", g.Player.Bananas)
-	if g.Stats.Rest == 10 {
-		AchSleepy.Get(g)
-	}
+	g.CheckAchievements(CounterHook)
 }
 
 func (g *game) AutoPlayer(ev event) bool {
@@ -733,8 +790,11 @@ loop:
 				g.Player.HP = g.Player.HPMax()
 				g.PrintStyled("You died.", logSpecial)
 				g.StoryPrint("You died (wizard mode)")
+			} else if g.Practice && len(g.Checkpoints) > 0 && g.ui.PracticeDeath() {
+				continue loop
 			} else {
 				g.LevelStats()
+				g.Emit(gameEnded{})
 				err := g.RemoveSaveFile()
 				if err != nil {
 					g.PrintfStyled("Error removing save file: %v", logError, err.Error())
@@ -746,10 +806,25 @@ loop:
 		if g.Events.Len() == 0 {
 			break loop
 		}
+		if g.Practice && g.CheckpointDue() {
+			err := g.Checkpoint()
+			if err != nil {
+				g.PrintfStyled("Error making checkpoint: %v", logError, err)
+			}
+		}
 		ev := g.PopIEvent().Event
 		g.Turn = ev.Rank()
 		g.Ev = ev
 		ev.Action(g)
+		if g.rewind > 0 {
+			i := g.rewind - 1
+			g.rewind = 0
+			err := g.Rewind(i)
+			if err != nil {
+				g.PrintfStyled("Error rewinding: %v", logError, err)
+			}
+			continue loop
+		}
 		if g.AutoNext {
 			continue loop
 		}
@@ -1,6 +1,18 @@
 package main
 
-import "testing"
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestInitLevel(t *testing.T) {
 	Testing = true
@@ -54,3 +66,1033 @@ func TestInitLevel(t *testing.T) {
 		}
 	}
 }
+
+// TestConcurrentGames runs independent games in parallel. It is meant to be
+// run with the race detector, to check that games do not share state.
+func TestConcurrentGames(t *testing.T) {
+	Testing = true
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g := &game{}
+			for depth := 0; depth < 3; depth++ {
+				g.InitLevel()
+				g.ComputeLOS()
+				g.BuildAutoexploreMap(g.AutoexploreSources())
+				for _, m := range g.Monsters {
+					pos := m.SearchAround(g, m.Pos, 5)
+					if pos != InvalidPos {
+						m.Path = m.APath(g, m.Pos, pos)
+					}
+				}
+				if g.DumpDungeon() == "" {
+					t.Errorf("empty dungeon dump at depth %d", g.Depth)
+				}
+				g.Depth++
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSeed(t *testing.T) {
+	Testing = true
+	g1 := &game{}
+	g1.Seed(42)
+	g2 := &game{}
+	g2.Seed(42)
+	g1.InitLevel()
+	// another game in between does not change the sequence of g2
+	other := &game{}
+	other.InitLevel()
+	g2.InitLevel()
+	if !reflect.DeepEqual(g1.Dungeon, g2.Dungeon) || g1.Player.Pos != g2.Player.Pos {
+		t.Errorf("different dungeons for the same seed")
+	}
+	if len(g1.Monsters) != len(g2.Monsters) {
+		t.Fatalf("different monsters for the same seed")
+	}
+	for i, mons := range g1.Monsters {
+		if mons.Kind != g2.Monsters[i].Kind || mons.Pos != g2.Monsters[i].Pos {
+			t.Errorf("different monsters for the same seed")
+		}
+	}
+}
+
+func TestBehaviours(t *testing.T) {
+	Testing = true
+	for beh := BehPatrol; beh <= BehCrazyImp; beh++ {
+		if behaviours[beh] == nil {
+			t.Errorf("no implementation for behaviour %d", beh)
+		}
+	}
+	g := &game{}
+	for depth := 0; depth < 3; depth++ {
+		g.InitLevel()
+		for _, m := range g.Monsters {
+			beh := g.Bands[m.Band].Behaviour()
+			for i := 0; i < 10; i++ {
+				pos := beh.NextTarget(g, m)
+				if g.Bands[m.Band].Beh == BehCrazyImp && pos == InvalidPos {
+					continue
+				}
+				if !pos.valid() {
+					t.Errorf("bad target for %s: %+v", m.Kind, pos)
+				}
+			}
+		}
+		g.Depth++
+	}
+}
+
+func TestNoticeTampering(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	for _, m := range g.Monsters {
+		m.ComputeLOS(g)
+		m.State = Wandering
+		pos := m.Pos.E()
+		if !pos.valid() || !m.LOS[pos] {
+			continue
+		}
+		g.Tampered = map[position]tampering{pos: {T: WallCell, Turn: g.Turn}}
+		t0 := g.Dungeon.Cell(pos).T
+		if t0 == WallCell {
+			continue
+		}
+		m.Target = m.Pos
+		m.NoticeTampering(g)
+		if !m.Kind.NoticesTampering() {
+			if m.Target != m.Pos {
+				t.Errorf("%s noticed tampering at %+v", m.Kind, pos)
+			}
+			continue
+		}
+		if m.Target != pos {
+			t.Errorf("%s did not notice tampering at %+v", m.Kind, pos)
+		}
+		m.Target = m.Pos
+		m.NoticeTampering(g)
+		if m.Target != m.Pos || len(g.Tampered) == 0 {
+			t.Errorf("%s noticed tampering at %+v twice", m.Kind, pos)
+		}
+		g.Dungeon.SetCell(pos, DoorCell)
+		g.Tampered = map[position]tampering{}
+		g.Turn++
+		g.Tamper(pos)
+		m.NoticeTampering(g)
+		if m.Target != pos {
+			t.Errorf("%s did not notice opened door at %+v", m.Kind, pos)
+		}
+		g.Turn += DurationDoorSwing + 1
+		m.Target = m.Pos
+		m.Noticed = nil
+		m.NoticeTampering(g)
+		if m.Target != m.Pos || len(g.Tampered) > 0 {
+			t.Errorf("%s noticed closed door at %+v", m.Kind, pos)
+		}
+		g.Turn -= DurationDoorSwing + 2
+		g.Dungeon.SetCell(pos, t0)
+	}
+}
+
+func TestScent(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	pos := g.Player.Pos
+	g.LayScent()
+	if g.ScentAt(pos) != ScentDuration {
+		t.Errorf("bad scent: %d", g.ScentAt(pos))
+	}
+	g.Turn += ScentDuration / 2
+	if g.ScentAt(pos) != ScentDuration/2 {
+		t.Errorf("scent did not decay: %d", g.ScentAt(pos))
+	}
+	g.Clouds[pos] = CloudFog
+	if g.ScentAt(pos) != 0 {
+		t.Errorf("scent not dispersed: %d", g.ScentAt(pos))
+	}
+	delete(g.Clouds, pos)
+	g.LayScent()
+	m := &monster{Kind: MonsDog}
+	if nb := g.Dungeon.FreeNeighbors(pos); len(nb) > 0 {
+		m.Pos = nb[0]
+		if spos, ok := m.SmellScent(g); !ok || spos != pos {
+			t.Errorf("dog at %+v did not smell scent at %+v", m.Pos, pos)
+		}
+	}
+}
+
+func TestAlarm(t *testing.T) {
+	Testing = true
+	g := &game{}
+	raised := false
+	for depth := 0; depth < MaxDepth && !raised; depth++ {
+		g.InitLevel()
+		g.Depth++
+		for _, m := range g.Monsters {
+			if !m.Kind.RaisesAlarms() {
+				continue
+			}
+			state := m.State
+			m.State = Hunting
+			if !m.GoRaiseAlarm(g) {
+				m.State = state
+				continue
+			}
+			if !m.RaisingAlarm || g.Dungeon.Cell(m.Target).T != AlarmCell {
+				t.Errorf("%s not going to an alarm: %+v", m.Kind, m.Target)
+			}
+			g.Ev = &simpleEvent{EAction: PlayerTurn}
+			g.RaiseAlarm(m)
+			raised = true
+			if !g.Player.HasStatus(StatusAlarm) {
+				t.Errorf("no alarm status")
+			}
+			for _, mons := range g.Monsters {
+				if mons.Band != m.Band && !mons.Kind.Peaceful() && mons.State != Watching {
+					t.Errorf("%s not watching after alarm", mons.Kind)
+				}
+			}
+			break
+		}
+	}
+	if !raised {
+		t.Errorf("no guard could raise an alarm")
+	}
+}
+
+func TestSoundPropagation(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	sp := &soundPath{game: g}
+	for i, c := range g.Dungeon.Cells {
+		pos := idxtopos(i)
+		cost := sp.Cost(pos, pos)
+		switch c.T {
+		case WallCell, DoorCell:
+			if cost <= 1 {
+				t.Errorf("%s does not muffle noise", c.T)
+			}
+		case GroundCell:
+			if cost != 1 {
+				t.Errorf("ground muffles noise: %d", cost)
+			}
+		}
+	}
+	g.MakeNoise(WallNoise, g.Player.Pos)
+	if g.NoiseLevel != WallNoise {
+		t.Errorf("bad noise level: %d", g.NoiseLevel)
+	}
+}
+
+func TestLandmarks(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	nb := g.Dungeon.FreeNeighbors(g.Player.Pos)
+	if len(nb) == 0 {
+		t.Fatalf("no free neighbor at %+v", g.Player.Pos)
+	}
+	pos := nb[0]
+	g.Dungeon.SetCell(pos, BarrelCell)
+	delete(g.TerrainKnowledge, pos)
+	has := func() bool {
+		lms := g.Landmarks()
+		found := false
+		for i, lm := range lms {
+			if i > 0 && lms[i-1].Dist > lm.Dist {
+				t.Errorf("unsorted landmarks: %+v", lms)
+			}
+			if lm.Pos == pos && lm.Kind == LandmarkBarrel {
+				found = true
+			}
+		}
+		return found
+	}
+	g.Dungeon.Cells[pos.idx()].Explored = false
+	if has() {
+		t.Errorf("unexplored barrel at %+v is a landmark", pos)
+	}
+	g.Dungeon.SetExplored(pos)
+	if !has() {
+		t.Errorf("barrel at %+v is not a landmark", pos)
+	}
+	if lpos, ok := g.NearestLandmark(LandmarkBarrel); !ok || lpos.Distance(g.Player.Pos) != 1 {
+		t.Errorf("bad nearest barrel: %+v", lpos)
+	}
+	g.TerrainKnowledge[pos] = GroundCell
+	if has() {
+		t.Errorf("barrel at %+v remembered as ground is a landmark", pos)
+	}
+}
+
+func TestStealthCosts(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	g.MonsterLOS = map[position]bool{}
+	g.LastMonsterKnownAt = map[position]*monster{}
+	for i, c := range g.Dungeon.Cells {
+		if c.Explored || c.T != GroundCell {
+			continue
+		}
+		pos := idxtopos(i)
+		g.Illuminated[i] = true
+		if cost := g.StealthCosts()[i]; cost != stealthCostNormal {
+			t.Errorf("unexplored lighted cell %+v has cost %d", pos, cost)
+		}
+		g.Dungeon.SetCell(pos, FoliageCell)
+		if cost := g.StealthCosts()[i]; cost != stealthCostNormal {
+			t.Errorf("unexplored foliage %+v has cost %d", pos, cost)
+		}
+		g.Dungeon.SetCell(pos, GroundCell)
+		g.Dungeon.SetExplored(pos)
+		if cost := g.StealthCosts()[i]; cost != stealthCostNormal+stealthCostLighted {
+			t.Errorf("explored lighted cell %+v has cost %d", pos, cost)
+		}
+		return
+	}
+	t.Errorf("no unexplored ground cell")
+}
+
+func TestAchievementRules(t *testing.T) {
+	g := &game{}
+	g.Turn = 1
+	g.Player = &player{HP: 1}
+	g.Stats.Achievements = map[achievement]int{}
+	g.Stats.Lore = map[int]bool{}
+	g.Stats.GrowDepths(MaxDepth)
+	g.Stats.FireUse = 4
+	g.Stats.Jumps = 10
+	g.CheckAchievements(CounterHook)
+	for _, ach := range []achievement{AchPyromancerNovice, AchPyromancerInitiate} {
+		if g.Stats.Achievements[ach] == 0 {
+			t.Errorf("missing achievement %s", ach)
+		}
+	}
+	if g.Stats.Achievements[AchPyromancerMaster] != 0 || g.Stats.Achievements[AchAcrobat] != 0 {
+		t.Errorf("unexpected achievements: %v", g.Stats.Achievements)
+	}
+	g.Depth = 5
+	for depth := 2; depth <= 5; depth++ {
+		g.Stats.DExplPerc[depth] = 95
+	}
+	g.Stats.DRests[3] = 1
+	g.CheckAchievements(LevelEndHook)
+	for _, ach := range []achievement{AchNoviceExplorer, AchInitiateExplorer, AchInsomniaNovice, AchAntimagicInitiate, AchStealthInitiate} {
+		if g.Stats.Achievements[ach] == 0 {
+			t.Errorf("missing achievement %s", ach)
+		}
+	}
+	if g.Stats.Achievements[AchInsomniaInitiate] != 0 {
+		t.Errorf("insomnia initiate despite rest at depth 3")
+	}
+	g.Stats.Achievements = map[achievement]int{}
+	g.Stats.DSpotted[4] = 3
+	g.CheckAchievements(LevelEndHook)
+	if g.Stats.Achievements[AchStealthInitiate] != 0 || g.Stats.Achievements[AchStealthNovice] == 0 {
+		t.Errorf("bad stealth achievements with spotted player at depth 4: %v", g.Stats.Achievements)
+	}
+	g.Stats.DSpotted[4] = 0
+	g.Stats.Achievements = map[achievement]int{}
+	g.Player.HP = 0
+	g.CheckAchievements(GameEndHook)
+	if g.Stats.Achievements[NoAchievement] != 0 {
+		t.Errorf("pitiful death despite explorer achievement")
+	}
+	g.Stats.Achievements = map[achievement]int{}
+	g.Stats.DExplPerc[5] = 50
+	g.CheckAchievements(GameEndHook)
+	if len(g.Stats.Achievements) != 1 || g.Stats.Achievements[NoAchievement] == 0 {
+		t.Errorf("expected only pitiful death: %v", g.Stats.Achievements)
+	}
+}
+
+type recorder struct {
+	events []gameEvent
+}
+
+func (r *recorder) Observe(g *game, ev gameEvent) {
+	r.events = append(r.events, ev)
+}
+
+func TestLevelDebrief(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	for _, mons := range g.Monsters {
+		mons.Alerted = false
+	}
+	g.Monsters[0].Alerted = true
+	g.Monsters[1].Dead = true
+	g.Stats.DSpotted[g.Depth] = 3
+	g.Stats.DUSpotted[g.Depth] = 1
+	want := fmt.Sprintf("spotted 3 times by 1 monsters, while %d monsters never noticed you", len(g.Monsters)-2)
+	if d := g.LevelDebrief(); !strings.Contains(d, want) {
+		t.Errorf("bad debrief: %q", d)
+	}
+}
+
+func TestFilteredLog(t *testing.T) {
+	g := &game{Depth: 2, Turn: 30}
+	g.Print("You hear a door.")
+	g.PrintStyled("The dog bites you.", logMonsterHit)
+	g.PrintStyled("You are no longer slow.", logStatusEnd)
+	if n := len(g.FilteredLog(LogFilterNone, "")); n != 3 {
+		t.Errorf("bad number of entries: %d", n)
+	}
+	if l := g.FilteredLog(LogFilterHits, ""); len(l) != 1 || l[0].Text != "The dog bites you." {
+		t.Errorf("bad hits: %+v", l)
+	}
+	if l := g.FilteredLog(LogFilterNone, "HEAR"); len(l) != 1 || l[0].Text != "You hear a door." {
+		t.Errorf("bad case insensitive search: %+v", l)
+	}
+	if l := g.FilteredLog(LogFilterStatusEnds, "door"); len(l) != 0 {
+		t.Errorf("bad filtered search: %+v", l)
+	}
+	if s := g.Log[0].Stamped(); s != "Depth  2|Turn    30| You hear a door." {
+		t.Errorf("bad stamp: %q", s)
+	}
+}
+
+func TestNotes(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	g.Player.Pos = position{10, 10}
+	far, near := position{20, 10}, position{12, 10}
+	g.SetNote(far, "far")
+	g.SetNote(near, "near")
+	if notes := g.SortedNotes(g.Player.Pos); !reflect.DeepEqual(notes, []position{near, far}) {
+		t.Errorf("bad note order: %v", notes)
+	}
+	if d := g.DumpNotes(); !strings.Contains(d, "(12,10): near\n- (20,10): far") {
+		t.Errorf("bad notes dump: %q", d)
+	}
+	g.SetNote(near, "")
+	if _, ok := g.Notes[near]; ok || len(g.Notes) != 1 {
+		t.Errorf("note not removed: %v", g.Notes)
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	Testing = true
+	g := &game{}
+	r := &recorder{}
+	g.Subscribe(r)
+	g.InitLevel()
+	if len(r.events) == 0 || r.events[len(r.events)-1] != (levelEntered{Depth: 1}) {
+		t.Fatalf("level entered not emitted: %v", r.events)
+	}
+	g.Ev = &simpleEvent{EAction: PlayerTurn}
+	g.PutStatus(StatusSwift, 5)
+	if g.Stats.Statuses[StatusSwift] != 1 {
+		t.Errorf("status statistics not updated")
+	}
+	if r.events[len(r.events)-1] != (statusGained{Status: StatusSwift, Duration: 5}) {
+		t.Errorf("status gained not emitted: %v", r.events[len(r.events)-1])
+	}
+	m := g.Monsters[0]
+	m.Alerted = false
+	m.State = Wandering
+	m.MakeHunt(g)
+	if ev, ok := r.events[len(r.events)-1].(playerSpotted); !ok || ev.Mons != m || !ev.Unaware {
+		t.Errorf("player spotted not emitted: %v", r.events[len(r.events)-1])
+	}
+	if g.Stats.NUSpotted != 1 || g.Stats.DUSpotted[g.Depth] != 1 {
+		t.Errorf("spotted statistics not updated")
+	}
+}
+
+func TestTelemetry(t *testing.T) {
+	Testing = true
+	g := &game{}
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	g.dataDir = dir
+	err = g.StartTelemetry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.InitLevel()
+	g.MakeNoise(5, g.Player.Pos)
+	g.Emit(gameEnded{})
+	err = g.StopTelemetry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a loaded game continues its file, a new one has its own
+	err = g.StartTelemetry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Emit(levelEntered{})
+	err = g.StopTelemetry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "telemetry", "*.jsonl"))
+	if err != nil || len(files) != 1 || !strings.Contains(files[0], g.ID) {
+		t.Fatalf("expected a telemetry file: %v %v", files, err)
+	}
+	ng := &game{dataDir: dir}
+	err = ng.StartTelemetry()
+	if err == nil {
+		err = ng.StopTelemetry()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nfiles, _ := filepath.Glob(filepath.Join(dir, "telemetry", "*.jsonl")); len(nfiles) != 2 {
+		t.Errorf("expected a telemetry file per game: %v", nfiles)
+	}
+	data, err := ioutil.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	events := map[string]telemetryLine{}
+	for _, l := range lines {
+		var tl telemetryLine
+		err := json.Unmarshal([]byte(l), &tl)
+		if err != nil {
+			t.Fatalf("bad line %q: %v", l, err)
+		}
+		events[tl.Event] = tl
+	}
+	if tl, ok := events["noise-made"]; !ok || tl.Noise != 5 || tl.At == nil || *tl.At != g.Player.Pos {
+		t.Errorf("bad noise line: %+v", tl)
+	}
+	if tl, ok := events["level-entered"]; !ok || tl.Depth != 1 {
+		t.Errorf("bad level line: %+v", tl)
+	}
+	if tl := events["game-ended"]; tl.Result != "death" {
+		t.Errorf("bad game end line: %+v", tl)
+	}
+	if !strings.Contains(lines[len(lines)-1], "level-entered") {
+		t.Errorf("loaded game did not continue its telemetry file")
+	}
+}
+
+func TestDaily(t *testing.T) {
+	Testing = true
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	date := "2020-02-02"
+	g := &game{dataDir: dir}
+	err = g.StartDaily(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&game{dataDir: dir}).StartDaily(date); err == nil {
+		t.Errorf("second attempt of the same day allowed")
+	}
+	g2 := &game{dataDir: filepath.Join(dir, "other")}
+	err = g2.StartDaily(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.InitLevel()
+	// another game in the same process does not change the daily game
+	other := &game{}
+	other.InitLevel()
+	g2.InitLevel()
+	if !reflect.DeepEqual(g.Params, g2.Params) {
+		t.Errorf("different parameters for the same day")
+	}
+	if !reflect.DeepEqual(g.Dungeon, g2.Dungeon) || g.Player.Pos != g2.Player.Pos {
+		t.Errorf("different dungeons for the same day")
+	}
+	if len(g.Monsters) != len(g2.Monsters) {
+		t.Fatalf("different monsters for the same day")
+	}
+	for i, mons := range g.Monsters {
+		if mons.Kind != g2.Monsters[i].Kind || mons.Pos != g2.Monsters[i].Pos {
+			t.Errorf("different monsters for the same day")
+		}
+	}
+	g.Player.HP = 0
+	g.Stats.KilledBy = "guard"
+	g.Emit(gameEnded{})
+	recs, err := g.LoadDailyRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := recs.Find(date)
+	if i < 0 || !recs[i].Done || recs[i].KilledBy != "guard" || recs[i].Depth != 1 {
+		t.Errorf("bad daily record: %+v", recs)
+	}
+	if !strings.Contains(g.Dump(), "Daily challenge of "+date) {
+		t.Errorf("dump not marked as daily")
+	}
+	if !strings.Contains(g.RunSummary(false), "daily "+date) {
+		t.Errorf("run history not marked as daily")
+	}
+	report := DailyReport(recs, "2020-02-03")
+	if !strings.Contains(report, "not attempted") || !strings.Contains(report, date+": killed by guard") {
+		t.Errorf("bad report: %s", report)
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.SetDifficulty(DifficultyExplorer, runPlan{})
+	g.InitLevel()
+	plan := DifficultyExplorer.Plan()
+	if len(g.Params.HealthPotion) != plan.HealthPotions || len(g.Params.MappingStone) != plan.MappingStones {
+		t.Errorf("plan not applied: %+v", g.Params)
+	}
+	bananas := 0
+	for _, n := range g.Params.ExtraBanana {
+		bananas += n
+	}
+	if bananas != plan.ExtraBananas {
+		t.Errorf("bad extra bananas: %d", bananas)
+	}
+	if !strings.Contains(g.Dump(), "Difficulty: Explorer") {
+		t.Errorf("difficulty not in dump")
+	}
+	g = &game{}
+	g.SetDifficulty(DifficultyCustom, runPlan{LoreLevels: 2, MonsterDensity: 150})
+	g.InitLevel()
+	if len(g.Params.Lore) != 2 || len(g.Params.HealthPotion) != 0 || len(g.Params.Blocked) != 0 {
+		t.Errorf("custom plan not applied: %+v", g.Params)
+	}
+	if n := g.Params.Plan.ScaleBands(g, 2); n != 3 {
+		t.Errorf("bad scaled bands: %d", n)
+	}
+	if g.Params.Difficulty != DifficultyCustom {
+		t.Errorf("difficulty not recorded")
+	}
+}
+
+func TestConducts(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.InitLevel()
+	g.Conducts = map[conduct]bool{ConductNoRest: true}
+	for _, c := range conducts {
+		if !c.Kept(&g.Stats) {
+			t.Errorf("conduct %s broken at start", c)
+		}
+	}
+	g.Stats.Rest++
+	if ConductNoRest.Kept(&g.Stats) {
+		t.Errorf("no rest conduct still kept")
+	}
+	// no prompt once broken
+	if err := g.ConfirmConduct(ConductNoRest); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var pos position
+	for p := range g.Objects.Potions {
+		pos = p
+		break
+	}
+	if len(g.Objects.Potions) > 0 {
+		g.Objects.Potions[pos] = HealthPotion
+		g.Player.HP = 1
+		if !g.PotionDrinkable(pos) {
+			t.Errorf("potion not drinkable while wounded")
+		}
+		g.DrinkPotion(pos)
+		if ConductNoPotions.Kept(&g.Stats) {
+			t.Errorf("no potions conduct still kept")
+		}
+	}
+	dump := g.Dump()
+	if !strings.Contains(dump, "- No rest: broken (declared)") || !strings.Contains(dump, "- No jumps: kept\n") {
+		t.Errorf("bad conducts in dump:\n%s", g.ConductsReport())
+	}
+}
+
+func TestEndless(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.SetDifficulty(DifficultyNormal, runPlan{Endless: true})
+	g.InitLevel()
+	if !g.Params.Plan.Endless {
+		t.Fatalf("endless setting lost")
+	}
+	g.Depth = MaxDepth
+	g.InitLevel()
+	if len(g.Objects.Stairs) == 0 {
+		t.Errorf("no stairs on the last level")
+	}
+	for _, depth := range []int{MaxDepth + 1, MaxDepth + 5} {
+		g.Depth = depth
+		g.InitLevel()
+		if len(g.Params.Special) <= depth {
+			t.Errorf("no special room planned for depth %d", depth)
+		}
+		danger := 0
+		for _, mons := range g.Monsters {
+			danger += mons.Kind.Dangerousness()
+		}
+		if danger < EndlessDanger(depth)/2 {
+			t.Errorf("depth %d: danger %d below budget %d", depth, danger, EndlessDanger(depth))
+		}
+	}
+	if EndlessDanger(MaxDepth+5) <= EndlessDanger(MaxDepth+1) {
+		t.Errorf("danger does not escalate")
+	}
+	// the stealth bonus of a level cannot be lost on other levels
+	g.Stats.DUSpotted[MaxDepth+1] = 10
+	g.Stats.DUSpotted[MaxDepth+2] = 3
+	if score := g.EndlessScore(); score != 5*EndlessDepthScore+4*EndlessStealthScore-3*EndlessSpottedMalus {
+		t.Errorf("bad score: %d", score)
+	}
+	if s := g.RunSummary(false); !strings.Contains(s, "endless") || !strings.Contains(s, "score") {
+		t.Errorf("bad run summary: %s", s)
+	}
+}
+
+func TestRevisit(t *testing.T) {
+	Testing = true
+	g := &game{}
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	g.dataDir = dir
+	g.SetDifficulty(DifficultyNormal, runPlan{Revisits: true})
+	g.InitLevel()
+	g.Ev = &simpleEvent{EAction: PlayerTurn}
+	var exit position
+	for pos, st := range g.Objects.Stairs {
+		if st == NormalStair {
+			exit = pos
+		}
+	}
+	g.Player.Pos = exit
+	dungeon, monsters := g.Dungeon, g.Monsters
+	g.Descend(DescendNormal)
+	if g.Depth != 2 || g.Levels[1] == nil {
+		t.Fatalf("first level not kept")
+	}
+	if g.Objects.Stairs[g.Player.Pos] != UpStair {
+		t.Errorf("no up stairs at arrival")
+	}
+	g.Turn += RevisitPatrolTurns + 1
+	if err := g.Ascend(); err != nil {
+		t.Fatalf("ascend: %v", err)
+	}
+	if g.Depth != 1 || g.Dungeon != dungeon || len(g.Monsters) != len(monsters) || g.Monsters[0] != monsters[0] {
+		t.Errorf("first level not restored")
+	}
+	if g.Player.Pos != exit {
+		t.Errorf("bad arrival: %v instead of %v", g.Player.Pos, exit)
+	}
+	for _, mons := range g.Monsters {
+		if mons.Exists() && g.MonsterAt(mons.Pos) != mons {
+			t.Errorf("bad monster position cache")
+		}
+	}
+	if g.Levels[2] == nil || g.ExploredLevels != 2 {
+		t.Errorf("second level not kept")
+	}
+	g.Descend(DescendNormal)
+	if g.Depth != 2 || g.Objects.Stairs[g.Player.Pos] != UpStair {
+		t.Errorf("second level not restored at up stairs")
+	}
+	for _, mons := range g.Monsters {
+		mons.State = Hunting
+	}
+	g.SimulateElapsed(RevisitForgetTurns + 1)
+	for _, mons := range g.Monsters {
+		if mons.Exists() && mons.State == Hunting {
+			t.Errorf("monster still hunting after a long time")
+		}
+	}
+	// crowd the arrival place
+	lvl := g.Levels[1]
+	places := append([]position{lvl.Exit}, lvl.Dungeon.FreeNeighbors(lvl.Exit)...)
+	for _, mons := range lvl.Monsters {
+		if len(places) == 0 {
+			break
+		}
+		if mons.Exists() && lvl.Dungeon.Cell(places[0]).IsPassable() {
+			mons.Pos = places[0]
+		}
+		places = places[1:]
+	}
+	if err := g.Ascend(); err != nil {
+		t.Fatalf("ascend: %v", err)
+	}
+	if g.MonsterAt(g.Player.Pos).Exists() {
+		t.Errorf("monster left at arrival")
+	}
+	seen := map[position]bool{}
+	for _, mons := range g.Monsters {
+		if !mons.Exists() {
+			continue
+		}
+		if seen[mons.Pos] || g.MonsterAt(mons.Pos) != mons {
+			t.Errorf("monsters sharing a cell at %v", mons.Pos)
+		}
+		seen[mons.Pos] = true
+	}
+}
+
+func TestDraft(t *testing.T) {
+	Testing = true
+	g := &game{}
+	kits := g.DraftKits()
+	if len(kits) != DraftChoices {
+		t.Fatalf("bad number of kits: %d", len(kits))
+	}
+	mags := map[magaraKind]bool{}
+	cloaks := map[item]bool{}
+	for _, k := range kits {
+		for _, mk := range k.Magaras {
+			if mags[mk] {
+				t.Errorf("magara offered twice: %v", magara{Kind: mk})
+			}
+			mags[mk] = true
+		}
+		if cloaks[k.Cloak] || !k.Cloak.IsCloak() {
+			t.Errorf("bad cloak: %s", k.Cloak.ShortDesc(g))
+		}
+		cloaks[k.Cloak] = true
+	}
+	if len(g.GeneratedMagaras) > 0 || len(g.GeneratedCloaks) > 0 {
+		t.Errorf("draft changed generated items")
+	}
+	g.Draft = &kits[1]
+	g.InitLevel()
+	if g.Player.Magaras[0].Kind != kits[1].Magaras[0] || g.Player.Magaras[1].Kind != kits[1].Magaras[1] || g.Player.Magaras[1].Charges == 0 {
+		t.Errorf("starting magaras not drafted")
+	}
+	if g.Player.Inventory.Body != kits[1].Cloak {
+		t.Errorf("starting cloak not drafted")
+	}
+	if !strings.Contains(g.Dump(), "Drafted starting kit: "+kits[1].String()) {
+		t.Errorf("draft not in dump")
+	}
+	if !strings.Contains(g.RunSummary(false), "draft") {
+		t.Errorf("draft not in run summary")
+	}
+}
+
+func TestPractice(t *testing.T) {
+	Testing = true
+	g := &game{}
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	g.dataDir = dir
+	g.InitLevel()
+	g.EnterPracticeMode()
+	if !g.CheckpointDue() {
+		t.Fatalf("no checkpoint due at level start")
+	}
+	if err := g.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	if g.CheckpointDue() {
+		t.Errorf("checkpoint due right after a checkpoint")
+	}
+	pos := g.Player.Pos
+	g.Player.HP = 0
+	g.Player.Pos = InvalidPos
+	if err := g.Rewind(0); err != nil {
+		t.Fatal(err)
+	}
+	if g.Player.HP <= 0 || g.Player.Pos != pos || g.Rewinds != 1 || len(g.Checkpoints) != 1 || g.dataDir != dir {
+		t.Errorf("bad rewind")
+	}
+	if g.CheckpointDue() {
+		t.Errorf("checkpoint due after a rewind")
+	}
+	for i := 0; i < MaxCheckpoints+2; i++ {
+		g.Checkpoint()
+	}
+	if len(g.Checkpoints) != MaxCheckpoints {
+		t.Errorf("bad number of checkpoints: %d", len(g.Checkpoints))
+	}
+	lg, err := g.DecodeGameSave(g.Checkpoints[len(g.Checkpoints)-1].Data)
+	if err != nil || len(lg.Checkpoints) > 0 {
+		t.Errorf("checkpoints saved in checkpoints")
+	}
+	if s := g.RunSummary(true); !strings.Contains(s, "practice") {
+		t.Errorf("bad run summary: %s", s)
+	}
+}
+
+func TestEndlessDescend(t *testing.T) {
+	Testing = true
+	g := &game{}
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	g.dataDir = dir
+	g.config.LevelDebrief = true
+	g.SetDifficulty(DifficultyNormal, runPlan{Endless: true})
+	g.InitLevel()
+	g.Ev = &simpleEvent{EAction: PlayerTurn}
+	for g.Depth < MaxDepth+2 {
+		depth := g.Depth
+		for pos, st := range g.Objects.Stairs {
+			if st == NormalStair {
+				g.Player.Pos = pos
+				break
+			}
+		}
+		g.Stats.DRests[depth] = depth
+		if g.Descend(DescendNormal) {
+			t.Fatalf("escaped at depth %d", depth)
+		}
+		if g.Depth != depth+1 {
+			t.Fatalf("could not descend from depth %d", depth)
+		}
+	}
+	for depth := MaxDepth; depth < g.Depth; depth++ {
+		if g.Stats.DRests[depth] != depth {
+			t.Errorf("statistics of depth %d overwritten: %d", depth, g.Stats.DRests[depth])
+		}
+	}
+	g.Depth--
+	if d := g.LevelDebrief(); !strings.Contains(d, fmt.Sprintf("rested %d times", g.Depth)) {
+		t.Errorf("bad debrief for depth %d: %s", g.Depth, d)
+	}
+}
+
+func TestAgent(t *testing.T) {
+	Testing = true
+	g := &game{}
+	g.Seed(1)
+	in := strings.NewReader(`{"Action": "Dance"}
+{"Action": "Travel"}
+{"Action": "WaitTurn"}
+`)
+	out := &strings.Builder{}
+	ui := NewAgentUI(g, in, out)
+	err := ui.PlayAgent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(strings.NewReader(out.String()))
+	obs := []agentObservation{}
+	for dec.More() {
+		var o agentObservation
+		err := dec.Decode(&o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		obs = append(obs, o)
+	}
+	if len(obs) != 4 {
+		t.Fatalf("bad number of observations: %d", len(obs))
+	}
+	if o := obs[0]; o.Depth != 1 || o.Turn != 0 || o.HP != o.HPMax || len(o.Cells) == 0 || o.Error != "" || o.Done {
+		t.Errorf("bad first observation: %+v", o)
+	}
+	if o := obs[1]; o.Error != `Unknown action "Dance".` || o.Turn != 0 {
+		t.Errorf("bad observation after unknown action: %+v", o)
+	}
+	if o := obs[2]; o.Error != "Travel needs a valid target." || o.Turn != 0 {
+		t.Errorf("bad observation after travel without target: %+v", o)
+	}
+	if o := obs[3]; o.Error != "" || o.Turn == 0 || o.Pos != obs[0].Pos {
+		t.Errorf("bad observation after waiting: %+v", o)
+	}
+}
+
+func TestBotReproducible(t *testing.T) {
+	Testing = true
+	r, err := PlayBot(7, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, err := PlayBot(7, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != r2 {
+		t.Errorf("same seed, different games: %v, %v", r, r2)
+	}
+}
+
+func TestSpectators(t *testing.T) {
+	dir, err := ioutil.TempDir("", "harmonist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "game.sock")
+	ui := &gameui{}
+	err = ui.Publish(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp := ui.spectators
+	if err := (&gameui{}).Publish(addr); err == nil {
+		t.Errorf("live game socket taken over")
+	}
+	// a spectator that does not read is dropped and disconnected
+	conn, err := net.Dial("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	nwatchers := func() int {
+		sp.mu.Lock()
+		defer sp.mu.Unlock()
+		return len(sp.watchers)
+	}
+	for i := 0; nwatchers() == 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	df := drawFrame{}
+	for i := 0; i < 1000; i++ {
+		df.Draws = append(df.Draws, cellDraw{X: i % DungeonWidth, Y: i / DungeonWidth})
+	}
+	for i := 0; i < 2*SpectatorQueue && nwatchers() > 0; i++ {
+		sp.Send(df)
+	}
+	if nwatchers() > 0 {
+		t.Fatalf("slow spectator not dropped")
+	}
+	// writing fails once the game closed its end, without reading first, as
+	// reading would unblock the game side
+	for i := 0; i < 100 && err == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+		_, err = conn.Write([]byte{0})
+	}
+	if err == nil {
+		t.Errorf("slow spectator not disconnected")
+	}
+	sp.Close()
+	// the socket of a game that ended without removing it is replaced
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	ln.Close()
+	ui = &gameui{}
+	err = ui.Publish(addr)
+	if err != nil {
+		t.Errorf("stale socket not replaced: %v", err)
+	} else {
+		ui.spectators.Close()
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// RunSummary returns the line describing the game in the run history, at
+// the end of the game.
+func (g *game) RunSummary(won bool) string {
+	mode := g.Params.Difficulty.String()
+	if g.Daily != "" {
+		mode += ", daily " + g.Daily
+	}
+	if g.Params.Plan.Endless {
+		mode += ", endless"
+	}
+	if g.Draft != nil {
+		mode += ", draft"
+	}
+	if g.Wizard {
+		mode += ", wizard"
+	}
+	if g.Practice {
+		mode += ", practice"
+	}
+	var result string
+	if won {
+		result = fmt.Sprintf("escaped in %d turns", g.Turn)
+	} else {
+		result = fmt.Sprintf("killed by %s at depth %d after %d turns", g.Stats.KilledBy, g.Depth, g.Turn)
+	}
+	if g.Params.Plan.Endless {
+		result += fmt.Sprintf(", score %d", g.EndlessScore())
+	}
+	return fmt.Sprintf("%s [%s] %s", time.Now().Format("2006-01-02 15:04"), mode, result)
+}
+
+// historyObserver appends finished games to the run history. Games in endless
+// mode have their own history, as their score is not comparable.
+type historyObserver struct{}
+
+func (historyObserver) Observe(g *game, ev gameEvent) {
+	if ev, ok := ev.(gameEnded); ok && !Testing {
+		file := "history"
+		if g.Params.Plan.Endless {
+			file = "endless-history"
+		}
+		err := g.AppendHistory(file, g.RunSummary(ev.Won))
+		if err != nil {
+			g.PrintfStyled("Error writing run history: %v", logError, err)
+		}
+	}
+}
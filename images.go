@@ -235,6 +235,10 @@ L5MRkoo//XMGL4jGMAPFTm3JAAAAAElFTkSuQmCC
 	TileImgs["letter-g"] = []byte(`iVBORw0KGgoAAAANSUhEUgAAABAAAAAYCAIAAAB8wupbAAAAbElEQVQ4je1RQQ7AMAhSs/9/mR2W
 OGcoNVl2GzcrKFSzHx/AWw1gSXU3sxiyE8dqUh1RX0L0KEK3yf5aZIZrTyuJwFhuZTLZAPY/NiJV
 S5qdxnhoobkPJ+5QBz3usL1aF1BL7fFdaCqb+FQ4AfRHPAUIlersAAAAAElFTkSuQmCC
+`)
+	TileImgs["letter-gong"] = []byte(`iVBORw0KGgoAAAANSUhEUgAAABAAAAAYCAAAAADWyyLQAAAAT0lEQVR4nGJiQAOEBVhABMN/EMHA
+CBNk+P8fQYLM+A+RYwQrZILzoSJkWEuUACPUXRDrmGDWwaxngjsA6hwMMxhBUiBdMBpDBQZkooUA
+YACp8hEqXVQvNgAAAABJRU5ErkJggg==
 `)
 	TileImgs["letter-gt"] = []byte(`iVBORw0KGgoAAAANSUhEUgAAABAAAAAYCAIAAAB8wupbAAAAR0lEQVQ4jWNgGMTg////xChjxNTA
 yMiIQzGqBuL1YHEbHudhNwmPVTitpprzmEjQTZ6TSPY0dtXEBisxBpOcNLAYPwoGAAAA570v86cV
@@ -826,6 +830,10 @@ jk3PlmHbpnXjso7rw6b308P/69//HBz/HBz/HBz/HBz/HBz/HBz/HBysfHz/HBz/HBz/HBz/HBwA
 AABXYnq0tLRtbW1fGku6AAAAD3RFWHRTb2Z0d2FyZQBHcmFmeDKgolNqAAAATklEQVQYlZ2QSQ4A
 IAgDmf9/2ogLrXqyJgZHoGLEt4AK+5ZLAQGWLCUjVc4dkCoARuAGCXefafIAy7tAiNG6MCOf9QT+
 +Dfw+W6Af4moAVkPAHV0XLSkAAAAAElFTkSuQmCC
+`)
+	TileImgs["map-gong"] = []byte(`iVBORw0KGgoAAAANSUhEUgAAABAAAAAYCAAAAADWyyLQAAAAYUlEQVR4nJyQgQrAIBBCNfr/X3Z0
+pcQKBtsgxLN3dzW8vsPokGX9vCRAawCCE/LNvgqjyBhTS0xb5iiGzJAThMJoYZQ7E6NCLtHSdu1Q
+c7CQ2xz79Mdy/4ztyXRJ4JPxDABsXxc2OdnJ3QAAAABJRU5ErkJggg==
 `)
 	TileImgs["map-ground"] = []byte(`iVBORw0KGgoAAAANSUhEUgAAABAAAAAYCAMAAADEfo0+AAADAFBMVEUAAAD///8A/wD/+wCC/wAA
 /wAA/30A//8Agv8AAP95AP/PAP//ANf/AIL/AAD/fQDLmkWWPBhhAAD/94LD/4KC/4KC/76C//+C
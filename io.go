@@ -10,10 +10,8 @@ import (
 	"path/filepath"
 )
 
-func Replay(file string) error {
-	ui := &gameui{}
-	g := &game{}
-	ui.g = g
+func Replay(ui *gameui, file string) error {
+	g := ui.g
 	g.ui = ui
 	err := g.LoadReplay(file)
 	if err != nil {
@@ -30,7 +28,18 @@ func Replay(file string) error {
 	return nil
 }
 
-func (g *game) DataDir() (string, error) {
+func Watch(ui *gameui, addr string) error {
+	err := ui.Init()
+	if err != nil {
+		return err
+	}
+	defer ui.Close()
+	ui.DrawBufferInit()
+	return ui.Watch(addr)
+}
+
+// XDGDataHome returns the base directory for user data files.
+func XDGDataHome() string {
 	var xdg string
 	if os.Getenv("GOOS") == "windows" {
 		xdg = os.Getenv("LOCALAPPDATA")
@@ -40,7 +49,16 @@ func (g *game) DataDir() (string, error) {
 	if xdg == "" {
 		xdg = filepath.Join(os.Getenv("HOME"), ".local", "share")
 	}
-	dataDir := filepath.Join(xdg, "harmonist")
+	return xdg
+}
+
+// DataDir returns the directory for the saves, configuration, dumps and
+// replays of the game, creating it if needed.
+func (g *game) DataDir() (string, error) {
+	dataDir := g.dataDir
+	if dataDir == "" {
+		dataDir = filepath.Join(XDGDataHome(), "harmonist")
+	}
 	_, err := os.Stat(dataDir)
 	if err != nil {
 		err = os.MkdirAll(dataDir, 0755)
@@ -97,6 +115,12 @@ func (g *game) Load() (bool, error) {
 	if lg.Version != Version {
 		return true, fmt.Errorf("saved game for previous version %s.", lg.Version)
 	}
+	// keep the unsaved state of the running game
+	lg.ui = g.ui
+	lg.config = g.config
+	lg.pathCache = g.pathCache
+	lg.dataDir = g.dataDir
+	lg.rng = g.rng
 	*g = *lg
 	return true, nil
 }
@@ -108,7 +132,7 @@ func (g *game) SaveConfig() error {
 		return err
 	}
 	saveFile := filepath.Join(dataDir, "config.gob")
-	data, err := GameConfig.ConfigSave()
+	data, err := g.config.ConfigSave()
 	if err != nil {
 		g.Print(err.Error())
 		return err
@@ -140,13 +164,58 @@ func (g *game) LoadConfig() (bool, error) {
 	if err != nil {
 		return true, err
 	}
-	if c.Version != GameConfig.Version {
+	if c.Version != g.config.Version {
 		return true, errors.New("Version mismatch")
 	}
-	GameConfig = *c
+	g.config = *c
 	return true, nil
 }
 
+func (g *game) SaveDailyRecords(recs dailyRecords) error {
+	dataDir, err := g.DataDir()
+	if err != nil {
+		return err
+	}
+	data, err := EncodeDailyRecords(recs)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dataDir, "daily"), data, 0644)
+}
+
+func (g *game) LoadDailyRecords() (dailyRecords, error) {
+	dataDir, err := g.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dataDir, "daily"))
+	if os.IsNotExist(err) {
+		// no daily challenge played yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDailyRecords(data)
+}
+
+// AppendHistory appends a line to a run history file.
+func (g *game) AppendHistory(file, line string) error {
+	dataDir, err := g.DataDir()
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dataDir, file), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(line + "\n")
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
 func (g *game) RemoveDataFile(file string) error {
 	dataDir, err := g.DataDir()
 	if err != nil {
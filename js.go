@@ -8,23 +8,21 @@ import (
 	"fmt"
 	"log"
 	"runtime"
+	"time"
 	"unicode/utf8"
 
 	"syscall/js"
 )
 
 func main() {
-	ui := &gameui{}
+	ui := NewGameUI(&game{})
 	err := ui.Init()
 	if err != nil {
 		log.Fatalf("harmonist: %v\n", err)
 	}
 	defer ui.Close()
-	GameConfig.Tiles = true
-	GameConfig.Version = Version
-	LinkColors()
-	GameConfig.DarkLOS = true
-	ApplyDarkLOS()
+	ui.LinkColors()
+	ui.ApplyDarkLOS()
 	go func() {
 		for {
 			ui.ReqAnimFrame()
@@ -38,6 +36,10 @@ func main() {
 func newGame(ui *gameui) {
 	g := &game{}
 	ui.g = g
+	g.config.Tiles = true
+	g.config.Version = Version
+	g.config.DarkLOS = true
+	g.config.LevelDebrief = true
 	load, err := g.LoadConfig()
 	if load && err != nil {
 		log.Printf("Error loading config: %v\n", err)
@@ -46,19 +48,30 @@ func newGame(ui *gameui) {
 			log.Printf("Error resetting config: %v\n", err)
 		}
 	} else if load {
-		CustomKeys = true
+		ui.CustomKeys = true
 	}
-	ApplyConfig()
+	ui.ApplyConfig()
 	ui.PostConfig()
+	var daily, again bool
 	if runtime.GOARCH != "wasm" {
-		ui.DrawWelcome()
+		daily = ui.DrawWelcome()
 	} else {
-		again := ui.HandleStartMenu()
+		again, daily = ui.HandleStartMenu()
 		if again {
 			return
 		}
 	}
 	load, err = g.Load()
+	var dailyerr error
+	if daily && (!load || err != nil) {
+		dailyerr = g.StartDaily(DailyDate(time.Now()))
+	} else if !load || err != nil {
+		g.SetDifficulty(ui.DifficultyMenu())
+		g.Draft = ui.DraftMenu(g.DraftKits())
+	}
+	if !load || err != nil {
+		g.Conducts = ui.ConductsMenu()
+	}
 	if !load {
 		g.InitLevel()
 	} else if err != nil {
@@ -66,43 +79,56 @@ func newGame(ui *gameui) {
 		g.Printf("Error loading saved game… starting new game. (%v)", err)
 	} else {
 		ui.DrawBufferInit()
+		if daily {
+			g.PrintStyled("Finish your saved game before starting the daily challenge.", logError)
+		}
+	}
+	if dailyerr != nil {
+		g.PrintfStyled("Error starting daily challenge: %v", logError, dailyerr)
+	} else if g.Daily != "" && g.Turn == 0 {
+		g.PrintfStyled("Daily challenge of %s: good luck!", logSpecial, g.Daily)
 	}
 	g.ui = ui
 	g.EventLoop()
 	ui.Clear()
-	ui.DrawColoredText("Do you want to collect some more bananas today?\n\n───Click or press any key to play again───", 7, 5, ColorFg)
+	ui.DrawColoredText("Do you want to collect some more bananas today?\n\n───Click or press any key to play again───", 7, 5, ui.ColorFg)
 	ui.DrawText(SaveError, 0, 10)
 	ui.Flush()
 	ui.PressAnyKey()
 }
 
-func (ui *gameui) HandleStartMenu() (again bool) {
+func (ui *gameui) HandleStartMenu() (again, daily bool) {
 	l := ui.DrawWelcomeCommon()
 	g := ui.g
 	for {
 		a := ui.StartMenu(l)
 		switch a {
+		case StartDaily:
+			if ui.DailyMenu() {
+				return false, true
+			}
+			return true, false
 		case StartWatchReplay:
 			err := g.LoadReplay()
 			if err != nil {
-				ui.ColorLine(l+1, ColorRed)
+				ui.ColorLine(l+1, ui.ColorRed)
 				ui.Flush()
 				Sleep(AnimDurShort)
 				log.Printf("Load replay: %v", err)
-				return true
+				return true, false
 			}
-			small := GameConfig.Small
-			GameConfig.Small = true
+			small := g.config.Small
+			g.config.Small = true
 			ui.ApplyToggleLayoutWithClear(false)
 			ui.RestartDrawBuffers()
 			ui.Replay()
 			if small {
-				GameConfig.Small = false
+				g.config.Small = false
 				ui.ApplyToggleLayoutWithClear(false)
 			}
-			return true
+			return true, false
 		default:
-			return false
+			return false, false
 		}
 	}
 }
@@ -110,6 +136,7 @@ func (ui *gameui) HandleStartMenu() (again bool) {
 var SaveError string
 
 type gameui struct {
+	uiContext
 	g         *game
 	cursor    position
 	display   js.Value
@@ -134,8 +161,8 @@ func (ui *gameui) InitElements() error {
 	ui.ctx.Set("imageSmoothingEnabled", false)
 	ui.width = 16
 	ui.height = 24
-	canvas.Set("height", 24*UIHeight)
-	canvas.Set("width", 16*UIWidth)
+	canvas.Set("height", 24*ui.UIHeight)
+	canvas.Set("width", 16*ui.UIWidth)
 	ui.cache = make(map[UICell]js.Value)
 	return nil
 }
@@ -150,7 +177,7 @@ func (ui *gameui) Draw(cell UICell, x, y int) {
 		canvas.Set("height", 24)
 		ctx := canvas.Call("getContext", "2d")
 		ctx.Set("imageSmoothingEnabled", false)
-		buf := getImage(cell).Pix
+		buf := ui.getImage(cell).Pix
 		ua := js.Global().Get("Uint8Array").New(js.ValueOf(len(buf)))
 		js.CopyBytesToJS(ua, buf)
 		ca := js.Global().Get("Uint8ClampedArray").New(ua)
@@ -201,7 +228,7 @@ func (g *game) SaveConfig() error {
 	if runtime.GOARCH != "wasm" {
 		return nil
 	}
-	conf, err := GameConfig.ConfigSave()
+	conf, err := g.config.ConfigSave()
 	if err != nil {
 		SaveError = err.Error()
 		return err
@@ -236,6 +263,48 @@ func (g *game) SaveReplay() error {
 	return nil
 }
 
+func (g *game) SaveDailyRecords(recs dailyRecords) error {
+	storage := js.Global().Get("localStorage")
+	if storage.Type() != js.TypeObject {
+		return errors.New("localStorage not found")
+	}
+	data, err := EncodeDailyRecords(recs)
+	if err != nil {
+		return err
+	}
+	storage.Call("setItem", "harmonistdaily", base64.StdEncoding.EncodeToString(data))
+	return nil
+}
+
+func (g *game) LoadDailyRecords() (dailyRecords, error) {
+	storage := js.Global().Get("localStorage")
+	if storage.Type() != js.TypeObject {
+		return nil, errors.New("localStorage not found")
+	}
+	s := storage.Call("getItem", "harmonistdaily")
+	if s.Type() != js.TypeString {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(s.String())
+	if err != nil {
+		return nil, err
+	}
+	return DecodeDailyRecords(data)
+}
+
+func (g *game) AppendHistory(file, line string) error {
+	storage := js.Global().Get("localStorage")
+	if storage.Type() != js.TypeObject {
+		return errors.New("localStorage not found")
+	}
+	history := ""
+	if s := storage.Call("getItem", "harmonist"+file); s.Type() == js.TypeString {
+		history = s.String()
+	}
+	storage.Call("setItem", "harmonist"+file, history+line+"\n")
+	return nil
+}
+
 func (g *game) RemoveSaveFile() error {
 	storage := js.Global().Get("localStorage")
 	storage.Call("removeItem", "harmonistsave")
@@ -265,6 +334,14 @@ func (g *game) Load() (bool, error) {
 	if err != nil {
 		return true, err
 	}
+	if lg.Version != Version {
+		return true, fmt.Errorf("saved game for previous version %s.", lg.Version)
+	}
+	// keep the unsaved state of the running game
+	lg.ui = g.ui
+	lg.config = g.config
+	lg.pathCache = g.pathCache
+	lg.rng = g.rng
 	*g = *lg
 
 	// // XXX: gob encoding works badly with gopherjs, it seems, some maps get broken
@@ -289,10 +366,10 @@ func (g *game) LoadConfig() (bool, error) {
 	if err != nil {
 		return true, err
 	}
-	if c.Version != GameConfig.Version {
+	if c.Version != g.config.Version {
 		return true, errors.New("Version mismatch")
 	}
-	GameConfig = *c
+	g.config = *c
 	return true, nil
 }
 
@@ -367,7 +444,7 @@ func (ui *gameui) Init() error {
 		}))
 	canvas.Call(
 		"addEventListener", "mousemove", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-			if CenteredCamera {
+			if ui.CenteredCamera {
 				return nil
 			}
 			e := args[0]
@@ -383,7 +460,7 @@ func (ui *gameui) Init() error {
 		}))
 	ui.menuHover = -1
 	ui.InitElements()
-	SolarizedPalette()
+	ui.SolarizedPalette()
 	ui.HideCursor()
 	settingsActions = append(settingsActions, toggleTiles)
 	return nil
@@ -415,26 +492,26 @@ func (ui *gameui) ReqAnimFrame() {
 }
 
 func (ui *gameui) ApplyToggleLayoutWithClear(clear bool) {
-	GameConfig.Small = !GameConfig.Small
-	if GameConfig.Small {
+	ui.g.config.Small = !ui.g.config.Small
+	if ui.g.config.Small {
 		if clear {
 			ui.Clear()
 			ui.Flush()
 		}
-		UIHeight = 24
-		UIWidth = 80
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	} else {
-		UIHeight = 26
-		if CenteredCamera {
-			UIWidth = 80
+		ui.UIHeight = 26
+		if ui.CenteredCamera {
+			ui.UIWidth = 80
 		} else {
-			UIWidth = 100
+			ui.UIWidth = 100
 		}
 	}
 	canvas := js.Global().Get("document").Call("getElementById", "gamecanvas")
-	canvas.Set("height", 24*UIHeight)
-	canvas.Set("width", 16*UIWidth)
-	ui.g.DrawBuffer = make([]UICell, UIWidth*UIHeight)
+	canvas.Set("height", 24*ui.UIHeight)
+	canvas.Set("width", 16*ui.UIWidth)
+	ui.g.DrawBuffer = make([]UICell, ui.UIWidth*ui.UIHeight)
 	ui.cache = make(map[UICell]js.Value)
 	if clear {
 		ui.Clear()
@@ -467,6 +544,7 @@ func (ui *gameui) PollEvent() (in uiInput) {
 		in.key = "\x1b"
 	case "Enter", "\r", "\n":
 		in.key = "."
+		in.enter = true
 	case "ArrowLeft":
 		in.key = "4"
 	case "ArrowRight":
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type landmarkKind int
+
+const (
+	LandmarkBarrel landmarkKind = iota
+	LandmarkStone
+	LandmarkMagara
+	LandmarkPotion
+)
+
+func (lk landmarkKind) String() (text string) {
+	switch lk {
+	case LandmarkBarrel:
+		text = "barrel"
+	case LandmarkStone:
+		text = "unused stone"
+	case LandmarkMagara:
+		text = "magara"
+	case LandmarkPotion:
+		text = "potion"
+	}
+	return text
+}
+
+type landmark struct {
+	Pos  position
+	Kind landmarkKind
+	Dist int
+}
+
+// RememberedTerrain returns the terrain at a position as the player
+// remembers it.
+func (g *game) RememberedTerrain(pos position) terrain {
+	if t, ok := g.TerrainKnowledge[pos]; ok {
+		return t
+	}
+	return g.Dungeon.Cell(pos).T
+}
+
+func (g *game) landmarkAt(pos position) (landmarkKind, bool) {
+	switch g.RememberedTerrain(pos) {
+	case BarrelCell:
+		return LandmarkBarrel, true
+	case StoneCell:
+		if g.Objects.Stones[pos] != InertStone {
+			return LandmarkStone, true
+		}
+	case MagaraCell:
+		return LandmarkMagara, true
+	case PotionCell:
+		return LandmarkPotion, true
+	}
+	return 0, false
+}
+
+// Landmarks returns the remembered landmarks the player can travel to,
+// sorted by path length.
+func (g *game) Landmarks() []landmark {
+	lms := []landmark{}
+	for i, c := range g.Dungeon.Cells {
+		if !c.Explored {
+			continue
+		}
+		pos := idxtopos(i)
+		if pos == g.Player.Pos || g.ExclusionsMap[pos] {
+			continue
+		}
+		lk, ok := g.landmarkAt(pos)
+		if !ok {
+			continue
+		}
+		pp := &playerPath{game: g, goal: pos}
+		_, cost, found := g.AstarPath(pp, g.Player.Pos, pos)
+		if !found || cost >= unreachable {
+			continue
+		}
+		lms = append(lms, landmark{Pos: pos, Kind: lk, Dist: cost})
+	}
+	sort.SliceStable(lms, func(i, j int) bool {
+		return lms[i].Dist < lms[j].Dist
+	})
+	return lms
+}
+
+func (g *game) NearestLandmark(lk landmarkKind) (position, bool) {
+	for _, lm := range g.Landmarks() {
+		if lm.Kind == lk {
+			return lm.Pos, true
+		}
+	}
+	return InvalidPos, false
+}
+
+func (g *game) GoToLandmark(pos position) error {
+	ex := &examiner{}
+	err := ex.Action(g, pos)
+	if err == nil && !g.MoveToTarget() {
+		err = errors.New("You could not move toward the landmark.")
+	}
+	if ex.Done() {
+		g.Targeting = InvalidPos
+	}
+	return err
+}
+
+func (g *game) GoToNearestLandmark(lk landmarkKind) error {
+	pos, ok := g.NearestLandmark(lk)
+	if !ok {
+		return fmt.Errorf("You do not know any reachable %s.", lk)
+	}
+	return g.GoToLandmark(pos)
+}
@@ -0,0 +1,191 @@
+package main
+
+import "errors"
+
+// level is the state of a level left by the player, kept in the saved game
+// when levels can be revisited.
+type level struct {
+	Dungeon          *dungeon
+	Monsters         []*monster
+	Bands            []bandInfo
+	Objects          objects
+	Places           places
+	Tampered         map[position]tampering
+	MagicalBarriers  map[position]terrain
+	TerrainKnowledge map[position]terrain
+	ExclusionsMap    map[position]bool
+	Notes            map[position]string
+	Turn             int      // turn at which the player left the level
+	Exit             position // stairs taken downwards, if any
+}
+
+const (
+	RevisitForgetTurns = 20  // turns after which hunting monsters give up
+	RevisitPatrolTurns = 100 // turns after which monsters are back on their paths
+)
+
+// StoreLevel keeps the current level, so that the player can come back to
+// it later. The exit is the position of the stairs taken downwards, or
+// InvalidPos.
+func (g *game) StoreLevel(exit position) {
+	if g.Levels == nil {
+		g.Levels = map[int]*level{}
+	}
+	g.Levels[g.Depth] = &level{
+		Dungeon:          g.Dungeon,
+		Monsters:         g.Monsters,
+		Bands:            g.Bands,
+		Objects:          g.Objects,
+		Places:           g.Places,
+		Tampered:         g.Tampered,
+		MagicalBarriers:  g.MagicalBarriers,
+		TerrainKnowledge: g.TerrainKnowledge,
+		ExclusionsMap:    g.ExclusionsMap,
+		Notes:            g.Notes,
+		Turn:             g.Turn,
+		Exit:             exit,
+	}
+}
+
+// PutUpStair puts stairs leading back to the previous level at the starting
+// position of the player in a new level.
+func (g *game) PutUpStair() {
+	g.Dungeon.SetCell(g.Player.Pos, StairCell)
+	g.Objects.Stairs[g.Player.Pos] = UpStair
+}
+
+// Stairs returns the position of some stairs of the level matching a
+// condition, or InvalidPos.
+func (lvl *level) Stairs(match func(st stair) bool) position {
+	pos := InvalidPos
+	for spos, st := range lvl.Objects.Stairs {
+		if match(st) && (!pos.valid() || spos.idx() < pos.idx()) {
+			pos = spos
+		}
+	}
+	return pos
+}
+
+// Ascend takes the up stairs back to the previous level.
+func (g *game) Ascend() error {
+	lvl := g.Levels[g.Depth-1]
+	if lvl == nil {
+		// should not happen
+		return errors.New("The stairs are blocked.")
+	}
+	g.LevelStats()
+	g.ExploredLevels = Max(g.ExploredLevels, g.Depth)
+	g.StoreLevel(InvalidPos)
+	g.Print("You climb back up the stairs.")
+	g.StoryPrint("Ascended stairs")
+	g.Depth--
+	g.DepthPlayerTurn = 0
+	g.Boredom = 0
+	g.PushEvent(&simpleEvent{ERank: g.Ev.Rank(), EAction: PlayerTurn})
+	arrival := lvl.Exit
+	if !arrival.valid() {
+		// the player fell from that level
+		arrival = lvl.Stairs(func(st stair) bool { return st != UpStair })
+	}
+	g.RestoreLevel(arrival)
+	g.Save()
+	return nil
+}
+
+// RestoreLevel makes the kept level at the current depth the current level
+// again, simulating approximately the time elapsed since the player left it.
+// The player arrives at the given position, or at a random place if it is
+// invalid.
+func (g *game) RestoreLevel(arrival position) {
+	lvl := g.Levels[g.Depth]
+	delete(g.Levels, g.Depth)
+	g.InitLevelStructures()
+	g.Dungeon = lvl.Dungeon
+	g.Monsters = lvl.Monsters
+	g.Bands = lvl.Bands
+	g.Objects = lvl.Objects
+	g.Places = lvl.Places
+	g.Tampered = lvl.Tampered
+	g.TerrainKnowledge = lvl.TerrainKnowledge
+	g.ExclusionsMap = lvl.ExclusionsMap
+	g.Notes = lvl.Notes
+	// temporary barriers and clouds vanished in the meantime
+	for pos, t := range lvl.MagicalBarriers {
+		g.Dungeon.SetCell(pos, t)
+	}
+	for _, mons := range g.Monsters {
+		if mons.Exists() {
+			g.MonstersPosCache[mons.Pos.idx()] = mons.Index + 1
+		}
+	}
+	g.SimulateElapsed(g.Turn - lvl.Turn)
+
+	g.CleanEvents()
+	for st := range g.Player.Statuses {
+		if st.Clean() {
+			g.Player.Statuses[st] = 0
+		}
+	}
+	for i := range g.Monsters {
+		g.PushEventRandomIndex(&monsterEvent{ERank: g.Turn, EAction: MonsterTurn, NMons: i})
+	}
+	for _, ev := range g.LevelEvents() {
+		if ev != EarthquakeLevel {
+			g.StartLevelEvent(ev)
+		}
+	}
+
+	pos := arrival
+	if !pos.valid() {
+		pos = g.FreePassableCell()
+	}
+	g.Player.Pos = pos
+	if mons := g.MonsterAt(pos); mons.Exists() {
+		// make room for the player
+		free := []position{}
+		for _, npos := range g.Dungeon.FreeNeighbors(pos) {
+			if g.Dungeon.Cell(npos).IsPassable() && !g.MonsterAt(npos).Exists() {
+				free = append(free, npos)
+			}
+		}
+		if len(free) > 0 {
+			mons.PlaceAt(g, free[g.RandInt(len(free))])
+		} else {
+			mons.PlaceAt(g, g.FreePassableCell())
+		}
+	}
+	g.PrintfStyled("You are back at depth %d.", logSpecial, g.Depth)
+	g.ComputeLOS()
+	g.MakeMonstersAware()
+	g.Emit(levelEntered{Depth: g.Depth})
+}
+
+// SimulateElapsed updates approximately the monsters of a restored level for
+// the turns elapsed since the player left: statuses end, hunting monsters
+// give up, and monsters go back to their usual places. They still remember
+// where they last saw the player.
+func (g *game) SimulateElapsed(elapsed int) {
+	for _, mons := range g.Monsters {
+		if !mons.Exists() {
+			continue
+		}
+		for i := range mons.Statuses {
+			mons.Statuses[i] = Max(0, mons.Statuses[i]-elapsed)
+		}
+		mons.Path = nil
+		if mons.State == Hunting && elapsed > RevisitForgetTurns {
+			mons.MakeWander()
+		}
+		if mons.State != Wandering || elapsed <= RevisitPatrolTurns {
+			continue
+		}
+		bpath := g.Bands[mons.Band].Path
+		if len(bpath) == 0 {
+			continue
+		}
+		pos := bpath[g.RandInt(len(bpath))]
+		if pos != mons.Pos && mons.CanPass(g, pos) && !g.MonsterAt(pos).Exists() {
+			mons.PlaceAt(g, pos)
+		}
+	}
+}
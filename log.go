@@ -1,6 +1,9 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type logStyle int
 
@@ -20,6 +23,8 @@ type logEntry struct {
 	Tick  bool
 	Style logStyle
 	Dups  int
+	Turn  int
+	Depth int
 }
 
 func (e logEntry) String() string {
@@ -29,6 +34,78 @@ func (e logEntry) String() string {
 	return e.Text
 }
 
+func (e logEntry) Stamped() string {
+	return fmt.Sprintf("Depth %2d|Turn %5d| %s", e.Depth, e.Turn, e)
+}
+
+type logFilter int
+
+const (
+	LogFilterNone logFilter = iota
+	LogFilterHits
+	LogFilterCritics
+	LogFilterStatusEnds
+	LogFilterSpecials
+)
+
+func (f logFilter) String() (text string) {
+	switch f {
+	case LogFilterNone:
+		text = "all"
+	case LogFilterHits:
+		text = "hits"
+	case LogFilterCritics:
+		text = "criticals"
+	case LogFilterStatusEnds:
+		text = "status ends"
+	case LogFilterSpecials:
+		text = "specials"
+	}
+	return text
+}
+
+func (f logFilter) Next() logFilter {
+	if f == LogFilterSpecials {
+		return LogFilterNone
+	}
+	return f + 1
+}
+
+func (f logFilter) Match(e logEntry) bool {
+	switch f {
+	case LogFilterHits:
+		return e.Style == logPlayerHit || e.Style == logMonsterHit
+	case LogFilterCritics:
+		return e.Style == logCritic
+	case LogFilterStatusEnds:
+		return e.Style == logStatusEnd
+	case LogFilterSpecials:
+		return e.Style == logSpecial
+	default:
+		return true
+	}
+}
+
+// FilteredLog returns the log entries matching the given filter and
+// containing the given text (case insensitive).
+func (g *game) FilteredLog(f logFilter, search string) []logEntry {
+	if f == LogFilterNone && search == "" {
+		return g.Log
+	}
+	search = strings.ToLower(search)
+	entries := []logEntry{}
+	for _, e := range g.Log {
+		if !f.Match(e) {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(e.Text), search) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
 func (g *game) Print(s string) {
 	e := logEntry{Text: s, Index: g.LogIndex}
 	g.PrintEntry(e)
@@ -50,6 +127,8 @@ func (g *game) PrintfStyled(format string, style logStyle, a ...interface{}) {
 }
 
 func (g *game) PrintEntry(e logEntry) {
+	e.Turn = g.Turn
+	e.Depth = g.Depth
 	if e.Index == g.LogNextTick {
 		e.Tick = true
 	}
@@ -77,7 +156,7 @@ func (g *game) StoryPrintf(format string, a ...interface{}) {
 }
 
 func (g *game) CrackSound() (text string) {
-	switch RandInt(4) {
+	switch g.RandInt(4) {
 	case 0:
 		text = "Crack!"
 	case 1:
@@ -91,7 +170,7 @@ func (g *game) CrackSound() (text string) {
 }
 
 func (g *game) ExplosionSound() (text string) {
-	switch RandInt(3) {
+	switch g.RandInt(3) {
 	case 0:
 		text = "Bang!"
 	case 1:
@@ -323,7 +323,7 @@ func (g *game) SeeNotable(c cell, pos position) {
 	case MagaraCell:
 		mag := g.Objects.Magaras[pos]
 		dp := &mappingPath{game: g}
-		_, l, ok := AstarPath(dp, g.Player.Pos, pos)
+		_, l, ok := g.AstarPath(dp, g.Player.Pos, pos)
 		if ok {
 			g.StoryPrintf("Spotted %s (distance: %d)", mag, l)
 		} else {
@@ -332,7 +332,7 @@ func (g *game) SeeNotable(c cell, pos position) {
 	case ItemCell:
 		it := g.Objects.Items[pos]
 		dp := &mappingPath{game: g}
-		_, l, ok := AstarPath(dp, g.Player.Pos, pos)
+		_, l, ok := g.AstarPath(dp, g.Player.Pos, pos)
 		if ok {
 			g.StoryPrintf("Spotted %s (distance: %d)", it.ShortDesc(g), l)
 		} else {
@@ -341,7 +341,7 @@ func (g *game) SeeNotable(c cell, pos position) {
 	case StairCell:
 		st := g.Objects.Stairs[pos]
 		dp := &mappingPath{game: g}
-		_, l, ok := AstarPath(dp, g.Player.Pos, pos)
+		_, l, ok := g.AstarPath(dp, g.Player.Pos, pos)
 		if ok {
 			g.StoryPrintf("Discovered %s (distance: %d)", st, l)
 		} else {
@@ -349,7 +349,7 @@ func (g *game) SeeNotable(c cell, pos position) {
 		}
 	case FakeStairCell:
 		dp := &mappingPath{game: g}
-		_, l, ok := AstarPath(dp, g.Player.Pos, pos)
+		_, l, ok := g.AstarPath(dp, g.Player.Pos, pos)
 		if ok {
 			g.StoryPrintf("Discovered %s (distance: %d)", NormalStairShortDesc, l)
 		} else {
@@ -359,7 +359,7 @@ func (g *game) SeeNotable(c cell, pos position) {
 		st := g.Objects.Story[pos]
 		if st == StoryArtifactSealed {
 			dp := &mappingPath{game: g}
-			_, l, ok := AstarPath(dp, g.Player.Pos, pos)
+			_, l, ok := g.AstarPath(dp, g.Player.Pos, pos)
 			if ok {
 				g.StoryPrintf("Discovered Portal Moon Gem Artifact (distance: %d)", l)
 			} else {
@@ -460,9 +460,9 @@ func (g *game) ComputeRayHighlight(pos position) {
 }
 
 func (g *game) ComputeNoise() {
-	dij := &noisePath{game: g}
+	dij := &soundPath{game: g}
 	rg := DefaultLOSRange
-	nm := Dijkstra(dij, []position{g.Player.Pos}, rg)
+	nm := g.Dijkstra(dij, []position{g.Player.Pos}, rg)
 	count := 0
 	for k := range g.Noise {
 		delete(g.Noise, k)
@@ -479,7 +479,7 @@ func (g *game) ComputeNoise() {
 		}
 		mons := g.MonsterAt(pos)
 		if mons.Exists() && mons.State != Resting && mons.State != Watching &&
-			(RandInt(rmax) > 0 || g.Dungeon.Cell(mons.Pos).T == QueenRockCell) {
+			(g.RandInt(rmax) > 0 || g.Dungeon.Cell(mons.Pos).T == QueenRockCell) {
 			switch mons.Kind {
 			case MonsMirrorSpecter, MonsSatowalgaPlant, MonsButterfly:
 				if mons.Kind == MonsMirrorSpecter && g.Player.Inventory.Body == CloakHear {
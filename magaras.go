@@ -84,7 +84,7 @@ func (g *game) RandomStartingMagara() magara {
 	var mag magaraKind
 loop:
 	for {
-		mag = mags[RandInt(len(mags))]
+		mag = mags[g.RandInt(len(mags))]
 		for _, m := range g.GeneratedMagaras {
 			if m == mag {
 				continue loop
@@ -105,7 +105,7 @@ func (g *game) RandomMagara() magara {
 	var mag magaraKind
 loop:
 	for {
-		mag = mags[RandInt(len(mags))]
+		mag = mags[g.RandInt(len(mags))]
 		for _, m := range g.GeneratedMagaras {
 			if m == mag {
 				continue loop
@@ -144,6 +144,10 @@ func (g *game) UseMagara(n int) (err error) {
 	if mag.Charges <= 0 {
 		return errors.New("Not enough charges for using this magara.")
 	}
+	err = g.ConfirmConduct(ConductNoEvocation)
+	if err != nil {
+		return err
+	}
 	switch mag.Kind {
 	case BlinkMagara:
 		err = g.EvokeBlink()
@@ -193,53 +197,9 @@ func (g *game) UseMagara(n int) (err error) {
 	if err != nil {
 		return err
 	}
-	g.Stats.MagarasUsed++
-	g.Stats.UsedMagaras[mag.Kind]++
-	g.Stats.DMagaraUses[g.Depth]++
 	g.Player.MP -= mag.MPCost(g)
 	g.Player.Magaras[n].Charges--
-	g.StoryPrintf("Evoked %s (MP: %d, Charges: %d)", mag, g.Player.MP, g.Player.Magaras[n].Charges)
-	if mag.Harmonic() {
-		g.Stats.HarmonicMagUse++
-		if g.Stats.HarmonicMagUse == 6 {
-			AchHarmonistNovice.Get(g)
-		}
-		if g.Stats.HarmonicMagUse == 11 {
-			AchHarmonistInitiate.Get(g)
-		}
-		if g.Stats.HarmonicMagUse == 16 {
-			AchHarmonistMaster.Get(g)
-		}
-	} else if mag.Oric() {
-		g.Stats.OricMagUse++
-		if g.Stats.OricMagUse == 6 {
-			AchNoviceOricCelmist.Get(g)
-		}
-		if g.Stats.OricMagUse == 11 {
-			AchInitiateOricCelmist.Get(g)
-		}
-		if g.Stats.OricMagUse == 16 {
-			AchMasterOricCelmist.Get(g)
-		}
-	} else if mag.Kind == FireMagara {
-		g.Stats.FireUse++
-		if g.Stats.FireUse == 2 {
-			AchPyromancerNovice.Get(g)
-		}
-		if g.Stats.FireUse == 4 {
-			AchPyromancerInitiate.Get(g)
-		}
-		if g.Stats.FireUse == 6 {
-			AchPyromancerMaster.Get(g)
-		}
-	}
-	switch mag.Kind {
-	case TeleportMagara, TeleportOtherMagara, BlinkMagara, SwappingMagara, DispersalMagara:
-		g.Stats.OricTelUse++
-		if g.Stats.OricTelUse == 14 {
-			AchTeleport.Get(g)
-		}
-	}
+	g.Emit(magaraEvoked{Magara: g.Player.Magaras[n]})
 	g.RenewEvent(DurationTurn)
 	return nil
 }
@@ -445,9 +405,10 @@ func (g *game) BlinkPos(mpassable bool) position {
 	if len(losPos) == 0 {
 		return InvalidPos
 	}
-	npos := losPos[RandInt(len(losPos))]
+	SortPositions(losPos)
+	npos := losPos[g.RandInt(len(losPos))]
 	for i := 0; i < 4; i++ {
-		pos := losPos[RandInt(len(losPos))]
+		pos := losPos[g.RandInt(len(losPos))]
 		if npos.Distance(g.Player.Pos) < pos.Distance(g.Player.Pos) {
 			npos = pos
 		}
@@ -481,7 +442,7 @@ func (g *game) MonstersInLOS() []*monster {
 	}
 	// shuffle before, because the order could be unnaturally predicted
 	for i := 0; i < len(ms); i++ {
-		j := i + RandInt(len(ms)-i)
+		j := i + g.RandInt(len(ms)-i)
 		ms[i], ms[j] = ms[j], ms[i]
 	}
 	return ms
@@ -496,7 +457,7 @@ func (g *game) MonstersInCardinalLOS() []*monster {
 	}
 	// shuffle before, because the order could be unnaturally predicted
 	for i := 0; i < len(ms); i++ {
-		j := i + RandInt(len(ms)-i)
+		j := i + g.RandInt(len(ms)-i)
 		ms[i], ms[j] = ms[j], ms[i]
 	}
 	return ms
@@ -614,13 +575,13 @@ func (g *game) EvokeFog() error {
 
 func (g *game) Fog(at position, radius int) {
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{at}, radius)
+	nm := g.Dijkstra(dij, []position{at}, radius)
 	nm.iter(at, func(n *node) {
 		pos := n.Pos
 		_, ok := g.Clouds[pos]
 		if !ok && g.Dungeon.Cell(pos).AllowsFog() {
 			g.Clouds[pos] = CloudFog
-			g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationFog + RandInt(DurationFog/2), EAction: CloudEnd, Pos: pos})
+			g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationFog + g.RandInt(DurationFog/2), EAction: CloudEnd, Pos: pos})
 		}
 	})
 	g.ComputeLOS()
@@ -676,7 +637,7 @@ func (g *game) EvokeSleeping() error {
 		}
 		mons.State = Resting
 		mons.Dir = NoDir
-		mons.ExhaustTime(g, 4+RandInt(2))
+		mons.ExhaustTime(g, 4+g.RandInt(2))
 		targets = append(targets, g.Ray(mons.Pos)...)
 	}
 	if len(targets) == 0 {
@@ -727,7 +688,7 @@ func (g *game) EvokeLignification() error {
 
 func (g *game) EvokeNoise() error {
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{g.Player.Pos}, 23)
+	nm := g.Dijkstra(dij, []position{g.Player.Pos}, 23)
 	noises := []position{}
 	g.NoiseIllusion = map[position]bool{}
 	for _, mons := range g.Monsters {
@@ -852,7 +813,7 @@ func (g *game) CreateMagicalBarrierAt(pos position) {
 	g.Dungeon.SetCell(pos, BarrierCell)
 	delete(g.Clouds, pos)
 	g.MagicalBarriers[pos] = t
-	g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationMagicalBarrier + RandInt(DurationMagicalBarrier/2), Pos: pos, EAction: ObstructionEnd})
+	g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationMagicalBarrier + g.RandInt(DurationMagicalBarrier/2), Pos: pos, EAction: ObstructionEnd})
 }
 
 func (g *game) EvokeEnergyMagara() error {
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"time"
 )
 
 func main() {
@@ -22,19 +23,67 @@ func main() {
 	opt256colors := flag.Bool("x", !color8, "use xterm 256-color palette (solarized approximation)")
 	optNoAnim := flag.Bool("n", false, "no animations")
 	optReplay := flag.String("r", "", "path to replay file")
+	optPublish := flag.String("p", "", "publish the game for spectators on a Unix socket path or TCP address")
+	optAgent := flag.Bool("agent", false, "play with actions and observations as JSON lines on standard input and output")
+	optBot := flag.Int("bot", 0, "play `n` games with the built-in bot and report statistics")
+	optTelemetry := flag.Bool("telemetry", false, "write game events as JSON lines to a file per game in the data directory")
 	flag.Parse()
+	g := &game{}
+	ui := NewGameUI(g)
 	if *optSolarized {
-		SolarizedPalette()
+		ui.SolarizedPalette()
 	} else if color8 && !*opt256colors || !color8 && *opt8colors {
-		SolarizedPalette()
-		Simple8ColorPalette()
+		ui.SolarizedPalette()
+		ui.Simple8ColorPalette()
 	}
 	if *optVersion {
 		fmt.Println(Version)
 		os.Exit(0)
 	}
+	if flag.Arg(0) == "serve" {
+		err := Serve(flag.Arg(1))
+		if err != nil {
+			log.Printf("harmonist: serve: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if *optBot > 0 {
+		err := BotBenchmark(*optBot, os.Stdout, *optTelemetry)
+		if err != nil {
+			log.Printf("harmonist: bot: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if *optAgent {
+		ui := NewAgentUI(g, os.Stdin, os.Stdout)
+		var err error
+		if *optTelemetry {
+			err = g.StartTelemetry()
+		}
+		if err == nil {
+			err = ui.PlayAgent()
+		}
+		if terr := g.StopTelemetry(); err == nil {
+			err = terr
+		}
+		if err != nil {
+			log.Printf("harmonist: agent: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if flag.Arg(0) == "watch" {
+		err := Watch(ui, flag.Arg(1))
+		if err != nil {
+			log.Printf("harmonist: watch: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	if *optReplay != "" {
-		err := Replay(*optReplay)
+		err := Replay(ui, *optReplay)
 		if err != nil {
 			log.Printf("harmonist: replay: %v\n", err)
 			os.Exit(1)
@@ -42,28 +91,40 @@ func main() {
 		os.Exit(0)
 	}
 	if *optCenteredCamera {
-		CenteredCamera = true
+		ui.CenteredCamera = true
+		ui.UIWidth = 80
 	}
 	if *optNoAnim {
-		DisableAnimations = true
+		ui.DisableAnimations = true
 	}
+	ui.Telemetry = *optTelemetry
 
-	ui := &gameui{}
-	g := &game{}
-	ui.g = g
-	if CenteredCamera {
-		UIWidth = 80
-	}
 	err := ui.Init()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "harmonist: %v\n", err)
 		os.Exit(1)
 	}
 	defer ui.Close()
+	if *optPublish != "" {
+		err := ui.Publish(*optPublish)
+		if err != nil {
+			log.Printf("harmonist: publish: %v\n", err)
+			os.Exit(1)
+		}
+		defer ui.spectators.Close()
+	}
+
+	ui.Play()
+}
 
-	LinkColors()
-	GameConfig.DarkLOS = true
-	GameConfig.Version = Version
+// Play loads the configuration and the saved game, if any, and runs the game
+// until the player quits.
+func (ui *gameui) Play() {
+	g := ui.g
+	ui.LinkColors()
+	g.config.DarkLOS = true
+	g.config.LevelDebrief = true
+	g.config.Version = Version
 
 	load, err := g.LoadConfig()
 	var cfgerrstr string
@@ -75,12 +136,28 @@ func main() {
 			cfgreseterr = fmt.Sprintf("Error resetting config: %s", err.Error())
 		}
 	} else if load {
-		CustomKeys = true
+		ui.CustomKeys = true
 	}
-	ApplyConfig()
+	ui.ApplyConfig()
 	ui.PostConfig()
-	ui.DrawWelcome()
+	daily := ui.DrawWelcome()
 	load, err = g.Load()
+	var telerr error
+	if ui.Telemetry {
+		// after loading, which replaces the game state
+		telerr = g.StartTelemetry()
+		defer g.StopTelemetry()
+	}
+	var dailyerr error
+	if daily && (!load || err != nil) {
+		dailyerr = g.StartDaily(DailyDate(time.Now()))
+	} else if !load || err != nil {
+		g.SetDifficulty(ui.DifficultyMenu())
+		g.Draft = ui.DraftMenu(g.DraftKits())
+	}
+	if !load || err != nil {
+		g.Conducts = ui.ConductsMenu()
+	}
 	if !load {
 		g.InitLevel()
 	} else if err != nil {
@@ -89,6 +166,17 @@ func main() {
 		g.PrintStyled("Could not load saved game… starting new game.", logError)
 	} else {
 		ui.DrawBufferInit()
+		if daily {
+			g.PrintStyled("Finish your saved game before starting the daily challenge.", logError)
+		}
+	}
+	if dailyerr != nil {
+		g.PrintfStyled("Error starting daily challenge: %v", logError, dailyerr)
+	} else if g.Daily != "" && g.Turn == 0 {
+		g.PrintfStyled("Daily challenge of %s: good luck!", logSpecial, g.Daily)
+	}
+	if telerr != nil {
+		g.PrintfStyled("Error starting telemetry: %v", logError, telerr)
 	}
 	if cfgerrstr != "" {
 		g.PrintStyled(cfgerrstr, logError)
@@ -145,6 +145,17 @@ func (mk monsterKind) GoodFlair() bool {
 	}
 }
 
+// Tracker reports whether monsters of the kind follow the scent of the
+// player.
+func (mk monsterKind) Tracker() bool {
+	switch mk {
+	case MonsDog, MonsCrazyImp:
+		return true
+	default:
+		return false
+	}
+}
+
 func (mk monsterKind) Notable() bool {
 	switch mk {
 	case MonsCrazyImp, MonsEarthDragon, MonsHazeCat:
@@ -163,6 +174,17 @@ func (mk monsterKind) CanOpenDoors() bool {
 	}
 }
 
+// NoticesTampering reports whether monsters of the kind notice when cells
+// in view have changed, like an extinguished light or a burnt door.
+func (mk monsterKind) NoticesTampering() bool {
+	switch mk {
+	case MonsGuard, MonsHighGuard, MonsMadNixe, MonsOricCelmist, MonsHarmonicCelmist, MonsVampire:
+		return true
+	default:
+		return false
+	}
+}
+
 func (mk monsterKind) Patrolling() bool {
 	switch mk {
 	case MonsGuard, MonsHighGuard, MonsMadNixe, MonsOricCelmist, MonsHarmonicCelmist:
@@ -506,15 +528,17 @@ type monster struct {
 	Search        position
 	Alerted       bool
 	Waiting       int
+	RaisingAlarm  bool
+	Noticed       map[position]int // turn of the last noticed change of tampered cells
 }
 
-func (m *monster) Init() {
+func (m *monster) Init(g *game) {
 	m.Attack = m.Kind.BaseAttack()
 	m.Pos = InvalidPos
 	m.LOS = map[position]bool{}
 	m.LastKnownPos = InvalidPos
 	m.Search = InvalidPos
-	if RandInt(2) == 0 {
+	if g.RandInt(2) == 0 {
 		m.Left = true
 	}
 	switch m.Kind {
@@ -562,19 +586,19 @@ func (m *monster) Exists() bool {
 	return m != nil && !m.Dead
 }
 
-func (m *monster) Alternate() {
+func (m *monster) Alternate(g *game) {
 	if m.Left {
-		if RandInt(4) > 0 {
-			m.Dir = m.Dir.Left()
+		if g.RandInt(4) > 0 {
+			m.Dir = m.Dir.Left(g)
 		} else {
-			m.Dir = m.Dir.Right()
+			m.Dir = m.Dir.Right(g)
 			m.Left = false
 		}
 	} else {
-		if RandInt(3) > 0 {
-			m.Dir = m.Dir.Right()
+		if g.RandInt(3) > 0 {
+			m.Dir = m.Dir.Right(g)
 		} else {
-			m.Dir = m.Dir.Left()
+			m.Dir = m.Dir.Left(g)
 			m.Left = true
 		}
 	}
@@ -723,7 +747,7 @@ func (m *monster) Explode(g *game) {
 			}
 		} else if g.Player.Pos == pos {
 			m.InflictDamage(g, 1, 1)
-		} else if c.IsDestructible() && RandInt(3) > 0 {
+		} else if c.IsDestructible() && g.RandInt(3) > 0 {
 			if c.T.IsDiggable() {
 				g.Dungeon.SetCell(pos, RubbleCell)
 			} else {
@@ -745,12 +769,7 @@ func (m *monster) Explode(g *game) {
 
 func (m *monster) NaturalAwake(g *game) {
 	m.Target = m.NextTarget(g)
-	switch g.Bands[m.Band].Beh {
-	case BehGuard:
-		m.StartWatching()
-	default:
-		m.MakeWander()
-	}
+	g.Bands[m.Band].Behaviour().Awake(g, m)
 	m.GatherBand(g)
 }
 
@@ -770,121 +789,44 @@ func (m *monster) RandomFreeNeighbor(g *game) position {
 	if len(fnb) == 0 {
 		return m.Pos
 	}
-	samedir := fnb[RandInt(len(fnb))]
+	samedir := fnb[g.RandInt(len(fnb))]
 	for _, pos := range fnb {
 		if m.Dir.InViewCone(m.Pos, pos.To(pos.Dir(m.Pos))) {
 			samedir = pos
 			break
 		}
 	}
-	if RandInt(4) > 0 {
+	if g.RandInt(4) > 0 {
 		return samedir
 	}
-	return fnb[RandInt(len(fnb))]
+	return fnb[g.RandInt(len(fnb))]
 }
 
-type mbehaviour int
-
-const (
-	BehPatrol mbehaviour = iota
-	BehGuard
-	BehWander
-	BehExplore
-	BehCrazyImp
-)
-
-var SearchAroundCache []position
-
 func (m *monster) SearchAround(g *game, pos position, radius int) position {
 	dij := &monPath{game: g, monster: m}
-	nm := Dijkstra(dij, []position{pos}, radius)
-	SearchAroundCache = SearchAroundCache[:0]
+	nm := g.Dijkstra(dij, []position{pos}, radius)
+	pc := g.PathCache()
+	pc.searchAround = pc.searchAround[:0]
 	nm.iter(pos, func(n *node) {
-		SearchAroundCache = append(SearchAroundCache, n.Pos)
+		pc.searchAround = append(pc.searchAround, n.Pos)
 	})
-	if len(SearchAroundCache) > 0 {
-		p := SearchAroundCache[RandInt(len(SearchAroundCache))]
+	if len(pc.searchAround) > 0 {
+		p := pc.searchAround[g.RandInt(len(pc.searchAround))]
 		return p
 	}
 	return InvalidPos
 }
 
-func (m *monster) NextTarget(g *game) (pos position) {
-	band := g.Bands[m.Band]
-	pos = m.Pos
-	switch band.Beh {
-	case BehWander:
-		if m.Pos.Distance(band.Path[0]) < 8+RandInt(8) {
-			pos = m.SearchAround(g, m.Pos, 4)
-			if pos != InvalidPos {
-				break
-			}
-		}
-		if m.Search != InvalidPos && RandInt(2) == 0 {
-			pos = m.SearchAround(g, m.Search, 7)
-			if pos != InvalidPos {
-				break
-			}
-		}
-		pos = m.SearchAround(g, band.Path[0], 7)
-		if pos != InvalidPos {
-			break
-		}
-		pos = band.Path[0]
-	case BehExplore:
-		if m.Kind.CanOpenDoors() {
-			if m.Search != InvalidPos && RandInt(4) == 0 {
-				pos = m.SearchAround(g, m.Search, 7)
-			} else {
-				pos = m.SearchAround(g, pos, 5)
-			}
-			if pos != InvalidPos {
-				break
-			}
-		}
-		pos = band.Path[RandInt(len(band.Path))]
-	case BehGuard:
-		if m.Search != InvalidPos && m.Search.Distance(m.Pos) < 5 && RandInt(2) == 0 {
-			pos = m.SearchAround(g, m.Search, 3)
-			if pos != InvalidPos {
-				break
-			}
-		}
-		pos = band.Path[0]
-	case BehPatrol:
-		if m.Search != InvalidPos && RandInt(4) > 0 {
-			pos = m.SearchAround(g, m.Search, 7)
-			if pos != m.Pos && pos != InvalidPos {
-				break
-			}
-		}
-		if band.Path[0] == m.Target {
-			pos = band.Path[1]
-		} else if band.Path[1] == m.Target {
-			pos = band.Path[0]
-		} else if band.Path[0].Distance(m.Pos) < band.Path[1].Distance(m.Pos) {
-			pos = band.Path[0]
-			if RandInt(4) == 0 {
-				pos = band.Path[1]
-			}
-		} else {
-			pos = band.Path[1]
-			if RandInt(4) == 0 {
-				pos = band.Path[0]
-			}
-		}
-	case BehCrazyImp:
-		path := m.APath(g, m.Pos, g.Player.Pos)
-		if len(path) == 0 {
-			pos = m.SearchAround(g, m.Pos, 3)
-		} else {
-			pos = g.Player.Pos
-		}
-	}
-	return pos
+// NextTarget returns the next place the monster heads to, according to the
+// behaviour of its band.
+func (m *monster) NextTarget(g *game) position {
+	return g.Bands[m.Band].Behaviour().NextTarget(g, m)
 }
 
 func (m *monster) HandleMonsSpecifics(g *game) (done bool) {
+	if g.Bands[m.Band].Behaviour().Turn(g, m) {
+		return true
+	}
 	switch m.Kind {
 	case MonsSatowalgaPlant:
 		switch m.State {
@@ -896,8 +838,8 @@ func (m *monster) HandleMonsSpecifics(g *game) (done bool) {
 				m.StartWatching()
 			}
 		default:
-			if RandInt(4) > 0 {
-				m.Alternate()
+			if g.RandInt(4) > 0 {
+				m.Alternate(g)
 			}
 		}
 		// oklob plants are static ranged-only
@@ -923,7 +865,7 @@ func (m *monster) HandleMonsSpecifics(g *game) (done bool) {
 			}
 		}
 	case MonsCrazyImp:
-		if g.Player.Sees(m.Pos) && RandInt(2) == 0 && !m.Status(MonsConfused) && !m.Status(MonsExhausted) {
+		if g.Player.Sees(m.Pos) && g.RandInt(2) == 0 && !m.Status(MonsConfused) && !m.Status(MonsExhausted) {
 			g.PrintStyled("Crazy Imp: “♫ larilon, larila ♫ ♪”", logSpecial)
 			g.MakeNoise(SingingNoise, m.Pos)
 			//g.ui.MusicAnimation(m.Pos)
@@ -941,31 +883,23 @@ func (m *monster) HandleWatching(g *game) {
 	if m.Kind == MonsHazeCat {
 		turns = 3
 	}
-	if m.Watching+RandInt(2) < turns {
-		m.Alternate()
+	if g.Player.HasStatus(StatusAlarm) {
+		// heightened vigilance
+		turns += 2
+	}
+	if m.Watching+g.RandInt(2) < turns {
+		m.Alternate(g)
 		m.Watching++
-		if m.Kind == MonsDog {
-			dij := &monPath{game: g, monster: m}
-			nm := Dijkstra(dij, []position{m.Pos}, DogFlairDist)
-			if _, ok := nm.at(g.Player.Pos); ok {
-				m.Target = g.Player.Pos
+		if m.Kind.Tracker() {
+			if pos, ok := m.SmellScent(g); ok {
+				m.Target = pos
 				m.MakeWander()
 			}
 		}
 	} else {
 		// pick a random cell: more escape strategies for the player
 		m.Target = m.NextTarget(g)
-		switch g.Bands[m.Band].Beh {
-		case BehGuard:
-			m.Alternate()
-			if m.Pos != m.Target {
-				m.MakeWander()
-				m.GatherBand(g)
-			}
-		default:
-			m.MakeWander()
-			m.GatherBand(g)
-		}
+		g.Bands[m.Band].Behaviour().StopWatching(g, m)
 	}
 	g.Ev.Renew(g, DurationTurn)
 }
@@ -1008,12 +942,21 @@ func (m *monster) HandleEndPath(g *game) {
 		// the cell where the player was last noticed may not be recheable for the monster
 		m.Search = InvalidPos
 	}
+	if m.RaisingAlarm {
+		m.RaisingAlarm = false
+		if m.Pos == m.Target && g.Dungeon.Cell(m.Pos).T == AlarmCell {
+			g.RaiseAlarm(m)
+		}
+	}
 	switch m.State {
 	case Wandering, Hunting:
 		if !m.Peaceful(g) {
 			if !m.SeesPlayer(g) {
+				if m.State == Hunting && m.GoRaiseAlarm(g) {
+					break
+				}
 				m.StartWatching()
-				m.Alternate()
+				m.Alternate(g)
 			}
 		} else {
 			m.Target = m.NextTarget(g)
@@ -1087,7 +1030,7 @@ func (m *monster) HandleMove(g *game) {
 			}
 			m.Path = m.Path[:len(m.Path)-1]
 		}
-	case (mons.Pos == target && m.Pos == monstarget || m.Waiting > 5+RandInt(2)) && !mons.Status(MonsLignified):
+	case (mons.Pos == target && m.Pos == monstarget || m.Waiting > 5+g.RandInt(2)) && !mons.Status(MonsLignified):
 		target := mons.Pos
 		monstarget := m.Pos
 		m.MoveTo(g, target)
@@ -1099,7 +1042,7 @@ func (m *monster) HandleMove(g *game) {
 		g.MonstersPosCache[m.Pos.idx()] = m.Index + 1
 		mons.Swapped = true
 	case m.State == Hunting && mons.State != Hunting:
-		if m.Waiting > 2+RandInt(3) {
+		if m.Waiting > 2+g.RandInt(3) {
 			if mons.Peaceful(g) {
 				mons.MakeWander()
 			} else {
@@ -1111,7 +1054,7 @@ func (m *monster) HandleMove(g *game) {
 		}
 		m.Waiting++
 	case !mons.SeesPlayer(g) && mons.State != Hunting:
-		if m.Waiting > 1+RandInt(2) && mons.Kind != MonsSatowalgaPlant {
+		if m.Waiting > 1+g.RandInt(2) && mons.Kind != MonsSatowalgaPlant {
 			mons.MakeWanderAt(mons.RandomFreeNeighbor(g))
 		} else {
 			m.Path = m.APath(g, m.Pos, m.Target)
@@ -1136,14 +1079,17 @@ func (m *monster) HandleTurn(g *game) {
 	}
 	ppos := g.Player.Pos
 	mpos := m.Pos
-	switch m.Kind {
-	case MonsGuard, MonsHighGuard:
+	switch {
+	case m.Kind == MonsGuard, m.Kind == MonsHighGuard:
 		// they have to put lights on, could be optimized (TODO)
 		m.ComputeLOS(g)
+	case m.Kind.NoticesTampering() && len(g.Tampered) > 0:
+		m.ComputeLOS(g)
 	}
 	m.MakeAware(g)
+	m.NoticeTampering(g)
 	if m.State == Resting {
-		if RandInt(3000) == 0 || m.Kind.ShallowSleep() && RandInt(10) == 0 {
+		if g.RandInt(3000) == 0 || m.Kind.ShallowSleep() && g.RandInt(10) == 0 {
 			m.NaturalAwake(g)
 		}
 		g.RenewEvent(DurationTurn)
@@ -1212,7 +1158,7 @@ func (m *monster) InvertFoliage(g *game) {
 }
 
 func (m *monster) Exhaust(g *game) {
-	m.ExhaustTime(g, DurationExhaustionMonster+RandInt(DurationExhaustionMonster/2))
+	m.ExhaustTime(g, DurationExhaustionMonster+g.RandInt(DurationExhaustionMonster/2))
 }
 
 func (m *monster) ExhaustTime(g *game, t int) {
@@ -1224,7 +1170,7 @@ func (m *monster) HitPlayer(g *game) {
 		return
 	}
 	dmg := m.Attack
-	clang := RandInt(4) == 0
+	clang := g.RandInt(4) == 0
 	noise := g.HitNoise(clang)
 	g.MakeNoise(noise, g.Player.Pos)
 	var sclang string
@@ -1342,7 +1288,7 @@ func (m *monster) HitSideEffects(g *game) {
 		g.PlacePlayerAt(ompos)
 		g.Print("The flying milfid makes you swap positions.")
 		g.StoryPrintf("Position swap by %s", m.Kind)
-		m.ExhaustTime(g, 5+RandInt(5))
+		m.ExhaustTime(g, 5+g.RandInt(5))
 		if g.Dungeon.Cell(g.Player.Pos).T == ChasmCell {
 			g.PushAgainEvent(&simpleEvent{ERank: g.Ev.Rank(), EAction: AbyssFall})
 		}
@@ -1505,7 +1451,7 @@ func (g *game) BarrierCandidates(pos position, todir direction) []position {
 			candidates[len(candidates)-1], candidates[i] = candidates[i], candidates[len(candidates)-1]
 		}
 	}
-	if len(candidates) == 4 && RandInt(2) == 0 {
+	if len(candidates) == 4 && g.RandInt(2) == 0 {
 		candidates[1], candidates[2] = candidates[2], candidates[1]
 	}
 	if len(candidates) == 4 {
@@ -1584,7 +1530,7 @@ func (m *monster) ThrowJavelin(g *game) bool {
 		return false
 	}
 	dmg := DmgNormal
-	clang := RandInt(4) == 0
+	clang := g.RandInt(4) == 0
 	noise := g.HitNoise(clang)
 	var sclang string
 	if clang {
@@ -1595,7 +1541,7 @@ func (m *monster) ThrowJavelin(g *game) bool {
 	g.ui.MonsterJavelinAnimation(g.Ray(m.Pos), true)
 	g.MakeNoise(noise, g.Player.Pos)
 	m.InflictDamage(g, dmg, dmg)
-	m.ExhaustTime(g, 10+RandInt(5))
+	m.ExhaustTime(g, 10+g.RandInt(5))
 	g.RenewEvent(DurationTurn)
 	return true
 }
@@ -1603,7 +1549,7 @@ func (m *monster) ThrowJavelin(g *game) bool {
 func (m *monster) Corrode(g *game) {
 	count := 0
 	for i, _ := range g.Player.Magaras {
-		n := RandInt(2)
+		n := g.RandInt(2)
 		g.Player.Magaras[i].Charges -= n
 		if g.Player.Magaras[i].Charges < 0 {
 			g.Player.Magaras[i].Charges = 0
@@ -1625,7 +1571,7 @@ func (m *monster) ThrowAcid(g *game) bool {
 	dmg := DmgNormal
 	noise := g.HitNoise(false) // no clang with acid projectiles
 	g.Printf("%s throws acid at you (%d dmg).", m.Kind.Definite(true), dmg)
-	g.ui.MonsterProjectileAnimation(g.Ray(m.Pos), '*', ColorGreen)
+	g.ui.MonsterProjectileAnimation(g.Ray(m.Pos), '*', g.ui.ColorGreen)
 	g.MakeNoise(noise, g.Player.Pos)
 	m.InflictDamage(g, dmg, dmg)
 	m.Corrode(g)
@@ -1643,7 +1589,7 @@ func (m *monster) NixeAttraction(g *game) bool {
 	g.PrintfStyled("%s lures you to her.", logMonsterHit, m.Kind.Definite(true))
 	g.StoryPrintf("Lured by %s", m.Kind)
 	ray := g.Ray(m.Pos)
-	g.ui.MonsterProjectileAnimation(ray, '*', ColorCyan)
+	g.ui.MonsterProjectileAnimation(ray, '*', g.ui.ColorCyan)
 	if len(ray) > 1 {
 		// should always be the case
 		g.ui.TeleportAnimation(g.Player.Pos, ray[1], true)
@@ -1698,7 +1644,7 @@ func (m *monster) AbsorbMana(g *game) bool {
 	g.Player.MP -= 1
 	g.Printf("%s absorbs your mana.", m.Kind.Definite(true))
 	g.StoryPrintf("Mana absorbed by %s (MP: %d)", m.Kind, g.Player.MP)
-	m.ExhaustTime(g, 1+RandInt(2))
+	m.ExhaustTime(g, 1+g.RandInt(2))
 	g.RenewEvent(DurationTurn)
 	return true
 }
@@ -1717,15 +1663,7 @@ func (m *monster) Blink(g *game) {
 func (m *monster) MakeHunt(g *game) (noticed bool) {
 	if m.State != Hunting {
 		m.State = Hunting
-		g.Stats.NSpotted++
-		g.Stats.DSpotted[g.Depth]++
-		if !m.Alerted {
-			g.Stats.NUSpotted++
-			g.Stats.DUSpotted[g.Depth]++
-			if g.Stats.DUSpotted[g.Depth] >= 90*len(g.Monsters)/100 {
-				AchUnstealthy.Get(g)
-			}
-		}
+		g.Emit(playerSpotted{Mons: m, Unaware: !m.Alerted})
 		m.Alerted = true
 		noticed = true
 	}
@@ -1748,6 +1686,57 @@ func (m *monster) MakeWatchIfHurt(g *game) {
 	}
 }
 
+// NoticeTampering makes a wandering or watching monster investigate the
+// nearest changed cell in its view, if its kind cares about such changes.
+// Each monster remembers the changes it noticed, and shares them with the
+// band members it gathers.
+func (m *monster) NoticeTampering(g *game) {
+	if !m.Kind.NoticesTampering() || m.State != Wandering && m.State != Watching {
+		return
+	}
+	target := InvalidPos
+	for pos, tp := range g.Tampered {
+		if !g.Tampering(pos, tp) {
+			// restored, for example a relit fire or a closed door
+			delete(g.Tampered, pos)
+			continue
+		}
+		if !m.LOS[pos] || !m.Dir.InViewCone(m.Pos, pos) {
+			continue
+		}
+		if turn, ok := m.Noticed[pos]; ok && turn >= tp.Turn {
+			continue
+		}
+		if target == InvalidPos || pos.Distance(m.Pos) < target.Distance(m.Pos) ||
+			pos.Distance(m.Pos) == target.Distance(m.Pos) && pos.idx() < target.idx() {
+			target = pos
+		}
+	}
+	if target == InvalidPos {
+		return
+	}
+	if g.Player.Sees(m.Pos) {
+		g.Printf("%s looks suspicious.", m.Kind.Definite(true))
+	}
+	m.MakeWanderAt(target)
+	m.GatherBand(g)
+	turn := g.Tampered[target].Turn
+	for _, mons := range g.Monsters {
+		if mons.Band == m.Band && mons.Target == target {
+			mons.RememberTampering(target, turn)
+		}
+	}
+}
+
+// RememberTampering records that the monster knows about the change of the
+// cell at the given turn.
+func (m *monster) RememberTampering(pos position, turn int) {
+	if m.Noticed == nil {
+		m.Noticed = map[position]int{}
+	}
+	m.Noticed[pos] = turn
+}
+
 func (m *monster) MakeAware(g *game) {
 	if m.Peaceful(g) || m.Status(MonsSatiated) {
 		if m.State == Resting && m.Pos.Distance(g.Player.Pos) == 1 {
@@ -1777,7 +1766,7 @@ func (m *monster) GatherBand(g *game) {
 		return
 	}
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{m.Pos}, 4)
+	nm := g.Dijkstra(dij, []position{m.Pos}, 4)
 	for _, mons := range g.Monsters {
 		if mons.Band == m.Band {
 			if mons.State == Hunting && m.State != Hunting {
@@ -0,0 +1,9 @@
+// +build !ansi !serve
+
+package main
+
+import "errors"
+
+func Serve(file string) error {
+	return errors.New("serve mode is only available with the ansi backend and the serve tag (build with --tags 'ansi serve')")
+}
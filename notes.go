@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+func (g *game) SetNote(pos position, text string) {
+	if text == "" {
+		if _, ok := g.Notes[pos]; ok {
+			delete(g.Notes, pos)
+			g.Print("You remove the note.")
+		}
+		return
+	}
+	if g.Notes == nil {
+		g.Notes = map[position]string{}
+	}
+	g.Notes[pos] = text
+	g.Printf("You write a note: “%s”.", text)
+}
+
+// SortedNotes returns the positions of map notes, nearest to the given
+// position first.
+func (g *game) SortedNotes(to position) []position {
+	notes := []position{}
+	for pos := range g.Notes {
+		notes = append(notes, pos)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		di, dj := notes[i].Distance(to), notes[j].Distance(to)
+		if di != dj {
+			return di < dj
+		}
+		return notes[i].idx() < notes[j].idx()
+	})
+	return notes
+}
+
+func (g *game) DumpNotes() string {
+	buf := &bytes.Buffer{}
+	fmt.Fprint(buf, "Map notes:\n")
+	for _, pos := range g.SortedNotes(g.Player.Pos) {
+		fmt.Fprintf(buf, "- (%d,%d): %s\n", pos.X, pos.Y, g.Notes[pos])
+	}
+	return buf.String()
+}
@@ -26,6 +26,7 @@ const (
 	NormalStair stair = iota
 	WinStair
 	BlockedStair
+	UpStair
 )
 
 func (st stair) String() (desc string) {
@@ -36,6 +37,8 @@ func (st stair) String() (desc string) {
 		desc = "monolith portal"
 	case BlockedStair:
 		desc = "sealed stairs"
+	case UpStair:
+		desc = "up stairs"
 	}
 	return desc
 }
@@ -55,12 +58,23 @@ func (st stair) ShortDesc(g *game) (desc string) {
 		desc = "a monolith portal"
 	case BlockedStair:
 		desc = "blocked " + NormalStair.ShortDesc(g)
+	case UpStair:
+		desc = "stairs upwards"
 	}
 	return desc
 }
 
-const NormalStairDesc = "Stairs lead to the next level of Dayoriah Clan's domain in Hareka's Underground. You will not be able to come back, because an oric barrier seals the stairs when they are traversed by intruders. The upside of this is that ennemies cannot follow you either."
-const DeepStairDesc = "Those very deep stairs lead to the next level of Dayoriah Clan's domain in Hareka's Underground. You will not be able to come back, because an oric barrier seals the stairs when they are traversed by intruders. The upside of this is that ennemies cannot follow you either."
+const NormalStairDesc = "Stairs lead to the next level of Dayoriah Clan's domain in Hareka's Underground."
+const DeepStairDesc = "Those very deep stairs lead to the next level of Dayoriah Clan's domain in Hareka's Underground."
+
+// StairBarrierDesc explains what happens behind the player when going
+// downstairs, which depends on whether levels can be revisited.
+func (g *game) StairBarrierDesc() string {
+	if g.Params.Plan.Revisits {
+		return " An oric barrier prevents ennemies from following you through the stairs, but you will be able to come back by the stairs upwards you arrive at."
+	}
+	return " You will not be able to come back, because an oric barrier seals the stairs when they are traversed by intruders. The upside of this is that ennemies cannot follow you either."
+}
 
 func (st stair) Desc(g *game) (desc string) {
 	switch st {
@@ -70,13 +84,15 @@ func (st stair) Desc(g *game) (desc string) {
 			desc += " If you're courageous enough, you may skip this portal and continue going deeper in the dungeon, to find Marevor's magara, finishing Shaedra's failed mission."
 		}
 	case NormalStair:
-		desc = NormalStairDesc
+		desc = NormalStairDesc + g.StairBarrierDesc()
 		if g.Depth == WinDepth {
-			desc = DeepStairDesc
+			desc = DeepStairDesc + g.StairBarrierDesc()
 			desc += " You may want to take those after freeing Shaedra from her cell."
 		}
 	case BlockedStair:
-		desc = "Stairs lead to the next level of the Dayoriah Clan's domain in Hareka's Underground. These are sealed by an oric magical barrier that you have to disable by activating a corresponding seal stone. You will not be able to come back, because an oric barrier seals the stairs again when they are traversed by intruders. The upside of this is that ennemies cannot follow you either."
+		desc = "Stairs lead to the next level of the Dayoriah Clan's domain in Hareka's Underground. These are sealed by an oric magical barrier that you have to disable by activating a corresponding seal stone." + g.StairBarrierDesc()
+	case UpStair:
+		desc = "Stairs lead back to the previous level of Dayoriah Clan's domain. Monsters there will not have forgotten you, but they may have stopped looking for you."
 	}
 	return desc
 }
@@ -85,15 +101,18 @@ func (st stair) Style(g *game) (r rune, fg uicolor) {
 	r = '>'
 	switch st {
 	case WinStair:
-		fg = ColorFgMagicPlace
+		fg = g.Palette().ColorFgMagicPlace
 		r = 'Δ'
 	case NormalStair:
-		fg = ColorFgPlace
+		fg = g.Palette().ColorFgPlace
 		if g.Depth == WinDepth {
-			fg = ColorViolet
+			fg = g.Palette().ColorViolet
 		}
 	case BlockedStair:
-		fg = ColorFgMagicPlace
+		fg = g.Palette().ColorFgMagicPlace
+	case UpStair:
+		fg = g.Palette().ColorFgPlace
+		r = '<'
 	}
 	return r, fg
 }
@@ -174,15 +193,15 @@ func (stn stone) Style(g *game) (r rune, fg uicolor) {
 	r = '∩'
 	switch stn {
 	case InertStone:
-		fg = ColorFgPlace
+		fg = g.Palette().ColorFgPlace
 	case SealStone:
-		fg = ColorFgPlayer
+		fg = g.Palette().ColorFgPlayer
 	case MappingStone, SensingStone:
-		fg = ColorViolet
+		fg = g.Palette().ColorViolet
 	case BarrelStone:
-		fg = ColorFgObject
+		fg = g.Palette().ColorFgObject
 	default:
-		fg = ColorFgMagicPlace
+		fg = g.Palette().ColorFgMagicPlace
 	}
 	return r, fg
 }
@@ -191,6 +210,7 @@ func (g *game) UseStone(pos position) {
 	g.StoryPrintf("Activated %s", g.Objects.Stones[pos])
 	g.Objects.Stones[pos] = InertStone
 	g.Stats.UsedStones++
+	g.Stats.DUsedStones[g.Depth]++
 	g.Print("The stone becomes inert.")
 }
 
@@ -199,6 +219,12 @@ func (g *game) ActivateStone() (err error) {
 	if !ok {
 		return errors.New("No stone to activate here.")
 	}
+	if stn != InertStone {
+		err = g.ConfirmConduct(ConductNoStones)
+		if err != nil {
+			return err
+		}
+	}
 	oppos := g.Player.Pos
 	switch stn {
 	case InertStone:
@@ -241,7 +267,7 @@ const (
 func (g *game) ActivateQueenStone() {
 	g.MakeNoise(QueenStoneNoise, g.Player.Pos)
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{g.Player.Pos}, QueenStoneDistance)
+	nm := g.Dijkstra(dij, []position{g.Player.Pos}, QueenStoneDistance)
 	targets := []*monster{}
 	for _, m := range g.Monsters {
 		if !m.Exists() {
@@ -285,7 +311,7 @@ func (g *game) ActivateNightStone() error {
 		g.Printf("%s falls asleep.", mons.Kind.Definite(true))
 		mons.State = Resting
 		mons.Dir = NoDir
-		mons.ExhaustTime(g, 4+RandInt(2))
+		mons.ExhaustTime(g, 4+g.RandInt(2))
 	}
 	return nil
 }
@@ -337,7 +363,7 @@ func (g *game) TeleportToBarrel() {
 	for pos := range g.Objects.Barrels {
 		barrels = append(barrels, pos)
 	}
-	pos := barrels[RandInt(len(barrels))]
+	pos := barrels[g.RandInt(len(barrels))]
 	opos := g.Player.Pos
 	g.Print("You teleport away.")
 	g.ui.TeleportAnimation(opos, pos, true)
@@ -346,7 +372,7 @@ func (g *game) TeleportToBarrel() {
 
 func (g *game) MagicMapping(maxdist int) error {
 	dp := &mappingPath{game: g}
-	nm := Dijkstra(dp, []position{g.Player.Pos}, maxdist)
+	nm := g.Dijkstra(dp, []position{g.Player.Pos}, maxdist)
 	cdists := make(map[int][]int)
 	nm.iter(g.Player.Pos, func(n *node) {
 		pos := n.Pos
@@ -458,9 +484,9 @@ func (sc scroll) Desc(g *game) (desc string) {
 
 func (sc scroll) Style(g *game) (r rune, fg uicolor) {
 	r = '?'
-	fg = ColorFgMagicPlace
+	fg = g.Palette().ColorFgMagicPlace
 	if sc == ScrollLore {
-		fg = ColorViolet
+		fg = g.Palette().ColorViolet
 	}
 	return r, fg
 }
@@ -508,10 +534,10 @@ func (st story) ShortDesc(g *game, pos position) (desc string) {
 }
 
 func (st story) Style(g *game) (r rune, fg uicolor) {
-	fg = ColorFgPlayer
+	fg = g.Palette().ColorFgPlayer
 	switch st {
 	case NoStory:
-		fg = ColorFgLOS
+		fg = g.Palette().ColorFgLOS
 		r = '.'
 	case StoryShaedra:
 		r = 'S'
@@ -521,7 +547,7 @@ func (st story) Style(g *game) (r rune, fg uicolor) {
 		r = '='
 	case StoryArtifactSealed:
 		r = '='
-		fg = ColorFgMagicPlace
+		fg = g.Palette().ColorFgMagicPlace
 	}
 	return r, fg
 }
@@ -640,7 +666,7 @@ func (it item) Desc(g *game) (desc string) {
 }
 
 func (it item) Style(g *game) (r rune, fg uicolor) {
-	fg = ColorFgObject
+	fg = g.Palette().ColorFgObject
 	if it.IsAmulet() {
 		r = '='
 	} else if it.IsCloak() {
@@ -694,7 +720,7 @@ func (g *game) RandomCloak() (it item) {
 		CloakConversion}
 loop:
 	for {
-		it = cloaks[RandInt(len(cloaks))]
+		it = cloaks[g.RandInt(len(cloaks))]
 		for _, cl := range g.GeneratedCloaks {
 			if cl == it {
 				continue loop
@@ -713,7 +739,7 @@ func (g *game) RandomAmulet() (it item) {
 		AmuletObstruction}
 loop:
 	for {
-		it = amulets[RandInt(len(amulets))]
+		it = amulets[g.RandInt(len(amulets))]
 		for _, cl := range g.GeneratedAmulets {
 			if cl == it {
 				continue loop
@@ -760,13 +786,29 @@ func (p potion) Style(g *game) (r rune, fg uicolor) {
 	r = '!'
 	switch p {
 	case HealthPotion:
-		fg = ColorFgHPok
+		fg = g.Palette().ColorFgHPok
 	case MagicPotion:
-		fg = ColorFgMPok
+		fg = g.Palette().ColorFgMPok
 	}
 	return r, fg
 }
 
+// PotionDrinkable reports whether the player would drink the potion at pos
+// when standing over it.
+func (g *game) PotionDrinkable(pos position) bool {
+	p, ok := g.Objects.Potions[pos]
+	if !ok {
+		return false
+	}
+	switch p {
+	case HealthPotion:
+		return g.Player.HP < g.Player.HPMax()
+	case MagicPotion:
+		return g.Player.MP < g.Player.MPMax()
+	}
+	return false
+}
+
 func (g *game) DrinkPotion(pos position) {
 	p, ok := g.Objects.Potions[pos]
 	if !ok {
@@ -789,6 +831,7 @@ func (g *game) DrinkPotion(pos position) {
 		g.Player.MP++
 		g.StoryPrintf("Drank %s (MP: %d).", p, g.Player.MP)
 	}
+	g.Stats.Potions++
 	g.Printf("You drink %s.", p.ShortDesc(g))
 	g.Dungeon.SetCell(pos, GroundCell)
 	delete(g.Objects.Potions, pos)
@@ -109,6 +109,7 @@ type playerPath struct {
 	game      *game
 	neighbors [8]position
 	goal      position
+	costs     []int
 }
 
 func (pp *playerPath) Neighbors(pos position) []position {
@@ -135,6 +136,9 @@ func (pp *playerPath) Cost(from, to position) int {
 	if !pp.game.ExclusionsMap[from] && pp.game.ExclusionsMap[to] {
 		return unreachable
 	}
+	if pp.costs != nil {
+		return pp.costs[to.idx()]
+	}
 	return 1
 }
 
@@ -153,7 +157,7 @@ func (jp *jumpPath) Neighbors(pos position) []position {
 		return jp.game.PlayerCanPass(npos)
 	}
 	nb = pos.CardinalNeighbors(nb, keep)
-	nb = ShufflePos(nb)
+	nb = ShufflePos(jp.game, nb)
 	return nb
 }
 
@@ -183,9 +187,26 @@ func (fp *noisePath) Cost(from, to position) int {
 	return 1
 }
 
+// soundPath is used for noise propagation: noise goes through any cell,
+// but some materials muffle it more than others.
+type soundPath struct {
+	game      *game
+	neighbors [4]position
+}
+
+func (sp *soundPath) Neighbors(pos position) []position {
+	nb := sp.neighbors[:0]
+	return pos.CardinalNeighbors(nb, func(npos position) bool { return npos.valid() })
+}
+
+func (sp *soundPath) Cost(from, to position) int {
+	return sp.game.Dungeon.Cell(to).T.SoundCost()
+}
+
 type autoexplorePath struct {
 	game      *game
 	neighbors [8]position
+	costs     []int
 }
 
 func (ap *autoexplorePath) Neighbors(pos position) []position {
@@ -208,6 +229,11 @@ func (ap *autoexplorePath) Neighbors(pos position) []position {
 }
 
 func (ap *autoexplorePath) Cost(from, to position) int {
+	if ap.costs != nil {
+		// the map is built from the unexplored cells toward the player, so
+		// the player would be moving into from.
+		return ap.costs[from.idx()]
+	}
 	return 1
 }
 
@@ -217,9 +243,9 @@ type monPath struct {
 	neighbors [8]position
 }
 
-func ShufflePos(ps []position) []position {
+func ShufflePos(g *game, ps []position) []position {
 	for i := 0; i < len(ps); i++ {
-		j := i + RandInt(len(ps)-i)
+		j := i + g.RandInt(len(ps)-i)
 		ps[i], ps[j] = ps[j], ps[i]
 	}
 	return ps
@@ -232,7 +258,7 @@ func (mp *monPath) Neighbors(pos position) []position {
 	}
 	ret := pos.CardinalNeighbors(nb, keep)
 	// shuffle so that monster movement is not unnaturally predictable
-	ret = ShufflePos(ret)
+	ret = ShufflePos(mp.game, ret)
 	return ret
 }
 
@@ -269,7 +295,7 @@ func (mp *monPath) Estimation(from, to position) int {
 
 func (m *monster) APath(g *game, from, to position) []position {
 	mp := &monPath{game: g, monster: m}
-	path, _, found := AstarPath(mp, from, to)
+	path, _, found := g.AstarPath(mp, from, to)
 	if !found {
 		return nil
 	}
@@ -278,18 +304,66 @@ func (m *monster) APath(g *game, from, to position) []position {
 
 func (g *game) PlayerPath(from, to position) []position {
 	pp := &playerPath{game: g, goal: to}
-	path, _, found := AstarPath(pp, from, to)
+	if g.config.StealthPaths {
+		pp.costs = g.StealthCosts()
+	}
+	path, _, found := g.AstarPath(pp, from, to)
 	if !found {
 		return nil
 	}
 	return path
 }
 
+const (
+	stealthCostHidden      = 1
+	stealthCostNormal      = 2
+	stealthCostLighted     = 3
+	stealthCostMonsterLOS  = 8
+	stealthCostNearMonster = 4
+)
+
+// StealthCosts returns movement costs for each cell of the map, favoring
+// dark and hidden cells, and avoiding known monster cones of view and
+// surroundings of last known monster positions. Terrain and light only count
+// for explored cells, so that the costs do not reveal unknown parts of the
+// map.
+func (g *game) StealthCosts() []int {
+	costs := make([]int, DungeonNCells)
+	for i := range costs {
+		pos := idxtopos(i)
+		c := g.Dungeon.Cell(pos)
+		c.T = g.RememberedTerrain(pos)
+		switch {
+		case c.Explored && c.T == FoliageCell:
+			costs[i] = stealthCostHidden
+		default:
+			costs[i] = stealthCostNormal
+		}
+		if c.Explored && g.Illuminated[i] && c.IsIlluminable() {
+			costs[i] += stealthCostLighted
+		}
+		if g.MonsterLOS[pos] {
+			costs[i] += stealthCostMonsterLOS
+		}
+	}
+	for pos := range g.LastMonsterKnownAt {
+		for y := pos.Y - 2; y <= pos.Y+2; y++ {
+			for x := pos.X - 2; x <= pos.X+2; x++ {
+				npos := position{x, y}
+				if npos.valid() {
+					costs[npos.idx()] += stealthCostNearMonster
+				}
+			}
+		}
+	}
+	return costs
+}
+
 func (g *game) SortedNearestTo(cells []position, to position) []position {
 	ps := posSlice{}
 	for _, pos := range cells {
 		pp := &dungeonPath{dungeon: g.Dungeon, wcost: unreachable}
-		_, cost, found := AstarPath(pp, pos, to)
+		_, cost, found := g.AstarPath(pp, pos, to)
 		if found {
 			ps = append(ps, posCost{pos, cost})
 		}
@@ -151,10 +151,13 @@ func (g *game) Rest() error {
 	if g.Player.Bananas <= 0 {
 		return errors.New("You cannot sleep without eating for dinner a banana first.")
 	}
+	if err := g.ConfirmConduct(ConductNoRest); err != nil {
+		return err
+	}
 	g.ui.DrawMessage("Resting...")
 	g.RenewEvent(DurationTurn)
 	g.Resting = true
-	g.RestingTurns = RandInt(5) // you do not wake up when you want
+	g.RestingTurns = g.RandInt(5) // you do not wake up when you want
 	g.Player.Bananas--
 	return nil
 }
@@ -322,6 +325,11 @@ func (g *game) PlayerBump(pos position) error {
 		if c.T == ChasmCell && !g.Player.HasStatus(StatusLevitation) {
 			return g.AbyssJump()
 		}
+		if c.T == PotionCell && g.PotionDrinkable(pos) {
+			if err := g.ConfirmConduct(ConductNoPotions); err != nil {
+				return err
+			}
+		}
 		if c.T == BarrelCell {
 			g.Print("You hide yourself inside the barrel.")
 		} else if c.T == TableCell {
@@ -332,21 +340,14 @@ func (g *game) PlayerBump(pos position) error {
 			g.Print("You crawl under the wall.")
 		}
 		if c.T.IsDiggable() && c.T != HoledWallCell {
+			g.Tamper(pos)
 			g.Dungeon.SetCell(pos, RubbleCell)
 			g.MakeNoise(WallNoise, pos)
 			g.Print(g.CrackSound())
 			g.Fog(pos, 1)
 			g.Stats.Digs++
 			g.Stats.DestructionUse++
-			if g.Stats.DestructionUse == 20 {
-				AchDestructorNovice.Get(g)
-			}
-			if g.Stats.DestructionUse == 40 {
-				AchDestructorInitiate.Get(g)
-			}
-			if g.Stats.DestructionUse == 60 {
-				AchDestructorMaster.Get(g)
-			}
+			g.CheckAchievements(CounterHook)
 		}
 		if g.Player.Inventory.Body == CloakSmoke {
 			_, ok := g.Clouds[g.Player.Pos]
@@ -375,13 +376,13 @@ func (g *game) PlayerBump(pos position) error {
 
 func (g *game) SwiftFog() {
 	dij := &noisePath{game: g}
-	nm := Dijkstra(dij, []position{g.Player.Pos}, 2)
+	nm := g.Dijkstra(dij, []position{g.Player.Pos}, 2)
 	nm.iter(g.Player.Pos, func(n *node) {
 		pos := n.Pos
 		_, ok := g.Clouds[pos]
 		if !ok && g.Dungeon.Cell(pos).AllowsFog() {
 			g.Clouds[pos] = CloudFog
-			g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationFog + RandInt(DurationFog/2), EAction: CloudEnd, Pos: pos})
+			g.PushEvent(&posEvent{ERank: g.Ev.Rank() + DurationFog + g.RandInt(DurationFog/2), EAction: CloudEnd, Pos: pos})
 		}
 	})
 	g.PutStatus(StatusSwift, DurationShortSwiftness)
@@ -410,10 +411,16 @@ func (g *game) PlacePlayerAt(pos position) {
 	case SSW, SSE:
 		g.Player.Dir = S
 	}
+	if g.Dungeon.Cell(g.Player.Pos).T == DoorCell {
+		g.Tamper(g.Player.Pos)
+	}
 	g.Player.Pos = pos
-	if g.Dungeon.Cell(pos).T == QueenRockCell && !g.Player.HasStatus(StatusLevitation) {
-		g.MakeNoise(QueenRockFootstepNoise, pos)
-		g.Print("Tap-tap.")
+	if g.Dungeon.Cell(pos).T == DoorCell {
+		g.Tamper(pos)
+	}
+	if noise, sound := g.Dungeon.Cell(pos).T.Footsteps(); noise > 0 && !g.Player.HasStatus(StatusLevitation) {
+		g.MakeNoise(noise, pos)
+		g.Print(sound)
 	}
 	g.CollectGround()
 	g.ComputeLOS()
@@ -430,12 +437,11 @@ func (g *game) EnterLignification() {
 }
 
 func (g *game) ExtinguishFire() error {
+	g.Tamper(g.Player.Pos)
 	g.Dungeon.SetCell(g.Player.Pos, ExtinguishedLightCell)
 	g.Objects.Lights[g.Player.Pos] = false
 	g.Stats.Extinguishments++
-	if g.Stats.Extinguishments >= 15 {
-		AchExtinguisher.Get(g)
-	}
+	g.CheckAchievements(CounterHook)
 	g.Print("You extinguish the fire.")
 	g.Ev.Renew(g, DurationTurn)
 	return nil
@@ -447,7 +453,7 @@ func (g *game) PutStatus(st status, duration int) bool {
 	}
 	g.Player.Statuses[st] += duration
 	g.PushEvent(&simpleEvent{ERank: g.Ev.Rank() + DurationStatusStep, EAction: StatusEndActions[st]})
-	g.Stats.Statuses[st]++
+	g.Emit(statusGained{Status: st, Duration: duration})
 	if st.Good() {
 		g.Player.Expire[st] = g.Ev.Rank() + duration
 	}
@@ -459,7 +465,7 @@ func (g *game) PutFakeStatus(st status, duration int) bool {
 		return false
 	}
 	g.Player.Statuses[st] += duration
-	g.Stats.Statuses[st]++
+	g.Emit(statusGained{Status: st, Duration: duration})
 	if st.Good() {
 		g.Player.Expire[st] = g.Ev.Rank() + duration
 	}
@@ -476,6 +482,36 @@ func (g *game) UpdateKnowledge(pos position, t terrain) {
 	}
 }
 
+// tampering is a change of a cell made by the player.
+type tampering struct {
+	T    terrain // terrain before the first change
+	Turn int     // turn of the last change
+}
+
+// Tamper records the terrain of a cell before the player changes it, or
+// opens a door, so that monsters may notice the change later.
+func (g *game) Tamper(pos position) {
+	if g.Tampered == nil {
+		g.Tampered = map[position]tampering{}
+	}
+	tp, ok := g.Tampered[pos]
+	if !ok {
+		tp.T = g.Dungeon.Cell(pos).T
+	}
+	tp.Turn = g.Turn
+	g.Tampered[pos] = tp
+}
+
+// Tampering reports whether a change made by the player can still be seen:
+// the terrain is not restored, or the door is open or still swinging.
+func (g *game) Tampering(pos position, tp tampering) bool {
+	t := g.Dungeon.Cell(pos).T
+	if t != tp.T {
+		return true
+	}
+	return t == DoorCell && (pos == g.Player.Pos || g.Turn-tp.Turn <= DurationDoorSwing)
+}
+
 func (g *game) PlayerCanPass(pos position) bool {
 	if !pos.valid() {
 		return false
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 )
 
 type position struct {
@@ -256,35 +257,35 @@ func (pos position) Parents(from position, p []position) []position {
 	return p
 }
 
-func (pos position) RandomNeighbor(diag bool) position {
+func (pos position) RandomNeighbor(g *game, diag bool) position {
 	if diag {
-		return pos.RandomNeighborDiagonals()
+		return pos.RandomNeighborDiagonals(g)
 	}
-	return pos.RandomNeighborCardinal()
+	return pos.RandomNeighborCardinal(g)
 }
 
-func (pos position) RandomNeighborDiagonals() position {
+func (pos position) RandomNeighborDiagonals(g *game) position {
 	neighbors := [8]position{pos.E(), pos.W(), pos.N(), pos.S(), pos.NE(), pos.NW(), pos.SE(), pos.SW()}
 	var r int
-	switch RandInt(8) {
+	switch g.RandInt(8) {
 	case 0:
-		r = RandInt(len(neighbors[0:4]))
+		r = g.RandInt(len(neighbors[0:4]))
 	case 1:
-		r = RandInt(len(neighbors[0:2]))
+		r = g.RandInt(len(neighbors[0:2]))
 	default:
-		r = RandInt(len(neighbors[4:]))
+		r = g.RandInt(len(neighbors[4:]))
 	}
 	return neighbors[r]
 }
 
-func (pos position) RandomNeighborCardinal() position {
+func (pos position) RandomNeighborCardinal(g *game) position {
 	neighbors := [4]position{pos.E(), pos.W(), pos.N(), pos.S()}
 	var r int
-	switch RandInt(4) {
+	switch g.RandInt(4) {
 	case 0, 1:
-		r = RandInt(len(neighbors[0:2]))
+		r = g.RandInt(len(neighbors[0:2]))
 	default:
-		r = RandInt(len(neighbors))
+		r = g.RandInt(len(neighbors))
 	}
 	return neighbors[r]
 }
@@ -297,6 +298,12 @@ func (pos position) idx() int {
 	return pos.Y*DungeonWidth + pos.X
 }
 
+// SortPositions sorts positions in dungeon order, so that choices made from
+// positions collected in a map do not depend on the iteration order.
+func SortPositions(ps []position) {
+	sort.Slice(ps, func(i, j int) bool { return ps[i].idx() < ps[j].idx() })
+}
+
 func (pos position) valid() bool {
 	return pos.Y >= 0 && pos.Y < DungeonHeight && pos.X >= 0 && pos.X < DungeonWidth
 }
@@ -380,7 +387,7 @@ func (dir direction) InViewCone(from, to position) bool {
 
 var alternateDirs = []direction{E, NE, N, NW, W, SW, S, SE}
 
-func (dir direction) Left() (d direction) {
+func (dir direction) Left(g *game) (d direction) {
 	switch dir {
 	case E:
 		d = NE
@@ -399,12 +406,12 @@ func (dir direction) Left() (d direction) {
 	case SE:
 		d = E
 	default:
-		d = alternateDirs[RandInt(len(alternateDirs))]
+		d = alternateDirs[g.RandInt(len(alternateDirs))]
 	}
 	return d
 }
 
-func (dir direction) Right() (d direction) {
+func (dir direction) Right(g *game) (d direction) {
 	switch dir {
 	case E:
 		d = SE
@@ -423,7 +430,7 @@ func (dir direction) Right() (d direction) {
 	case SE:
 		d = S
 	default:
-		d = alternateDirs[RandInt(len(alternateDirs))]
+		d = alternateDirs[g.RandInt(len(alternateDirs))]
 	}
 	return d
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Practice mode: the whole game state is checkpointed at the start of each
+// level and regularly during play, and the player can rewind to a checkpoint
+// on demand or after dying. As in wizard mode, the game cannot be won.
+
+const (
+	CheckpointTurns = 200 // turns between two checkpoints on the same level
+	MaxCheckpoints  = 10  // older checkpoints are forgotten
+)
+
+// checkpoint is a saved game state of practice mode.
+type checkpoint struct {
+	Turn  int
+	Depth int
+	Data  []byte
+}
+
+func (cp checkpoint) String() string {
+	return fmt.Sprintf("turn %d, depth %d", cp.Turn, cp.Depth)
+}
+
+// EnterPracticeMode starts practice mode. The first checkpoint is made at the
+// next player turn.
+func (g *game) EnterPracticeMode() {
+	g.Practice = true
+	g.PrintStyled("You are now in practice mode and cannot obtain winner status.", logSpecial)
+	g.StoryPrint("Entered practice mode.")
+}
+
+// CheckpointDue reports whether a checkpoint should be made before the next
+// event: the event is a player turn either on a new level or long enough
+// after the last checkpoint.
+func (g *game) CheckpointDue() bool {
+	if g.Events.Len() == 0 {
+		return false
+	}
+	ev, ok := (*g.Events)[0].Event.(*simpleEvent)
+	if !ok || ev.EAction != PlayerTurn {
+		return false
+	}
+	if len(g.Checkpoints) == 0 {
+		return true
+	}
+	last := g.Checkpoints[len(g.Checkpoints)-1]
+	return last.Depth != g.Depth || ev.Rank() >= last.Turn+CheckpointTurns
+}
+
+// Checkpoint saves the current game state as a new checkpoint. It should be
+// called between events, so that the next player turn is in the saved event
+// queue.
+func (g *game) Checkpoint() error {
+	cps := g.Checkpoints
+	g.Checkpoints = nil
+	data, err := g.GameSave()
+	g.Checkpoints = cps
+	if err != nil {
+		return err
+	}
+	turn := g.Turn
+	if g.Events.Len() > 0 {
+		turn = (*g.Events)[0].Event.Rank()
+	}
+	g.Checkpoints = append(g.Checkpoints, checkpoint{Turn: turn, Depth: g.Depth, Data: data})
+	if len(g.Checkpoints) > MaxCheckpoints {
+		g.Checkpoints = g.Checkpoints[len(g.Checkpoints)-MaxCheckpoints:]
+	}
+	return nil
+}
+
+// Rewind brings the game back to the state of the checkpoint with the given
+// index. Later checkpoints are forgotten.
+func (g *game) Rewind(i int) error {
+	if i < 0 || i >= len(g.Checkpoints) {
+		return errors.New("No such checkpoint.")
+	}
+	cp := g.Checkpoints[i]
+	lg, err := g.DecodeGameSave(cp.Data)
+	if err != nil {
+		return err
+	}
+	// keep the unsaved state of the running game
+	lg.ui = g.ui
+	lg.config = g.config
+	lg.pathCache = g.pathCache
+	lg.dataDir = g.dataDir
+	lg.rng = g.rng
+	lg.observers = g.observers
+	lg.telemetry = g.telemetry
+	lg.Checkpoints = g.Checkpoints[:i+1]
+	lg.Rewinds = g.Rewinds + 1
+	*g = *lg
+	g.PrintfStyled("You rewind to the checkpoint of %s.", logSpecial, cp)
+	g.StoryPrintf("Rewound to %s", cp)
+	g.Save()
+	return nil
+}
+
+// Practice handles the practice mode key: it offers to enter practice mode,
+// or to rewind to a checkpoint. The rewind itself happens at the end of the
+// current turn.
+func (ui *gameui) Practice() error {
+	g := ui.g
+	if !g.Practice {
+		g.Print("Do you really want to enter practice mode (no return)? [y/N]")
+		ui.DrawDungeonView(NormalMode)
+		if !ui.PromptConfirmation() {
+			return errors.New(DoNothing)
+		}
+		g.EnterPracticeMode()
+		return nil
+	}
+	i := ui.CheckpointsMenu()
+	if i < 0 {
+		return errors.New(DoNothing)
+	}
+	g.rewind = i + 1
+	g.Ev.Renew(g, 0) // the turn is not spent if rewinding fails
+	return nil
+}
+
+// PracticeDeath offers to rewind to a checkpoint after dying in practice
+// mode. It reports whether the game goes on.
+func (ui *gameui) PracticeDeath() bool {
+	g := ui.g
+	g.PrintStyled("You die... Rewind to a checkpoint? [y/N]", logCritic)
+	ui.DrawDungeonView(NormalMode)
+	if !ui.PromptConfirmation() {
+		return false
+	}
+	i := ui.CheckpointsMenu()
+	if i < 0 {
+		return false
+	}
+	err := g.Rewind(i)
+	if err != nil {
+		g.PrintfStyled("Error rewinding: %v", logError, err)
+		return false
+	}
+	ui.DrawDungeonView(NormalMode)
+	return true
+}
+
+// CheckpointsMenu lets the player choose a checkpoint to rewind to, and
+// returns its index, or -1.
+func (ui *gameui) CheckpointsMenu() int {
+	g := ui.g
+	for {
+		ui.Clear()
+		ui.DrawColoredText(" Checkpoints ", 7, 2, ui.ColorYellow)
+		for i, cp := range g.Checkpoints {
+			ui.DrawText(fmt.Sprintf("- (%c) %s", 'a'+i, cp), 7, 4+i)
+		}
+		ui.DrawText("Press a letter to rewind to a checkpoint, or (x) to cancel.", 7, 5+len(g.Checkpoints))
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.interrupt || in.key == "x" || in.key == "X" {
+			return -1
+		}
+		for i := range g.Checkpoints {
+			if in.key == string('a'+rune(i)) || in.mouse && in.button == 0 && in.mouseY == 4+i {
+				return i
+			}
+		}
+	}
+}
@@ -12,7 +12,7 @@ func (ui *gameui) Replay() {
 	}
 	g.DrawLog = nil
 	rep := &replay{ui: ui, frames: dl, frame: 0}
-	if ColorBase03 == Color256Base03 {
+	if ui.ColorBase03 == Color256Base03 {
 		rep.color256 = true
 	}
 	rep.Run()
@@ -0,0 +1,74 @@
+package main
+
+// ScentDuration is the number of turns the scent of the player lasts on a
+// cell.
+const ScentDuration = 50
+
+// LayScent leaves the scent of the player on its cell. Water and fog do not
+// keep scents.
+func (g *game) LayScent() {
+	if g.Scent == nil {
+		g.Scent = make([]int, DungeonNCells)
+	}
+	pos := g.Player.Pos
+	if g.Dungeon.Cell(pos).T == WaterCell {
+		return
+	}
+	if cld, ok := g.Clouds[pos]; ok && cld == CloudFog {
+		return
+	}
+	g.Scent[pos.idx()] = g.Turn + ScentDuration
+}
+
+// ScentAt returns the strength of the scent of the player on a cell, from 0
+// (no scent) to ScentDuration (the player is just there). Fog clouds disperse
+// scent, whatever their origin.
+func (g *game) ScentAt(pos position) int {
+	if g.Scent == nil || !pos.valid() {
+		return 0
+	}
+	if cld, ok := g.Clouds[pos]; ok && cld == CloudFog {
+		return 0
+	}
+	s := g.Scent[pos.idx()] - g.Turn
+	if s < 0 {
+		return 0
+	}
+	return s
+}
+
+// scentPath is used by tracking monsters to follow scents, which do not go
+// across water.
+type scentPath struct {
+	game      *game
+	monster   *monster
+	neighbors [4]position
+}
+
+func (sp *scentPath) Neighbors(pos position) []position {
+	nb := sp.neighbors[:0]
+	keep := func(npos position) bool {
+		return sp.monster.CanPass(sp.game, npos) && sp.game.Dungeon.Cell(npos).T != WaterCell
+	}
+	return pos.CardinalNeighbors(nb, keep)
+}
+
+func (sp *scentPath) Cost(from, to position) int {
+	return 1
+}
+
+// SmellScent returns the cell with the freshest scent of the player the
+// monster can smell nearby, if it is fresher than the scent on its own cell.
+func (m *monster) SmellScent(g *game) (position, bool) {
+	sp := &scentPath{game: g, monster: m}
+	nm := g.Dijkstra(sp, []position{m.Pos}, DogFlairDist)
+	best := InvalidPos
+	strength := g.ScentAt(m.Pos)
+	nm.iter(m.Pos, func(n *node) {
+		if s := g.ScentAt(n.Pos); s > strength {
+			best = n.Pos
+			strength = s
+		}
+	})
+	return best, best != InvalidPos
+}
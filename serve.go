@@ -0,0 +1,229 @@
+// +build ansi,serve
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"path/filepath"
+	"regexp"
+	"runtime/debug"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+// serverConfig is the configuration of the SSH game server, read from a JSON
+// file like the following:
+//
+//	{
+//		"Address": ":2222",
+//		"HostKey": "/path/to/ssh_host_ed25519_key",
+//		"DataDir": "/var/games/harmonist",
+//		"Passwords": {"alice": "$2y$10$..."},
+//		"Keys": {"bob": ["ssh-ed25519 AAAA... bob@host"]}
+//	}
+//
+// Users log in with a password, stored as a bcrypt hash, or with one of their
+// public keys, written in authorized_keys format. Each user gets its own
+// subdirectory of DataDir for saves, configuration, dumps and replays.
+type serverConfig struct {
+	Address   string
+	HostKey   string
+	DataDir   string
+	Passwords map[string]string
+	Keys      map[string][]string
+}
+
+var validUserName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+func LoadServerConfig(file string) (*serverConfig, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &serverConfig{Address: ":2222"}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if cfg.HostKey == "" {
+		return nil, fmt.Errorf("%s: no host key", file)
+	}
+	if cfg.DataDir == "" {
+		cfg.DataDir = filepath.Join(XDGDataHome(), "harmonist", "users")
+	}
+	for user, hash := range cfg.Passwords {
+		if !validUserName.MatchString(user) || user == "." || user == ".." {
+			return nil, fmt.Errorf("%s: invalid user name: %q", file, user)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("%s: invalid password hash of %s: %v", file, user, err)
+		}
+	}
+	for user := range cfg.Keys {
+		if !validUserName.MatchString(user) || user == "." || user == ".." {
+			return nil, fmt.Errorf("%s: invalid user name: %q", file, user)
+		}
+	}
+	return cfg, nil
+}
+
+type server struct {
+	cfg     *serverConfig
+	ssh     *ssh.ServerConfig
+	keys    map[string]map[string]bool // user -> authorized key fingerprints
+	mu      sync.Mutex
+	playing map[string]bool
+}
+
+// Serve runs an SSH server in which each user plays its own game. If file is
+// empty, the server configuration is read from server.json in the data
+// directory.
+func Serve(file string) error {
+	if file == "" {
+		file = filepath.Join(XDGDataHome(), "harmonist", "server.json")
+	}
+	cfg, err := LoadServerConfig(file)
+	if err != nil {
+		return err
+	}
+	srv := &server{cfg: cfg, keys: map[string]map[string]bool{}, playing: map[string]bool{}}
+	for user, keys := range cfg.Keys {
+		srv.keys[user] = map[string]bool{}
+		for _, k := range keys {
+			pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k))
+			if err != nil {
+				return fmt.Errorf("key for user %s: %v", user, err)
+			}
+			srv.keys[user][ssh.FingerprintSHA256(pk)] = true
+		}
+	}
+	srv.ssh = &ssh.ServerConfig{
+		PasswordCallback:  srv.CheckPassword,
+		PublicKeyCallback: srv.CheckKey,
+	}
+	hostKey, err := ioutil.ReadFile(cfg.HostKey)
+	if err != nil {
+		return err
+	}
+	signer, err := ssh.ParsePrivateKey(hostKey)
+	if err != nil {
+		return fmt.Errorf("host key: %v", err)
+	}
+	srv.ssh.AddHostKey(signer)
+	ln, err := net.Listen("tcp", cfg.Address)
+	if err != nil {
+		return err
+	}
+	log.Printf("harmonist: serving on %s", ln.Addr())
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			log.Printf("harmonist: accept: %v", err)
+			continue
+		}
+		go srv.HandleConn(conn)
+	}
+}
+
+func (srv *server) CheckPassword(md ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+	hash, ok := srv.cfg.Passwords[md.User()]
+	if ok && bcrypt.CompareHashAndPassword([]byte(hash), password) == nil {
+		return nil, nil
+	}
+	return nil, errors.New("wrong user or password")
+}
+
+func (srv *server) CheckKey(md ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	if srv.keys[md.User()][ssh.FingerprintSHA256(key)] {
+		return nil, nil
+	}
+	return nil, errors.New("unknown public key")
+}
+
+func (srv *server) HandleConn(nconn net.Conn) {
+	conn, chans, reqs, err := ssh.NewServerConn(nconn, srv.ssh)
+	if err != nil {
+		log.Printf("harmonist: %s: handshake: %v", nconn.RemoteAddr(), err)
+		nconn.Close()
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+	for nch := range chans {
+		if nch.ChannelType() != "session" {
+			nch.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, requests, err := nch.Accept()
+		if err != nil {
+			log.Printf("harmonist: %s: %v", conn.User(), err)
+			continue
+		}
+		go srv.HandleSession(conn.User(), ch, requests)
+	}
+}
+
+func (srv *server) HandleSession(user string, ch ssh.Channel, requests <-chan *ssh.Request) {
+	started := false
+	for req := range requests {
+		switch req.Type {
+		case "pty-req", "env", "window-change":
+			req.Reply(true, nil)
+		case "shell":
+			req.Reply(!started, nil)
+			if !started {
+				started = true
+				go func() {
+					defer ch.Close()
+					srv.Play(user, ch)
+					ch.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				}()
+			}
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+// Play runs a game for the user on the SSH channel. A user can only play one
+// game at a time, as all its games share the same save.
+func (srv *server) Play(user string, ch ssh.Channel) {
+	srv.mu.Lock()
+	if srv.playing[user] {
+		srv.mu.Unlock()
+		fmt.Fprintf(ch, "You are already playing in another session.\r\n")
+		return
+	}
+	srv.playing[user] = true
+	srv.mu.Unlock()
+	defer func() {
+		srv.mu.Lock()
+		delete(srv.playing, user)
+		srv.mu.Unlock()
+	}()
+	defer func() {
+		// a crash only ends the session where it happened
+		if r := recover(); r != nil {
+			log.Printf("harmonist: %s: game crashed: %v\n%s", user, r, debug.Stack())
+			fmt.Fprintf(ch, "\r\nThe game crashed, sorry.\r\n")
+		}
+	}()
+	log.Printf("harmonist: %s: new session", user)
+	defer log.Printf("harmonist: %s: session ended", user)
+	g := &game{dataDir: filepath.Join(srv.cfg.DataDir, user)}
+	ui := NewStreamUI(g, ch, ch)
+	err := ui.Init()
+	if err != nil {
+		log.Printf("harmonist: %s: %v", user, err)
+		return
+	}
+	defer ui.Close()
+	ui.Play()
+}
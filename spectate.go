@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SpectatorQueue is the number of frames that can wait for a slow spectator
+// before it gets disconnected.
+const SpectatorQueue = 1000
+
+// spectators publishes the frames drawn by a game to the spectators connected
+// to a Unix socket or TCP address.
+type spectators struct {
+	ln       net.Listener
+	mu       sync.Mutex
+	screen   map[position]UICell
+	watchers map[chan drawFrame]net.Conn
+}
+
+// spectatorNetwork returns the network of a spectator address: addresses
+// containing a slash are Unix socket paths, others are TCP addresses.
+func spectatorNetwork(addr string) string {
+	if strings.Contains(addr, "/") {
+		return "unix"
+	}
+	return "tcp"
+}
+
+// Publish starts publishing the frames drawn by the interface on the given
+// address.
+func (ui *gameui) Publish(addr string) error {
+	network := spectatorNetwork(addr)
+	if network == "unix" {
+		fi, err := os.Stat(addr)
+		if err == nil && fi.Mode()&os.ModeSocket != 0 {
+			conn, err := net.Dial(network, addr)
+			if err == nil {
+				conn.Close()
+				return fmt.Errorf("a game is already published at %s", addr)
+			}
+			// stale socket of a previous game
+			os.Remove(addr)
+		}
+	}
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+	sp := &spectators{ln: ln, screen: map[position]UICell{}, watchers: map[chan drawFrame]net.Conn{}}
+	ui.spectators = sp
+	go sp.Accept()
+	return nil
+}
+
+func (sp *spectators) Accept() {
+	for {
+		conn, err := sp.ln.Accept()
+		if err != nil {
+			return
+		}
+		go sp.Serve(conn)
+	}
+}
+
+func (sp *spectators) Serve(conn net.Conn) {
+	defer conn.Close()
+	frames := make(chan drawFrame, SpectatorQueue)
+	sp.mu.Lock()
+	// the first frame brings the spectator up to date
+	df := drawFrame{}
+	for pos, c := range sp.screen {
+		df.Draws = append(df.Draws, cellDraw{Cell: c, X: pos.X, Y: pos.Y})
+	}
+	frames <- df
+	sp.watchers[frames] = conn
+	sp.mu.Unlock()
+	enc := gob.NewEncoder(conn)
+	for df := range frames {
+		err := enc.Encode(&df)
+		if err != nil {
+			break
+		}
+	}
+	sp.mu.Lock()
+	if _, ok := sp.watchers[frames]; ok {
+		delete(sp.watchers, frames)
+		close(frames)
+	}
+	sp.mu.Unlock()
+	for range frames {
+		// drain
+	}
+}
+
+// Send publishes a frame to every spectator. Spectators that cannot keep up
+// are dropped, so that they never slow down the game: closing their
+// connection also stops a write blocked on it.
+func (sp *spectators) Send(df drawFrame) {
+	if len(df.Draws) == 0 {
+		return
+	}
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	for _, dr := range df.Draws {
+		sp.screen[position{dr.X, dr.Y}] = dr.Cell
+	}
+	for frames, conn := range sp.watchers {
+		select {
+		case frames <- df:
+		default:
+			delete(sp.watchers, frames)
+			close(frames)
+			conn.Close()
+		}
+	}
+}
+
+func (sp *spectators) Close() {
+	sp.ln.Close()
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	for frames := range sp.watchers {
+		delete(sp.watchers, frames)
+		close(frames)
+	}
+}
+
+// Watch draws the frames of the game published at the given address, as they
+// come, until the game ends or the spectator quits.
+func (ui *gameui) Watch(addr string) error {
+	conn, err := net.Dial(spectatorNetwork(addr), addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	rep := &replay{ui: ui, undo: [][]cellDraw{}}
+	if ui.ColorBase03 == Color256Base03 {
+		rep.color256 = true
+	}
+	frames := make(chan drawFrame)
+	errs := make(chan error, 1)
+	quit := make(chan bool)
+	go func() {
+		dec := gob.NewDecoder(conn)
+		for {
+			var df drawFrame
+			err := dec.Decode(&df)
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case frames <- df:
+			case <-quit:
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			in := ui.PollEvent()
+			switch in.key {
+			case "Q", "q", "\x1b":
+				close(quit)
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case df := <-frames:
+			df.Draws = ui.VisibleDraws(df.Draws)
+			rep.frames = []drawFrame{df}
+			rep.frame = 0
+			rep.undo = rep.undo[:0]
+			rep.DrawFrame()
+		case err := <-errs:
+			if err == io.EOF {
+				// the game ended
+				return nil
+			}
+			return err
+		case <-quit:
+			return nil
+		}
+	}
+}
+
+// VisibleDraws returns the draws that fit in the interface, as the watched
+// game may use a bigger layout.
+func (ui *gameui) VisibleDraws(draws []cellDraw) []cellDraw {
+	visible := draws[:0]
+	for _, dr := range draws {
+		if dr.X >= 0 && dr.X < ui.UIWidth && dr.Y >= 0 && dr.Y < ui.UIHeight {
+			visible = append(visible, dr)
+		}
+	}
+	return visible
+}
@@ -3,6 +3,7 @@ package main
 type stats struct {
 	Story             []string
 	Killed            int
+	KilledBy          string
 	KilledMons        map[monsterKind]int
 	Moves             int
 	Waits             int
@@ -11,28 +12,30 @@ type stats struct {
 	ReceivedHits      int
 	Dodges            int
 	MagarasUsed       int
-	DMagaraUses       [MaxDepth + 1]int
+	DMagaraUses       []int
 	UsedStones        int
+	DUsedStones       []int
 	UsedMagaras       map[magaraKind]int
 	Damage            int
-	DDamage           [MaxDepth + 1]int
-	DExplPerc         [MaxDepth + 1]int
-	DSleepingPerc     [MaxDepth + 1]int
-	DKilledPerc       [MaxDepth + 1]int
+	DDamage           []int
+	DExplPerc         []int
+	DSleepingPerc     []int
+	DKilledPerc       []int
 	Burns             int
 	Digs              int
 	Rest              int
-	DRests            [MaxDepth + 1]int
+	DRests            []int
 	Turns             int
 	TWounded          int
 	TMWounded         int
 	TMonsLOS          int
 	NSpotted          int
 	NUSpotted         int
-	DSpotted          [MaxDepth + 1]int
-	DUSpotted         [MaxDepth + 1]int
-	DUSpottedPerc     [MaxDepth + 1]int
+	DSpotted          []int
+	DUSpotted         []int
+	DUSpottedPerc     []int
 	Achievements      map[achievement]int
+	DAchievements     [][]achievement
 	AtNotablePos      map[position]bool
 	HarmonicMagUse    int
 	OricMagUse        int
@@ -51,6 +54,22 @@ type stats struct {
 	TimesPushed       int
 	TimesBlinked      int
 	TimesBlocked      int
+	Alarms            int
+	Potions           int
+}
+
+// GrowDepths makes room in the per-depth statistics for levels down to the
+// given depth, as the endless descent has no last level.
+func (st *stats) GrowDepths(depth int) {
+	for _, ds := range []*[]int{&st.DMagaraUses, &st.DUsedStones, &st.DDamage, &st.DExplPerc,
+		&st.DSleepingPerc, &st.DKilledPerc, &st.DRests, &st.DSpotted, &st.DUSpotted, &st.DUSpottedPerc} {
+		for len(*ds) <= depth {
+			*ds = append(*ds, 0)
+		}
+	}
+	for len(st.DAchievements) <= depth {
+		st.DAchievements = append(st.DAchievements, nil)
+	}
 }
 
 func (g *game) TurnStats() {
@@ -79,17 +98,8 @@ func (g *game) LevelStats() {
 			exp++
 		}
 	}
-	g.Stats.DExplPerc[g.Depth] = exp * 100 / free
-	if g.Stats.DExplPerc[g.Depth] > 93 {
-		AchNoviceExplorer.Get(g)
-	}
-	if g.Depth >= 5 && g.Stats.DExplPerc[g.Depth] > 93 && g.Stats.DExplPerc[g.Depth-1] > 93 && g.Stats.DExplPerc[g.Depth-2] > 93 {
-		AchInitiateExplorer.Get(g)
-	}
-	if g.Depth >= 8 && g.Stats.DExplPerc[g.Depth] > 93 && g.Stats.DExplPerc[g.Depth-1] > 93 && g.Stats.DExplPerc[g.Depth-2] > 93 &&
-		g.Stats.DExplPerc[g.Depth-3] > 93 && g.Stats.DExplPerc[g.Depth-4] > 93 {
-		AchMasterExplorer.Get(g)
-	}
+	depth := g.Depth
+	g.Stats.DExplPerc[depth] = exp * 100 / free
 	//g.Stats.DBurns[g.Depth] = g.Stats.CurBurns // XXX to avoid little dump info leak
 	nmons := len(g.Monsters)
 	kmons := 0
@@ -103,9 +113,9 @@ func (g *game) LevelStats() {
 			smons++
 		}
 	}
-	g.Stats.DSleepingPerc[g.Depth] = smons * 100 / nmons
-	g.Stats.DKilledPerc[g.Depth] = kmons * 100 / nmons
-	g.Stats.DUSpottedPerc[g.Depth] = g.Stats.DUSpotted[g.Depth] * 100 / nmons
+	g.Stats.DSleepingPerc[depth] = smons * 100 / nmons
+	g.Stats.DKilledPerc[depth] = kmons * 100 / nmons
+	g.Stats.DUSpottedPerc[depth] = g.Stats.DUSpotted[depth] * 100 / nmons
 }
 
 type achievement string
@@ -160,6 +170,9 @@ const (
 func (ach achievement) Get(g *game) {
 	if g.Stats.Achievements[ach] == 0 {
 		g.Stats.Achievements[ach] = g.Turn
+		if g.Depth > 0 && g.Depth < len(g.Stats.DAchievements) {
+			g.Stats.DAchievements[g.Depth] = append(g.Stats.DAchievements[g.Depth], ach)
+		}
 		g.PrintfStyled("Achievement: %s.", logSpecial, ach)
 		g.StoryPrintf("Achievement: %s", ach)
 	}
@@ -183,28 +196,14 @@ func (pos position) Reach(g *game) {
 	switch c.T {
 	case TreeCell:
 		g.Stats.ClimbedTree++
-		if g.Stats.ClimbedTree == 12 {
-			AchTree.Get(g)
-		}
 	case TableCell:
 		g.Stats.TableHides++
-		if g.Stats.TableHides == 12 {
-			AchTable.Get(g)
-		}
 	case HoledWallCell:
 		g.Stats.HoledWallsCrawled++
-		if g.Stats.HoledWallsCrawled == 12 {
-			AchHole.Get(g)
-		}
 	case DoorCell:
 		g.Stats.DoorsOpened++
-		if g.Stats.DoorsOpened == 100 {
-			AchDoors.Get(g)
-		}
 	case BarrelCell:
 		g.Stats.BarrelHides++
-		if g.Stats.BarrelHides == 20 {
-			AchBarrels.Get(g)
-		}
 	}
+	g.CheckAchievements(CounterHook)
 }
@@ -20,6 +20,7 @@ const (
 	StatusDisguised
 	StatusDelay
 	StatusDispersal
+	StatusAlarm
 )
 
 func (st status) Flag() bool {
@@ -33,7 +34,7 @@ func (st status) Flag() bool {
 
 func (st status) Clean() bool {
 	switch st {
-	case StatusDelay:
+	case StatusDelay, StatusAlarm:
 		return true
 	default:
 		return false
@@ -42,7 +43,7 @@ func (st status) Clean() bool {
 
 func (st status) Info() bool {
 	switch st {
-	case StatusFlames, StatusHidden, StatusUnhidden, StatusLight, StatusDelay:
+	case StatusFlames, StatusHidden, StatusUnhidden, StatusLight, StatusDelay, StatusAlarm:
 		return true
 	}
 	return false
@@ -59,7 +60,7 @@ func (st status) Good() bool {
 
 func (st status) Bad() bool {
 	switch st {
-	case StatusConfusion, StatusNausea, StatusUnhidden, StatusIlluminated:
+	case StatusConfusion, StatusNausea, StatusUnhidden, StatusIlluminated, StatusAlarm:
 		return true
 	default:
 		return false
@@ -102,6 +103,8 @@ func (st status) String() string {
 		return "Delay"
 	case StatusDispersal:
 		return "Dispersal"
+	case StatusAlarm:
+		return "Alarm"
 	default:
 		// should not happen
 		return "unknown"
@@ -144,6 +147,8 @@ func (st status) Short() string {
 		return "De"
 	case StatusDispersal:
 		return "Dp"
+	case StatusAlarm:
+		return "Al"
 	default:
 		// should not happen
 		return "?"
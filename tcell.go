@@ -9,6 +9,7 @@ import (
 )
 
 type gameui struct {
+	uiContext
 	g *game
 	tcell.Screen
 	cursor position
@@ -42,16 +43,17 @@ func (ui *gameui) Close() {
 	ui.Screen.Fini()
 }
 
-var SmallScreen = false
-
 func (ui *gameui) Flush() {
+	if ui.agent != nil {
+		return
+	}
 	ui.DrawLogFrame()
 	for _, cdraw := range ui.g.DrawLog[len(ui.g.DrawLog)-1].Draws {
 		cell := cdraw.Cell
 		st := tcell.StyleDefault
 		fg := cell.Fg
 		bg := cell.Bg
-		if Only8Colors {
+		if ui.Only8Colors {
 			fg = Map16ColorTo8Color(fg)
 			bg = Map16ColorTo8Color(bg)
 		}
@@ -61,41 +63,47 @@ func (ui *gameui) Flush() {
 	//ui.g.Printf("%d %d %d", ui.g.DrawFrame, ui.g.DrawFrameStart, len(ui.g.DrawLog))
 	ui.Screen.Show()
 	w, h := ui.Screen.Size()
-	if w <= UIWidth-8 || h <= UIHeight-2 {
-		SmallScreen = true
+	if w <= ui.UIWidth-8 || h <= ui.UIHeight-2 {
+		ui.SmallScreen = true
 	} else {
-		SmallScreen = false
+		ui.SmallScreen = false
 	}
 }
 
 func (ui *gameui) ApplyToggleLayout() {
-	GameConfig.Small = !GameConfig.Small
-	if GameConfig.Small {
+	ui.g.config.Small = !ui.g.config.Small
+	if ui.g.config.Small {
 		ui.Clear()
 		ui.Flush()
-		UIHeight = 24
-		UIWidth = 80
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	} else {
-		UIHeight = 26
-		if CenteredCamera {
-			UIWidth = 80
+		ui.UIHeight = 26
+		if ui.CenteredCamera {
+			ui.UIWidth = 80
 		} else {
-			UIWidth = 100
+			ui.UIWidth = 100
 		}
 	}
-	ui.g.DrawBuffer = make([]UICell, UIWidth*UIHeight)
+	ui.g.DrawBuffer = make([]UICell, ui.UIWidth*ui.UIHeight)
 	ui.Clear()
 }
 
 func (ui *gameui) Small() bool {
-	return GameConfig.Small || SmallScreen
+	return ui.g.config.Small || ui.SmallScreen
 }
 
 func (ui *gameui) Interrupt() {
+	if ui.agent != nil {
+		return
+	}
 	ui.Screen.PostEvent(tcell.NewEventInterrupt(nil))
 }
 
 func (ui *gameui) PollEvent() (in uiInput) {
+	if ui.agent != nil {
+		return agentInput
+	}
 	switch tev := ui.Screen.PollEvent().(type) {
 	case *tcell.EventKey:
 		switch tev.Key() {
@@ -124,6 +132,9 @@ func (ui *gameui) PollEvent() (in uiInput) {
 			in.key = "m"
 		case tcell.KeyEnter:
 			in.key = "."
+			in.enter = true
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			in.key = "\x7f"
 		}
 		if tev.Rune() != 0 && in.key == "" {
 			in.key = string(tev.Rune())
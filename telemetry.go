@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// telemetryLine is a line of a telemetry file: the state of the player when
+// a domain event happened, and the details of the event, if any.
+type telemetryLine struct {
+	Turn       int
+	Depth      int
+	Pos        position
+	HP         int
+	MP         int
+	Event      string
+	At         *position `json:",omitempty"`
+	Monster    string    `json:",omitempty"`
+	Action     string    `json:",omitempty"`
+	Noise      int       `json:",omitempty"`
+	MagaraKind string    `json:",omitempty"`
+	Status     string    `json:",omitempty"`
+	Duration   int       `json:",omitempty"`
+	Unaware    bool      `json:",omitempty"`
+	Result     string    `json:",omitempty"`
+	KilledBy   string    `json:",omitempty"`
+}
+
+// telemetry is an observer that appends domain events as JSON lines to a
+// file. Lines are buffered and written once the turn is over, so that at
+// most the current turn is lost if the program is interrupted.
+type telemetry struct {
+	file *os.File
+	w    *bufio.Writer
+	turn int
+	err  error
+}
+
+// NewGameID returns an identifier for a new game, based on the current time.
+func NewGameID() string {
+	return time.Now().Format("20060102-150405.000000000")
+}
+
+// StartTelemetry makes the game write its domain events to a file in the
+// telemetry directory of the data directory, one file per game: a loaded
+// game appends to the file of its previous sessions.
+func (g *game) StartTelemetry() error {
+	dataDir, err := g.DataDir()
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(dataDir, "telemetry")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	if g.ID == "" {
+		// new game, not initialized yet
+		g.ID = NewGameID()
+	}
+	f, err := os.OpenFile(filepath.Join(dir, "game-"+g.ID+".jsonl"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	g.telemetry = &telemetry{file: f, w: bufio.NewWriter(f), turn: g.Turn}
+	g.Subscribe(g.telemetry)
+	return nil
+}
+
+// StopTelemetry writes the remaining telemetry lines and closes the file.
+func (g *game) StopTelemetry() error {
+	t := g.telemetry
+	if t == nil {
+		return nil
+	}
+	g.telemetry = nil
+	for i, o := range g.observers {
+		if o == observer(t) {
+			g.observers = append(g.observers[:i], g.observers[i+1:]...)
+			break
+		}
+	}
+	t.flush()
+	err := t.file.Sync()
+	if cerr := t.file.Close(); err == nil {
+		err = cerr
+	}
+	if t.err != nil {
+		return t.err
+	}
+	return err
+}
+
+func (t *telemetry) flush() {
+	if t.err == nil {
+		t.err = t.w.Flush()
+	}
+}
+
+func (t *telemetry) Observe(g *game, ev gameEvent) {
+	if t.err != nil {
+		return
+	}
+	if g.Turn != t.turn {
+		t.flush()
+		t.turn = g.Turn
+	}
+	line := telemetryLine{Turn: g.Turn, Depth: g.Depth, Event: ev.Name()}
+	if g.Player != nil {
+		line.Pos = g.Player.Pos
+		line.HP = g.Player.HP
+		line.MP = g.Player.MP
+	}
+	switch ev := ev.(type) {
+	case playerSpotted:
+		line.At = &ev.Mons.Pos
+		line.Monster = ev.Mons.Kind.String()
+		line.Unaware = ev.Unaware
+	case noiseMade:
+		line.At = &ev.Pos
+		line.Noise = ev.Noise
+	case magaraEvoked:
+		line.MagaraKind = magara{Kind: ev.Magara.Kind}.String()
+	case monsterKilled:
+		line.At = &ev.Mons.Pos
+		line.Monster = ev.Mons.Kind.String()
+	case statusGained:
+		line.Status = ev.Status.String()
+		line.Duration = ev.Duration
+	case statusEnded:
+		line.Status = ev.Status.String()
+	case monsterActed:
+		line.At = &ev.Mons.Pos
+		line.Monster = ev.Mons.Kind.String()
+		line.Action = ev.Action.String()
+	case gameEnded:
+		if ev.Won {
+			line.Result = "win"
+		} else {
+			line.Result = "death"
+			line.KilledBy = g.Stats.KilledBy
+		}
+	}
+	data, err := json.Marshal(line)
+	if err == nil {
+		data = append(data, '\n')
+		_, err = t.w.Write(data)
+	}
+	if err != nil {
+		t.err = err
+		g.PrintfStyled("Error writing telemetry: %v", logError, err)
+		return
+	}
+	if _, ok := ev.(gameEnded); ok {
+		t.flush()
+	}
+}
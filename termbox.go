@@ -7,6 +7,7 @@ import (
 )
 
 type gameui struct {
+	uiContext
 	g      *game
 	cursor position
 	// below unused for this backend
@@ -31,15 +32,16 @@ func (ui *gameui) Close() {
 	termbox.Close()
 }
 
-var SmallScreen = false
-
 func (ui *gameui) Flush() {
+	if ui.agent != nil {
+		return
+	}
 	ui.DrawLogFrame()
 	for _, cdraw := range ui.g.DrawLog[len(ui.g.DrawLog)-1].Draws {
 		cell := cdraw.Cell
 		fg := cell.Fg
 		bg := cell.Bg
-		if Only8Colors {
+		if ui.Only8Colors {
 			fg = Map16ColorTo8Color(fg)
 			bg = Map16ColorTo8Color(bg)
 		}
@@ -47,41 +49,47 @@ func (ui *gameui) Flush() {
 	}
 	termbox.Flush()
 	w, h := termbox.Size()
-	if w <= UIWidth-8 || h <= UIHeight-2 {
-		SmallScreen = true
+	if w <= ui.UIWidth-8 || h <= ui.UIHeight-2 {
+		ui.SmallScreen = true
 	} else {
-		SmallScreen = false
+		ui.SmallScreen = false
 	}
 }
 
 func (ui *gameui) ApplyToggleLayout() {
-	GameConfig.Small = !GameConfig.Small
-	if GameConfig.Small {
+	ui.g.config.Small = !ui.g.config.Small
+	if ui.g.config.Small {
 		ui.Clear()
 		ui.Flush()
-		UIHeight = 24
-		UIWidth = 80
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	} else {
-		UIHeight = 26
-		if CenteredCamera {
-			UIWidth = 80
+		ui.UIHeight = 26
+		if ui.CenteredCamera {
+			ui.UIWidth = 80
 		} else {
-			UIWidth = 100
+			ui.UIWidth = 100
 		}
 	}
-	ui.g.DrawBuffer = make([]UICell, UIWidth*UIHeight)
+	ui.g.DrawBuffer = make([]UICell, ui.UIWidth*ui.UIHeight)
 	ui.Clear()
 }
 
 func (ui *gameui) Small() bool {
-	return GameConfig.Small || SmallScreen
+	return ui.g.config.Small || ui.SmallScreen
 }
 
 func (ui *gameui) Interrupt() {
+	if ui.agent != nil {
+		return
+	}
 	termbox.Interrupt()
 }
 
 func (ui *gameui) PollEvent() (in uiInput) {
+	if ui.agent != nil {
+		return agentInput
+	}
 	switch tev := termbox.PollEvent(); tev.Type {
 	case termbox.EventKey:
 		if tev.Ch == 0 {
@@ -104,6 +112,9 @@ func (ui *gameui) PollEvent() (in uiInput) {
 				in.key = " "
 			case termbox.KeyEnter:
 				in.key = "."
+				in.enter = true
+			case termbox.KeyBackspace, termbox.KeyBackspace2:
+				in.key = "\x7f"
 			}
 		}
 		if tev.Ch != 0 && in.key == "" {
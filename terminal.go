@@ -6,8 +6,8 @@ func (ui *gameui) ApplyToggleTiles() {
 }
 
 func (ui *gameui) PostConfig() {
-	if GameConfig.Small {
-		UIHeight = 24
-		UIWidth = 80
+	if ui.g.config.Small {
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	}
 }
@@ -13,7 +13,7 @@ import (
 )
 
 func (ui *gameui) ApplyToggleTiles() {
-	GameConfig.Tiles = !GameConfig.Tiles
+	ui.g.config.Tiles = !ui.g.config.Tiles
 	for c, _ := range ui.cache {
 		if c.InMap {
 			delete(ui.cache, c)
@@ -157,6 +157,7 @@ var MapNames = map[rune]string{
 	'^':  "rubble",
 	'○':  "nolight",
 	'‗':  "queenrock",
+	'Ω':  "gong",
 }
 
 var LetterNames = map[rune]string{
@@ -230,20 +231,24 @@ var LetterNames = map[rune]string{
 	'^':  "rubble",
 	'○':  "nolight",
 	'‗':  "queenrock",
+	'Ω':  "gong",
 }
 
 func (ui *gameui) Interrupt() {
+	if ui.agent != nil {
+		return
+	}
 	Interrupt <- true
 }
 
 func (ui *gameui) Small() bool {
-	return GameConfig.Small
+	return ui.g.config.Small
 }
 
-func getImage(cell UICell) *image.RGBA {
+func (ui *gameui) getImage(cell UICell) *image.RGBA {
 	var pngImg []byte
 	hastile := false
-	if cell.InMap && GameConfig.Tiles {
+	if cell.InMap && ui.g.config.Tiles {
 		pngImg = TileImgs["map-notile"]
 		if im, ok := TileImgs["map-"+string(cell.R)]; ok {
 			pngImg = im
@@ -288,8 +293,8 @@ func getImage(cell UICell) *image.RGBA {
 }
 
 func (ui *gameui) PostConfig() {
-	if GameConfig.Small {
-		GameConfig.Small = false
+	if ui.g.config.Small {
+		ui.g.config.Small = false
 		ui.ApplyToggleLayoutWithClear(false)
 	}
 }
@@ -17,6 +17,7 @@ import (
 )
 
 type gameui struct {
+	uiContext
 	g         *game
 	ir        *gothic.Interpreter
 	cursor    position
@@ -31,7 +32,7 @@ type gameui struct {
 }
 
 func (ui *gameui) Init() error {
-	ui.canvas = image.NewRGBA(image.Rect(0, 0, UIWidth*16, UIHeight*24))
+	ui.canvas = image.NewRGBA(image.Rect(0, 0, ui.UIWidth*16, ui.UIHeight*24))
 	ui.ir = gothic.NewInterpreter(fmt.Sprintf(`
 wm title . "Harmonist Tk"
 wm resizable . 0 0
@@ -44,7 +45,7 @@ focus $can
 image create photo gamescreen -width $width -height $height -palette 256/256/256
 image create photo bufscreen -width $width -height $height -palette 256/256/256
 $can create image 0 0 -anchor nw -image gamescreen
-`, UIWidth, UIHeight))
+`, ui.UIWidth, ui.UIHeight))
 	ui.InitElements()
 	ui.ir.RegisterCommand("GetKey", func(c, keysym string) {
 		var s string
@@ -63,7 +64,7 @@ $can create image 0 0 -anchor nw -image gamescreen
 		}
 	})
 	ui.ir.RegisterCommand("MouseMotion", func(x, y int) {
-		if CenteredCamera {
+		if ui.CenteredCamera {
 			return
 		}
 		nx := (x - 1) / ui.width
@@ -101,10 +102,10 @@ wm protocol . WM_DELETE_WINDOW OnClosing
 `)
 	ui.menuHover = -1
 
-	SolarizedPalette()
+	ui.SolarizedPalette()
 	ui.HideCursor()
 	settingsActions = append(settingsActions, toggleTiles)
-	GameConfig.Tiles = true
+	ui.g.config.Tiles = true
 	return nil
 }
 
@@ -127,19 +128,22 @@ func (ui *gameui) Close() {
 }
 
 func (ui *gameui) Flush() {
+	if ui.agent != nil {
+		return
+	}
 	ui.DrawLogFrame()
 	// very ugly optimisation
-	xdgnmin := UIWidth - 1
+	xdgnmin := ui.UIWidth - 1
 	xdgnmax := 0
-	ydgnmin := UIHeight - 1
+	ydgnmin := ui.UIHeight - 1
 	ydgnmax := 0
-	xlogmin := UIWidth - 1
+	xlogmin := ui.UIWidth - 1
 	xlogmax := 0
-	ylogmin := UIHeight - 1
+	ylogmin := ui.UIHeight - 1
 	ylogmax := 0
-	xbarmin := UIWidth - 1
+	xbarmin := ui.UIWidth - 1
 	xbarmax := 0
-	ybarmin := UIHeight - 1
+	ybarmin := ui.UIHeight - 1
 	ybarmax := 0
 	for _, cdraw := range ui.g.DrawLog[len(ui.g.DrawLog)-1].Draws {
 		cell := cdraw.Cell
@@ -209,26 +213,26 @@ func (ui *gameui) ApplyToggleLayout() {
 }
 
 func (ui *gameui) ApplyToggleLayoutWithClear(clear bool) {
-	GameConfig.Small = !GameConfig.Small
-	if GameConfig.Small {
+	ui.g.config.Small = !ui.g.config.Small
+	if ui.g.config.Small {
 		ui.ir.Eval("wm geometry . =1280x576")
 		if clear {
 			ui.Clear()
 			ui.Flush()
 		}
-		UIHeight = 24
-		UIWidth = 80
+		ui.UIHeight = 24
+		ui.UIWidth = 80
 	} else {
 		ui.ir.Eval("wm geometry . =${width}x$height")
-		UIHeight = 26
-		if CenteredCamera {
-			UIWidth = 80
+		ui.UIHeight = 26
+		if ui.CenteredCamera {
+			ui.UIWidth = 80
 		} else {
-			UIWidth = 100
+			ui.UIWidth = 100
 		}
 	}
 	ui.cache = make(map[UICell]*image.RGBA)
-	ui.g.DrawBuffer = make([]UICell, UIWidth*UIHeight)
+	ui.g.DrawBuffer = make([]UICell, ui.UIWidth*ui.UIHeight)
 	if clear {
 		ui.Clear()
 	}
@@ -239,13 +243,16 @@ func (ui *gameui) Draw(cell UICell, x, y int) {
 	if im, ok := ui.cache[cell]; ok {
 		img = im
 	} else {
-		img = getImage(cell)
+		img = ui.getImage(cell)
 		ui.cache[cell] = img
 	}
 	draw.Draw(ui.canvas, image.Rect(x*ui.width, ui.height*y, (x+1)*ui.width, (y+1)*ui.height), img, image.Point{0, 0}, draw.Over)
 }
 
 func (ui *gameui) PollEvent() (in uiInput) {
+	if ui.agent != nil {
+		return agentInput
+	}
 	select {
 	case in = <-InCh:
 	case in.interrupt = <-Interrupt:
@@ -253,6 +260,7 @@ func (ui *gameui) PollEvent() (in uiInput) {
 	switch in.key {
 	case "KP_Enter", "Return", "\r", "\n":
 		in.key = "."
+		in.enter = true
 	case "Left", "KP_Left":
 		in.key = "4"
 	case "Right", "KP_Right":
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 	"unicode"
@@ -24,6 +25,35 @@ type uiInput struct {
 	mouseY    int
 	button    int
 	interrupt bool
+	enter     bool // the key was Enter, reported as '.' for commands
+}
+
+// uiContext holds the display settings and state of an interface. It is
+// embedded in the gameui of every backend, so that independent games can run
+// in the same process, each with its own interface.
+type uiContext struct {
+	palette
+	UIWidth           int
+	UIHeight          int
+	CenteredCamera    bool
+	DisableAnimations bool
+	Telemetry         bool
+	SmallScreen       bool
+	CustomKeys        bool
+	MenuCols          [][2]int
+	spectators        *spectators
+	agent             *agent
+}
+
+// NewGameUI returns an interface for a game with default settings. The
+// backend still has to be initialized with Init.
+func NewGameUI(g *game) *gameui {
+	ui := &gameui{g: g}
+	ui.UIWidth = 100
+	ui.UIHeight = 26
+	ui.Xterm256Palette()
+	ui.InitMenuCols()
+	return ui
 }
 
 func (ui *gameui) HideCursor() {
@@ -94,34 +124,73 @@ type startAction int
 const (
 	StartPlay startAction = iota
 	StartWatchReplay
+	StartDaily
 )
 
+func (sa startAction) String() string {
+	switch sa {
+	case StartPlay:
+		return "(P)lay"
+	case StartWatchReplay:
+		return "(W)atch replay"
+	case StartDaily:
+		return "(D)aily challenge"
+	default:
+		return ""
+	}
+}
+
+// Key returns the key that selects the action in the start menu.
+func (sa startAction) Key() string {
+	switch sa {
+	case StartWatchReplay:
+		return "w"
+	case StartDaily:
+		return "d"
+	default:
+		return "p"
+	}
+}
+
+// StartMenuActions returns the entries of the start menu.
+func StartMenuActions() []startAction {
+	if runtime.GOARCH == "wasm" {
+		return []startAction{StartPlay, StartDaily, StartWatchReplay}
+	}
+	return []startAction{StartPlay, StartDaily}
+}
+
+// StartMenu waits for the player to choose an entry of the start menu drawn
+// at line l. Outside the browser, any other key starts playing.
 func (ui *gameui) StartMenu(l int) startAction {
+	actions := StartMenuActions()
 	for {
 		in := ui.PollEvent()
-		switch in.key {
-		case "P", "p":
-			ui.ColorLine(l, ColorYellow)
-			ui.Flush()
-			Sleep(AnimDurShort)
+		if in.interrupt {
 			return StartPlay
-		case "W", "w":
-			ui.ColorLine(l+1, ColorYellow)
-			ui.Flush()
-			Sleep(AnimDurShort)
-			return StartWatchReplay
+		}
+		for i, a := range actions {
+			if strings.ToLower(in.key) == a.Key() {
+				ui.ColorLine(l+i, ui.ColorYellow)
+				ui.Flush()
+				Sleep(AnimDurShort)
+				return a
+			}
 		}
 		if in.key != "" && !in.mouse {
+			if runtime.GOARCH != "wasm" {
+				return StartPlay
+			}
 			continue
 		}
 		y := in.mouseY
 		switch in.button {
 		case -1:
 			oih := ui.itemHover
-			if y < l || y >= l+2 {
+			if y < l || y >= l+len(actions) {
 				ui.itemHover = -1
 				if oih != -1 {
-					ui.ColorLine(oih, ColorFg)
+					ui.ColorLine(oih, ui.ColorFg)
 					ui.Flush()
 				}
 				break
@@ -130,23 +199,18 @@ func (ui *gameui) StartMenu(l int) startAction {
 				break
 			}
 			ui.itemHover = y
-			ui.ColorLine(y, ColorYellow)
+			ui.ColorLine(y, ui.ColorYellow)
 			if oih != -1 {
-				ui.ColorLine(oih, ColorFg)
+				ui.ColorLine(oih, ui.ColorFg)
 			}
 			ui.Flush()
 		case 0:
-			if y < l || y >= l+2 {
+			if y < l || y >= l+len(actions) {
 				ui.itemHover = -1
 				break
 			}
 			ui.itemHover = -1
-			switch y - l {
-			case 0:
-				return StartPlay
-			case 1:
-				return StartWatchReplay
-			}
+			return actions[y-l]
 		}
 	}
 }
@@ -159,7 +223,7 @@ func (ui *gameui) PlayerTurnEvent() (again, quit bool, err error) {
 	case "":
 		if in.mouse {
 			var mpos position
-			if CenteredCamera {
+			if ui.CenteredCamera {
 				mpos = ui.CameraTargetPosition(in.mouseX, in.mouseY, true)
 			} else {
 				mpos = position{in.mouseX, in.mouseY}
@@ -225,11 +289,21 @@ func (ui *gameui) PlayerTurnEvent() (again, quit bool, err error) {
 	return again, quit, err
 }
 
-func (ui *gameui) Scroll(n int) (m int, quit bool) {
+type logViewAction int
+
+const (
+	ScrollLog logViewAction = iota
+	SearchLog
+	FilterLog
+	JumpToTurnLog
+	QuitLog
+)
+
+func (ui *gameui) Scroll(n int) (m int, action logViewAction) {
 	in := ui.PollEvent()
 	switch in.key {
 	case "Escape", "\x1b", " ", "x", "X":
-		quit = true
+		action = QuitLog
 	case "u", "9", "b":
 		n -= 12
 	case "d", "3", "f":
@@ -238,6 +312,12 @@ func (ui *gameui) Scroll(n int) (m int, quit bool) {
 		n++
 	case "k", "8":
 		n--
+	case "/":
+		action = SearchLog
+	case "F":
+		action = FilterLog
+	case "t":
+		action = JumpToTurnLog
 	case "":
 		if in.mouse {
 			switch in.button {
@@ -245,30 +325,30 @@ func (ui *gameui) Scroll(n int) (m int, quit bool) {
 				y := in.mouseY
 				x := in.mouseX
 				if x >= DungeonWidth {
-					quit = true
+					action = QuitLog
 					break
 				}
-				if y > UIHeight {
+				if y > ui.UIHeight {
 					break
 				}
 				n += y - (ui.MapHeight()+3)/2
 			}
 		}
 	}
-	return n, quit
+	return n, action
 }
 
 func (ui *gameui) GetIndex(x, y int) int {
-	return y*UIWidth + x
+	return y*ui.UIWidth + x
 }
 
 func (ui *gameui) GetPos(i int) (int, int) {
-	return i - (i/UIWidth)*UIWidth, i / UIWidth
+	return i - (i/ui.UIWidth)*ui.UIWidth, i / ui.UIWidth
 }
 
 func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 	if ui.itemHover >= 1 && ui.itemHover <= l {
-		ui.ColorLine(ui.itemHover, ColorYellow)
+		ui.ColorLine(ui.itemHover, ui.ColorYellow)
 		ui.Flush()
 	} else {
 		ui.itemHover = -1
@@ -283,7 +363,7 @@ func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 			return -1, true, nil
 		case 97 <= r && int(r) < 97+l:
 			if ui.itemHover >= 1 && ui.itemHover <= l {
-				ui.ColorLine(ui.itemHover, ColorFg)
+				ui.ColorLine(ui.itemHover, ui.ColorFg)
 			}
 			ui.itemHover = int(r-97) + 1
 			return int(r - 97), false, nil
@@ -294,9 +374,9 @@ func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 				ui.itemHover = 1
 			}
 			if oih > 0 && oih <= l {
-				ui.ColorLine(oih, ColorFg)
+				ui.ColorLine(oih, ui.ColorFg)
 			}
-			ui.ColorLine(ui.itemHover, ColorYellow)
+			ui.ColorLine(ui.itemHover, ui.ColorYellow)
 			ui.Flush()
 		case in.key == "8":
 			oih := ui.itemHover
@@ -305,13 +385,13 @@ func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 				ui.itemHover = l
 			}
 			if oih > 0 && oih <= l {
-				ui.ColorLine(oih, ColorFg)
+				ui.ColorLine(oih, ui.ColorFg)
 			}
-			ui.ColorLine(ui.itemHover, ColorYellow)
+			ui.ColorLine(ui.itemHover, ui.ColorYellow)
 			ui.Flush()
 		case in.key == "." && ui.itemHover >= 1 && ui.itemHover <= l:
 			if ui.itemHover >= 1 && ui.itemHover <= l {
-				ui.ColorLine(ui.itemHover, ColorFg)
+				ui.ColorLine(ui.itemHover, ui.ColorFg)
 			}
 			return ui.itemHover - 1, false, nil
 		case in.key == "" && in.mouse:
@@ -323,7 +403,7 @@ func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 				if y <= 0 || y > l || x >= DungeonWidth {
 					ui.itemHover = -1
 					if oih > 0 {
-						ui.ColorLine(oih, ColorFg)
+						ui.ColorLine(oih, ui.ColorFg)
 						ui.Flush()
 					}
 					break
@@ -332,9 +412,9 @@ func (ui *gameui) Select(l int) (index int, alternate bool, err error) {
 					break
 				}
 				ui.itemHover = y
-				ui.ColorLine(y, ColorYellow)
+				ui.ColorLine(y, ui.ColorYellow)
 				if oih > 0 {
-					ui.ColorLine(oih, ColorFg)
+					ui.ColorLine(oih, ui.ColorFg)
 				}
 				ui.Flush()
 			case 0:
@@ -433,7 +513,7 @@ func (ui *gameui) TargetModeEvent(targ Targeter, data *examineData) (again, quit
 				break
 			}
 			var mpos position
-			if CenteredCamera {
+			if ui.CenteredCamera {
 				mpos = ui.CameraTargetPosition(in.mouseX, in.mouseY, true)
 			} else {
 				mpos = position{in.mouseX, in.mouseY}
@@ -459,7 +539,7 @@ func (ui *gameui) TargetModeEvent(targ Targeter, data *examineData) (again, quit
 				err = errors.New(DoNothing)
 			} else {
 				var mpos position
-				if CenteredCamera {
+				if ui.CenteredCamera {
 					mpos = ui.CameraTargetPosition(in.mouseX, in.mouseY, true)
 				} else {
 					mpos = position{in.mouseX, in.mouseY}
@@ -501,6 +581,35 @@ func (ui *gameui) ReadRuneKey() rune {
 	}
 }
 
+// ReadString reads a line of text, showing it with the given prompt on line
+// lnum. It returns false if the input was cancelled.
+func (ui *gameui) ReadString(prompt string, lnum int) (string, bool) {
+	runes := []rune{}
+	for {
+		ui.DrawStyledTextLine(fmt.Sprintf(" %s%s_ ", prompt, string(runes)), lnum, FooterLine)
+		ui.Flush()
+		in := ui.PollEvent()
+		if in.enter {
+			return string(runes), true
+		}
+		switch in.key {
+		case "\x1b", "Escape":
+			return "", false
+		case "Enter", "\r", "\n":
+			return string(runes), true
+		case "\x7f", "\b":
+			if len(runes) > 0 {
+				runes = runes[:len(runes)-1]
+			}
+			continue
+		}
+		r := ui.ReadKey(in.key)
+		if unicode.IsPrint(r) {
+			runes = append(runes, r)
+		}
+	}
+}
+
 func (ui *gameui) ReadKey(s string) (r rune) {
 	bs := strings.NewReader(s)
 	r, _, _ = bs.ReadRune()
@@ -575,8 +684,17 @@ const (
 	ActionConfigure
 	ActionMenu
 	ActionNextStairs
+	ActionNote
+	ActionNextNote
 	ActionMenuCommandHelp
 	ActionMenuTargetingHelp
+	ActionTravel
+	ActionGoToBarrel
+	ActionGoToStone
+	ActionGoToMagara
+	ActionGoToPotion
+	ActionConducts
+	ActionPractice
 )
 
 var ConfigurableKeyActions = [...]action{
@@ -594,9 +712,16 @@ var ConfigurableKeyActions = [...]action{
 	ActionInventory,
 	ActionExamine,
 	ActionGoToStairs,
+	ActionTravel,
+	ActionGoToBarrel,
+	ActionGoToStone,
+	ActionGoToMagara,
+	ActionGoToPotion,
 	ActionExplore,
 	ActionLogs,
 	ActionDump,
+	ActionConducts,
+	ActionPractice,
 	ActionSave,
 	ActionQuit,
 	ActionMenu,
@@ -604,11 +729,11 @@ var ConfigurableKeyActions = [...]action{
 	ActionNextMonster,
 	ActionNextObject,
 	ActionNextStairs,
+	ActionNextNote,
 	ActionDescription,
 	ActionTarget,
-	ActionExclude}
-
-var CustomKeys bool
+	ActionExclude,
+	ActionNote}
 
 func FixedRuneKey(r rune) bool {
 	switch r {
@@ -626,6 +751,11 @@ func (k action) NormalModeAction() bool {
 		ActionWaitTurn,
 		ActionDescend,
 		ActionGoToStairs,
+		ActionTravel,
+		ActionGoToBarrel,
+		ActionGoToStone,
+		ActionGoToMagara,
+		ActionGoToPotion,
 		ActionExplore,
 		ActionExamine,
 		ActionEvoke,
@@ -633,6 +763,8 @@ func (k action) NormalModeAction() bool {
 		ActionInventory,
 		ActionLogs,
 		ActionDump,
+		ActionConducts,
+		ActionPractice,
 		ActionHelp,
 		ActionMenu,
 		ActionMenuCommandHelp,
@@ -672,6 +804,16 @@ func (k action) NormalModeDescription() (text string) {
 		text = "Descend stairs"
 	case ActionGoToStairs:
 		text = "Go to nearest stairs"
+	case ActionTravel:
+		text = "Travel to landmark"
+	case ActionGoToBarrel:
+		text = "Go to nearest barrel"
+	case ActionGoToStone:
+		text = "Go to nearest unused stone"
+	case ActionGoToMagara:
+		text = "Go to nearest magara"
+	case ActionGoToPotion:
+		text = "Go to nearest potion"
 	case ActionExplore:
 		text = "Autoexplore"
 	case ActionExamine:
@@ -686,6 +828,10 @@ func (k action) NormalModeDescription() (text string) {
 		text = "View previous messages"
 	case ActionDump:
 		text = "Write game statistics to file"
+	case ActionConducts:
+		text = "View conducts"
+	case ActionPractice:
+		text = "Practice mode (checkpoints and rewind)"
 	case ActionSave:
 		text = "Save and Quit"
 	case ActionQuit:
@@ -736,6 +882,10 @@ func (k action) TargetingModeDescription() (text string) {
 		text = "Target next object"
 	case ActionNextStairs:
 		text = "Target next stairs"
+	case ActionNextNote:
+		text = "Target next map note"
+	case ActionNote:
+		text = "Write or remove map note"
 	case ActionDescription:
 		text = "View target description"
 	case ActionTarget:
@@ -759,9 +909,11 @@ func (k action) TargetingModeAction() bool {
 		ActionNextMonster,
 		ActionNextObject,
 		ActionNextStairs,
+		ActionNextNote,
 		ActionDescription,
 		ActionTarget,
 		ActionExclude,
+		ActionNote,
 		ActionEscape:
 		return true
 	default:
@@ -769,10 +921,8 @@ func (k action) TargetingModeAction() bool {
 	}
 }
 
-var GameConfig config
-
-func ApplyDefaultKeyBindings() {
-	GameConfig.RuneNormalModeKeys = map[rune]action{
+func (ui *gameui) ApplyDefaultKeyBindings() {
+	ui.g.config.RuneNormalModeKeys = map[rune]action{
 		'h': ActionW,
 		'j': ActionS,
 		'k': ActionN,
@@ -792,6 +942,7 @@ func ApplyDefaultKeyBindings() {
 		'.': ActionWaitTurn,
 		'5': ActionWaitTurn,
 		'G': ActionGoToStairs,
+		'T': ActionTravel,
 		'o': ActionExplore,
 		'x': ActionExamine,
 		'v': ActionEvoke,
@@ -801,6 +952,8 @@ func ApplyDefaultKeyBindings() {
 		'm': ActionLogs,
 		'M': ActionMenu,
 		'#': ActionDump,
+		'C': ActionConducts,
+		'P': ActionPractice,
 		'?': ActionHelp,
 		'S': ActionSave,
 		'Q': ActionQuit,
@@ -809,7 +962,7 @@ func ApplyDefaultKeyBindings() {
 		'>': ActionWizardDescend,
 		'=': ActionConfigure,
 	}
-	GameConfig.RuneTargetModeKeys = map[rune]action{
+	ui.g.config.RuneTargetModeKeys = map[rune]action{
 		'h':    ActionW,
 		'j':    ActionS,
 		'k':    ActionN,
@@ -841,13 +994,15 @@ func ApplyDefaultKeyBindings() {
 		't':    ActionTarget,
 		'g':    ActionTarget,
 		'e':    ActionExclude,
+		'n':    ActionNote,
+		'N':    ActionNextNote,
 		' ':    ActionEscape,
 		'\x1b': ActionEscape,
 		'x':    ActionEscape,
 		'X':    ActionEscape,
 		'?':    ActionHelp,
 	}
-	CustomKeys = false
+	ui.CustomKeys = false
 }
 
 type runeKeyAction struct {
@@ -858,7 +1013,7 @@ type runeKeyAction struct {
 func (ui *gameui) HandleKeyAction(rka runeKeyAction) (again bool, quit bool, err error) {
 	if rka.r != 0 {
 		var ok bool
-		rka.k, ok = GameConfig.RuneNormalModeKeys[rka.r]
+		rka.k, ok = ui.g.config.RuneNormalModeKeys[rka.r]
 		if !ok {
 			switch rka.r {
 			case 's':
@@ -900,7 +1055,12 @@ func (ui *gameui) HandleKey(rka runeKeyAction) (again bool, quit bool, err error
 	case ActionWaitTurn:
 		g.WaitTurn()
 	case ActionGoToStairs:
-		stairs := g.StairsSlice()
+		stairs := []position{}
+		for _, pos := range g.StairsSlice() {
+			if g.Objects.Stairs[pos] != UpStair {
+				stairs = append(stairs, pos)
+			}
+		}
 		sortedStairs := g.SortedNearestTo(stairs, g.Player.Pos)
 		if len(sortedStairs) > 0 {
 			stair := sortedStairs[0]
@@ -919,6 +1079,17 @@ func (ui *gameui) HandleKey(rka runeKeyAction) (again bool, quit bool, err error
 		} else {
 			err = errors.New("You cannot go to any stairs.")
 		}
+	case ActionTravel:
+		err = ui.TravelToLandmark()
+		err = ui.CleanError(err)
+	case ActionGoToBarrel:
+		err = g.GoToNearestLandmark(LandmarkBarrel)
+	case ActionGoToStone:
+		err = g.GoToNearestLandmark(LandmarkStone)
+	case ActionGoToMagara:
+		err = g.GoToNearestLandmark(LandmarkMagara)
+	case ActionGoToPotion:
+		err = g.GoToNearestLandmark(LandmarkPotion)
 	case ActionInteract:
 		c := g.Dungeon.Cell(g.Player.Pos)
 		switch c.T {
@@ -926,6 +1097,11 @@ func (ui *gameui) HandleKey(rka runeKeyAction) (again bool, quit bool, err error
 			if g.Dungeon.Cell(g.Player.Pos).T == StairCell && g.Objects.Stairs[g.Player.Pos] != BlockedStair {
 				ui.MenuSelectedAnimation(MenuInteract, true)
 				strt := g.Objects.Stairs[g.Player.Pos]
+				if strt == UpStair {
+					err = g.Ascend()
+					ui.DrawDungeonView(NormalMode)
+					break
+				}
 				err = ui.OptionalDescendConfirmation(strt)
 				if err != nil {
 					break
@@ -995,6 +1171,13 @@ func (ui *gameui) HandleKey(rka runeKeyAction) (again bool, quit bool, err error
 	case ActionLogs:
 		ui.DrawPreviousLogs()
 		again = true
+	case ActionConducts:
+		ui.DrawDescription(g.ConductsReport(), "Conducts")
+		again = true
+	case ActionPractice:
+		err = ui.Practice()
+		err = ui.CleanError(err)
+		again = g.rewind == 0
 	case ActionSave:
 		g.Ev.Renew(g, 0)
 		errsave := g.Save()
@@ -1028,7 +1211,7 @@ func (ui *gameui) HandleKey(rka runeKeyAction) (again bool, quit bool, err error
 		if g.Wizard && g.Depth == WinDepth {
 			g.RescuedShaedra()
 		}
-		if g.Wizard && g.Depth < MaxDepth {
+		if g.Wizard && (g.Depth < MaxDepth || g.Params.Plan.Endless) {
 			g.StoryPrint("Descended wizardly")
 			if g.Descend(DescendNormal) {
 				ui.Win()
@@ -1128,6 +1311,35 @@ func (ui *gameui) NextStair(data *examineData) {
 	}
 }
 
+func (ui *gameui) NextNote(data *examineData) {
+	g := ui.g
+	if data.sortedNotes == nil {
+		data.sortedNotes = g.SortedNotes(g.Player.Pos)
+	}
+	if len(data.sortedNotes) == 0 {
+		g.Print("You have not written any notes on this level.")
+		return
+	}
+	if data.noteIndex >= len(data.sortedNotes) {
+		data.noteIndex = 0
+	}
+	data.npos = data.sortedNotes[data.noteIndex]
+	data.noteIndex++
+}
+
+func (ui *gameui) WriteNote(pos position) {
+	g := ui.g
+	prompt := "note: "
+	if _, ok := g.Notes[pos]; ok {
+		prompt = "note (empty to remove): "
+	}
+	text, ok := ui.ReadString(prompt, ui.MapHeight()+2)
+	if !ok {
+		return
+	}
+	g.SetNote(pos, strings.TrimSpace(text))
+}
+
 func (ui *gameui) NextObject(pos position, data *examineData) {
 	g := ui.g
 	nobject := data.nobject
@@ -1213,7 +1425,7 @@ func (ui *gameui) CursorKeyAction(targ Targeter, rka runeKeyAction, data *examin
 	again = true
 	if rka.r != 0 {
 		var ok bool
-		rka.k, ok = GameConfig.RuneTargetModeKeys[rka.r]
+		rka.k, ok = g.config.RuneTargetModeKeys[rka.r]
 		if !ok {
 			err = fmt.Errorf("Invalid targeting mode key '%c'. Type ? for help.", rka.r)
 			return again, quit, notarg, err
@@ -1243,6 +1455,13 @@ func (ui *gameui) CursorKeyAction(targ Targeter, rka runeKeyAction, data *examin
 		if g.Dungeon.Cell(g.Player.Pos).T == StairCell && g.Objects.Stairs[g.Player.Pos] != BlockedStair {
 			ui.MenuSelectedAnimation(MenuInteract, true)
 			strt := g.Objects.Stairs[g.Player.Pos]
+			if strt == UpStair {
+				again = false
+				g.Targeting = InvalidPos
+				notarg = true
+				err = g.Ascend()
+				break
+			}
 			err = ui.OptionalDescendConfirmation(strt)
 			if err != nil {
 				break
@@ -1264,6 +1483,10 @@ func (ui *gameui) CursorKeyAction(targ Targeter, rka runeKeyAction, data *examin
 		ui.NextMonster(rka.r, pos, data)
 	case ActionNextObject:
 		ui.NextObject(pos, data)
+	case ActionNextNote:
+		ui.NextNote(data)
+	case ActionNote:
+		ui.WriteNote(pos)
 	case ActionHelp, ActionMenuTargetingHelp:
 		ui.HideCursor()
 		ui.ExamineHelp()
@@ -1337,6 +1560,8 @@ type examineData struct {
 	nobject      int
 	sortedStairs []position
 	stairIndex   int
+	sortedNotes  []position
+	noteIndex    int
 }
 
 var InvalidPos = position{-1, -1}
@@ -1396,7 +1621,7 @@ loop:
 			}
 			ui.DrawStyledTextLine(st, ui.MapHeight()+2, FooterLine)
 		}
-		ui.SetCell(DungeonWidth, ui.MapHeight(), '┤', ColorFg, ColorBg)
+		ui.SetCell(DungeonWidth, ui.MapHeight(), '┤', ui.ColorFg, ui.ColorBg)
 		ui.Flush()
 		data.npos = pos
 		var notarg bool
@@ -1462,21 +1687,20 @@ func (m menu) Key(g *game) (key action) {
 	return key
 }
 
-var MenuCols = [][2]int{
-	//MenuExplore:  {0, 0},
-	MenuOther:     {0, 0},
-	MenuInventory: {0, 0},
-	MenuEvoke:     {0, 0},
-	MenuInteract:  {0, 0}}
-
-func init() {
-	for i := range MenuCols {
+func (ui *gameui) InitMenuCols() {
+	ui.MenuCols = [][2]int{
+		//MenuExplore:  {0, 0},
+		MenuOther:     {0, 0},
+		MenuInventory: {0, 0},
+		MenuEvoke:     {0, 0},
+		MenuInteract:  {0, 0}}
+	for i := range ui.MenuCols {
 		runes := utf8.RuneCountInString(menu(i).String())
 		if i == 0 {
-			MenuCols[0] = [2]int{25, 25 + runes}
+			ui.MenuCols[0] = [2]int{25, 25 + runes}
 			continue
 		}
-		MenuCols[i] = [2]int{MenuCols[i-1][1] + 2, MenuCols[i-1][1] + 2 + runes}
+		ui.MenuCols[i] = [2]int{ui.MenuCols[i-1][1] + 2, ui.MenuCols[i-1][1] + 2 + runes}
 	}
 }
 
@@ -1485,7 +1709,7 @@ func (ui *gameui) WhichButton(col int) (menu, bool) {
 	if ui.Small() {
 		return MenuOther, false
 	}
-	end := len(MenuCols) - 1
+	end := len(ui.MenuCols) - 1
 	switch g.Dungeon.Cell(g.Player.Pos).T {
 	case StairCell, BarrelCell, ScrollCell, MagaraCell, StoneCell, LightCell:
 		end++
@@ -1494,7 +1718,7 @@ func (ui *gameui) WhichButton(col int) (menu, bool) {
 			end++
 		}
 	}
-	for i, cols := range MenuCols[0:end] {
+	for i, cols := range ui.MenuCols[0:end] {
 		if cols[0] >= 0 && col >= cols[0] && col < cols[1] {
 			return menu(i), true
 		}
@@ -1509,8 +1733,11 @@ func (ui *gameui) UpdateInteractButton() string {
 	switch g.Dungeon.Cell(g.Player.Pos).T {
 	case StairCell:
 		interactMenu = "[descend]"
-		if g.Objects.Stairs[g.Player.Pos] == WinStair {
+		switch g.Objects.Stairs[g.Player.Pos] {
+		case WinStair:
 			interactMenu = "[escape]"
+		case UpStair:
+			interactMenu = "[ascend]"
 		}
 		show = true
 	case BarrelCell:
@@ -1540,9 +1767,9 @@ func (ui *gameui) UpdateInteractButton() string {
 	if !show {
 		return ""
 	}
-	i := len(MenuCols) - 1
+	i := len(ui.MenuCols) - 1
 	runes := utf8.RuneCountInString(interactMenu)
-	MenuCols[i][1] = MenuCols[i][0] + runes
+	ui.MenuCols[i][1] = ui.MenuCols[i][0] + runes
 	return interactMenu
 }
 
@@ -1551,6 +1778,7 @@ type wizardAction int
 const (
 	WizardInfoAction wizardAction = iota
 	WizardToggleMode
+	WizardToggleScent
 )
 
 func (a wizardAction) String() (text string) {
@@ -1559,6 +1787,8 @@ func (a wizardAction) String() (text string) {
 		text = "Info"
 	case WizardToggleMode:
 		text = "toggle normal/map/all wizard mode"
+	case WizardToggleScent:
+		text = "toggle scent map"
 	}
 	return text
 }
@@ -1566,6 +1796,7 @@ func (a wizardAction) String() (text string) {
 var WizardActions = []wizardAction{
 	WizardInfoAction,
 	WizardToggleMode,
+	WizardToggleScent,
 }
 
 func (ui *gameui) HandleWizardAction() error {
@@ -1588,15 +1819,15 @@ func (ui *gameui) HandleWizardAction() error {
 		}
 		g.StoryPrint("Toggle wizard mode.")
 		ui.DrawDungeonView(NoFlushMode)
+	case WizardToggleScent:
+		g.WizardScent = !g.WizardScent
+		ui.DrawDungeonView(NoFlushMode)
 	}
 	return nil
 }
 
 func (ui *gameui) Death() {
 	g := ui.g
-	if len(g.Stats.Achievements) == 0 {
-		NoAchievement.Get(g)
-	}
 	g.Print("You die... [(x) to continue]")
 	ui.DrawDungeonView(NormalMode)
 	ui.WaitForContinue(-1)
@@ -1613,6 +1844,8 @@ func (ui *gameui) Win() {
 	}
 	if g.Wizard {
 		g.Print("You escape by the magic portal! **WIZARD** [(x) to continue]")
+	} else if g.Practice {
+		g.Print("You escape by the magic portal! **PRACTICE** [(x) to continue]")
 	} else {
 		g.Print("You escape by the magic portal! [(x) to continue]")
 	}
@@ -1665,6 +1898,9 @@ func (ui *gameui) Wizard() bool {
 
 func (ui *gameui) HandlePlayerTurn() bool {
 	g := ui.g
+	if ui.agent != nil {
+		return ui.AgentTurn()
+	}
 getKey:
 	for {
 		var err error
@@ -1686,45 +1922,41 @@ getKey:
 }
 
 func (ui *gameui) ExploreStep() bool {
-	next := make(chan bool)
-	var stop bool
 	go func() {
 		Sleep(10)
 		ui.Interrupt()
 	}()
-	go func() {
-		err := ui.PressAnyKey()
-		interrupted := err != nil
-		next <- !interrupted
-	}()
-	stop = <-next
+	// wait for the key on the game goroutine, so that backends may stop it
+	// from PollEvent
+	err := ui.PressAnyKey()
+	stop := err == nil
 	ui.DrawDungeonView(NormalMode)
 	return stop
 }
 
 func (ui *gameui) Clear() {
-	for i := 0; i < UIHeight*UIWidth; i++ {
+	for i := 0; i < ui.UIHeight*ui.UIWidth; i++ {
 		x, y := ui.GetPos(i)
-		ui.SetCell(x, y, ' ', ColorFg, ColorBg)
+		ui.SetCell(x, y, ' ', ui.ColorFg, ui.ColorBg)
 	}
 }
 
 func (ui *gameui) DrawBufferInit() {
 	if len(ui.g.DrawBuffer) == 0 {
-		ui.g.DrawBuffer = make([]UICell, UIHeight*UIWidth)
-	} else if len(ui.g.DrawBuffer) != UIHeight*UIWidth {
-		ui.g.DrawBuffer = make([]UICell, UIHeight*UIWidth)
+		ui.g.DrawBuffer = make([]UICell, ui.UIHeight*ui.UIWidth)
+	} else if len(ui.g.DrawBuffer) != ui.UIHeight*ui.UIWidth {
+		ui.g.DrawBuffer = make([]UICell, ui.UIHeight*ui.UIWidth)
 	}
 }
 
-func ApplyConfig() {
-	if GameConfig.RuneNormalModeKeys == nil || GameConfig.RuneTargetModeKeys == nil {
-		ApplyDefaultKeyBindings()
+func (ui *gameui) ApplyConfig() {
+	if ui.g.config.RuneNormalModeKeys == nil || ui.g.config.RuneTargetModeKeys == nil {
+		ui.ApplyDefaultKeyBindings()
 	}
-	if GameConfig.DarkLOS {
-		ApplyDarkLOS()
+	if ui.g.config.DarkLOS {
+		ui.ApplyDarkLOS()
 	} else {
-		ApplyLightLOS()
+		ui.ApplyLightLOS()
 	}
 }
 
@@ -13,16 +13,22 @@ func Abs(x int) int {
 	return x
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
-
-func RandInt(n int) int {
+// RandInt returns a random number in [0,n) from the random generator of the
+// game, or 0 if n <= 0.
+func (g *game) RandInt(n int) int {
 	if n <= 0 {
 		return 0
 	}
-	x := rand.Intn(n)
-	return x
+	if g.rng == nil {
+		g.Seed(time.Now().UnixNano())
+	}
+	return g.rng.Intn(n)
+}
+
+// Seed sets the random generator of the game. Each game has its own, so that
+// games running in the same process do not change each other.
+func (g *game) Seed(seed int64) {
+	g.rng = rand.New(rand.NewSource(seed))
 }
 
 func Min(x, y int) int {